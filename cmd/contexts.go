@@ -0,0 +1,311 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/portworx/pxc/pkg/config"
+	"github.com/portworx/pxc/pkg/kubernetes"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+
+	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// contextResult is the output of running the command in a single context
+type contextResult struct {
+	name string
+	// table is set if the command printed a table
+	table *util.Table
+	// items is set if the command printed json or yaml
+	items interface{}
+	// text is set for any other output
+	text string
+	err  error
+}
+
+// contextItems is the json or yaml output of a single context
+type contextItems struct {
+	Context string      `json:"context" yaml:"context"`
+	Items   interface{} `json:"items,omitempty" yaml:"items,omitempty"`
+	Error   string      `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// setupContextFanOut replaces the command to run it in each of the contexts
+// selected by --pxc.contexts or --pxc.all-contexts
+func setupContextFanOut(cmd *cobra.Command) error {
+	flags := config.CM().GetFlags()
+	if !flags.IsFanOut() {
+		return nil
+	}
+
+	// Commands which manage the configuration only work on the local setup
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Parent() == rootCmd && util.ListContains([]string{"config", "login", "logout"}, c.Name()) {
+			return fmt.Errorf("Running %s commands in multiple contexts is not supported", c.Name())
+		}
+	}
+
+	run := cmd.RunE
+	if run == nil && cmd.Run != nil {
+		r := cmd.Run
+		run = func(c *cobra.Command, args []string) error {
+			r(c, args)
+			return nil
+		}
+	}
+	if run == nil {
+		return nil
+	}
+
+	cmd.Run = nil
+	cmd.RunE = func(c *cobra.Command, args []string) error {
+		return contextFanOutExec(c, args, run)
+	}
+	return nil
+}
+
+// contextFanOutExec runs the command in each context selected. The command
+// runs in process, in up to --pxc.max-concurrency contexts at the same time.
+func contextFanOutExec(
+	cmd *cobra.Command,
+	args []string,
+	run func(*cobra.Command, []string) error,
+) error {
+	flags := config.CM().GetFlags()
+
+	names := make([]string, 0, len(config.CM().Config.Contexts))
+	for name := range config.CM().Config.Contexts {
+		if flags.MatchesContext(name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("No contexts match %s", strings.Join(flags.Contexts, ","))
+	}
+	sort.Strings(names)
+
+	// Collect the output of the formatters instead of printing it
+	output, _ := cmd.Flags().GetString("output")
+	results := make([]*contextResult, len(names))
+	util.ForEach(len(names), flags.MaxConcurrency, true, func(i int) error {
+		results[i] = runInContext(cmd, args, run, names[i], output)
+		return nil
+	})
+
+	// Show output
+	switch output {
+	case util.FORMAT_JSON, util.FORMAT_YAML:
+		printMergedObjects(results, output)
+	default:
+		noHeaders, _ := cmd.Flags().GetBool("no-headers")
+		if err := printMergedTables(results, output, noHeaders); err != nil {
+			return err
+		}
+	}
+
+	// Report errors
+	failed := 0
+	for _, r := range results {
+		if r.err == nil {
+			continue
+		}
+		failed++
+		util.Eprintf("Context %s: %v\n", r.name, r.err)
+	}
+	if failed != 0 {
+		return fmt.Errorf("Command failed in %d of %d contexts", failed, len(results))
+	}
+
+	return nil
+}
+
+// runInContext runs the command with the configuration of the named
+// context. The configuration and the output of the formatters are carried by
+// the context of the command, so that several contexts can run at the same
+// time.
+func runInContext(
+	cmd *cobra.Command,
+	args []string,
+	run func(*cobra.Command, []string) error,
+	name, output string,
+) *contextResult {
+	r := &contextResult{
+		name: name,
+	}
+
+	cm, err := config.NewConfigManagerForContext(name)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	ctx := config.WithConfigManager(cmd.Context(), cm)
+
+	// The formatters get their data when they are printed, so the output
+	// is collected while the context is in use
+	ctx = util.WithFormatOutputHandler(ctx, func(in util.FormatOutput) error {
+		return r.collect(in, output)
+	})
+	defer kubernetes.StopTunnel(ctx)
+
+	// The command is shared by the contexts, so each one runs a copy with
+	// its own context
+	c := *cmd
+	c.SetContext(ctx)
+
+	logrus.Infof("Running in context %s", name)
+	r.err = run(&c, args)
+	return r
+}
+
+// collect saves the output of the formatter in the result
+func (r *contextResult) collect(in util.FormatOutput, output string) error {
+	switch output {
+	case util.FORMAT_JSON:
+		str, err := in.JsonFormat()
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal([]byte(str), &r.items)
+	case util.FORMAT_YAML:
+		str, err := in.YamlFormat()
+		if err != nil {
+			return err
+		}
+		return yaml.Unmarshal([]byte(str), &r.items)
+	case "", util.FORMAT_WIDE, util.FORMAT_CSV, util.FORMAT_MARKDOWN:
+		if tf, ok := in.(util.TableFormatOutput); ok {
+			table, err := tf.TableFormat()
+			r.table = table
+			return err
+		}
+	}
+
+	str, err := util.GetFormattedOutput(in)
+	if err != nil {
+		return err
+	}
+	r.text = str
+	return nil
+}
+
+// printMergedObjects prints a list with the output of each context
+func printMergedObjects(results []*contextResult, output string) {
+	merged := make([]*contextItems, 0, len(results))
+	for _, r := range results {
+		items := &contextItems{
+			Context: r.name,
+			Items:   r.items,
+		}
+		if r.err != nil {
+			items.Error = r.err.Error()
+		}
+		merged = append(merged, items)
+	}
+
+	if output == util.FORMAT_JSON {
+		util.PrintJson(merged)
+		util.Printf("\n")
+	} else {
+		util.PrintYaml(merged)
+	}
+}
+
+// printMergedTables prints the tables of all contexts as a single table with
+// a CONTEXT column. If any output is not a table, the output of each context
+// is printed in its own section.
+func printMergedTables(results []*contextResult, output string, noHeaders bool) error {
+	var headers []string
+	for _, r := range results {
+		if r.table != nil && headers == nil {
+			headers = r.table.Headers
+		}
+		if len(r.text) != 0 ||
+			(r.table != nil && !sameHeaders(headers, r.table.Headers)) {
+			printSections(results)
+			return nil
+		}
+	}
+	if headers == nil {
+		return nil
+	}
+
+	// Match the style of the existing headers
+	contextHeader := "Context"
+	if headers[0] == strings.ToUpper(headers[0]) {
+		contextHeader = "CONTEXT"
+	}
+
+	merged := &util.Table{
+		Headers: append([]string{contextHeader}, headers...),
+	}
+	for _, r := range results {
+		if r.table == nil {
+			continue
+		}
+		for _, row := range r.table.Rows {
+			merged.Rows = append(merged.Rows, append([]string{r.name}, row...))
+		}
+	}
+
+	switch output {
+	case util.FORMAT_CSV:
+		str, err := merged.Csv(noHeaders)
+		if err != nil {
+			return err
+		}
+		util.Printf("%s\n", str)
+	case util.FORMAT_MARKDOWN:
+		util.Printf("%s\n", merged.Markdown())
+	default:
+		str := merged.Tabbed()
+		if noHeaders {
+			str = strings.SplitN(str, "\n", 3)[2]
+		}
+		util.Printf("%s", str)
+	}
+	return nil
+}
+
+func printSections(results []*contextResult) {
+	for _, r := range results {
+		if r.err != nil {
+			continue
+		}
+		str := r.text
+		if r.table != nil {
+			str = r.table.Tabbed()
+		}
+		util.Printf("=== Context: %s ===\n%s\n", r.name, strings.TrimRight(str, "\n"))
+	}
+}
+
+func sameHeaders(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type testContextFormatter struct {
+	util.BaseFormatOutput
+	names []string
+}
+
+func (f *testContextFormatter) JsonFormat() (string, error) {
+	return util.ToJson(f.names)
+}
+
+func (f *testContextFormatter) YamlFormat() (string, error) {
+	return util.ToYaml(f.names)
+}
+
+func (f *testContextFormatter) TableFormat() (*util.Table, error) {
+	t := &util.Table{}
+	t.AddHeader("NAME")
+	for _, name := range f.names {
+		t.AddLine(name)
+	}
+	return t, nil
+}
+
+func collectContextResults(t *testing.T, output string) []*contextResult {
+	results := []*contextResult{
+		{name: "dev"},
+		{name: "prod", err: fmt.Errorf("unavailable")},
+		{name: "test"},
+	}
+	formatters := []*testContextFormatter{
+		{names: []string{"vol1", "vol2"}},
+		nil,
+		{names: []string{"vol3"}},
+	}
+	for i, r := range results {
+		if formatters[i] != nil {
+			assert.NoError(t, r.collect(formatters[i], output))
+		}
+	}
+	return results
+}
+
+func captureStdout(f func()) string {
+	var b bytes.Buffer
+	original := util.Stdout
+	util.Stdout = &b
+	defer func() { util.Stdout = original }()
+	f()
+	return b.String()
+}
+
+func TestPrintMergedTables(t *testing.T) {
+	results := collectContextResults(t, "")
+	out := captureStdout(func() {
+		assert.NoError(t, printMergedTables(results, "", false))
+	})
+	assert.Equal(t, ""+
+		"CONTEXT  NAME\n"+
+		"-------  ----\n"+
+		"dev      vol1\n"+
+		"dev      vol2\n"+
+		"test     vol3\n", out)
+
+	results = collectContextResults(t, util.FORMAT_CSV)
+	out = captureStdout(func() {
+		assert.NoError(t, printMergedTables(results, util.FORMAT_CSV, true))
+	})
+	assert.Equal(t, "dev,vol1\ndev,vol2\ntest,vol3\n", out)
+}
+
+func TestPrintMergedObjects(t *testing.T) {
+	results := collectContextResults(t, util.FORMAT_JSON)
+	out := captureStdout(func() {
+		printMergedObjects(results, util.FORMAT_JSON)
+	})
+	assert.JSONEq(t, `[
+		{"context": "dev", "items": ["vol1", "vol2"]},
+		{"context": "prod", "error": "unavailable"},
+		{"context": "test", "items": ["vol3"]}
+	]`, out)
+
+	results = collectContextResults(t, util.FORMAT_YAML)
+	out = captureStdout(func() {
+		printMergedObjects(results, util.FORMAT_YAML)
+	})
+	assert.Equal(t, ""+
+		"- context: dev\n"+
+		"  items:\n"+
+		"  - vol1\n"+
+		"  - vol2\n"+
+		"- context: prod\n"+
+		"  error: unavailable\n"+
+		"- context: test\n"+
+		"  items:\n"+
+		"  - vol3\n", out)
+}
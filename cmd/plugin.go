@@ -19,6 +19,7 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -137,7 +138,7 @@ func HandlePluginCommand(pluginHandler PluginHandler, cmdArgs []string) error {
 	token := authInfo.Token
 	if len(authInfo.KubernetesAuthInfo.SecretName) != 0 &&
 		len(authInfo.KubernetesAuthInfo.SecretNamespace) != 0 {
-		token, err = portworx.PxGetTokenFromSecret(authInfo.KubernetesAuthInfo.SecretName, authInfo.KubernetesAuthInfo.SecretNamespace)
+		token, err = portworx.PxGetTokenFromSecret(context.Background(), authInfo.KubernetesAuthInfo.SecretName, authInfo.KubernetesAuthInfo.SecretNamespace)
 		if err != nil {
 			return err
 		}
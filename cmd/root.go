@@ -16,6 +16,7 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"os"
 
 	"github.com/portworx/pxc/pkg/commander"
@@ -192,7 +193,8 @@ func rootPersistentPreRunE(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return nil
+	// Run the command in multiple contexts if requested
+	return setupContextFanOut(cmd)
 }
 
 func rootPersistentPostRunE(cmd *cobra.Command, args []string) error {
@@ -207,5 +209,5 @@ func rootPersistentPostRunE(cmd *cobra.Command, args []string) error {
 }
 
 func cleanup() {
-	kubernetes.StopTunnel()
+	kubernetes.StopTunnel(context.Background())
 }
@@ -71,7 +71,7 @@ func newClusterShowFlags() *clusterShowFlags {
 func clusterShowExec(c *cobra.Command, args []string) error {
 
 	// This will automatically setup the the connection to portworx
-	ctx, conn, err := portworx.PxConnectDefault()
+	ctx, conn, err := portworx.PxConnectDefault(c.Context())
 	if err != nil {
 		return err
 	}
@@ -102,7 +102,7 @@ func clusterShowExec(c *cobra.Command, args []string) error {
 	}
 	formatter.SetFormat(clusterShowOptions.output)
 
-	return util.PrintFormatted(formatter)
+	return util.PrintFormatted(c.Context(), formatter)
 }
 
 type showFormatter struct {
@@ -51,7 +51,7 @@ func UuidAddCommand(c *cobra.Command) {
 func clusterUuidExec(c *cobra.Command, args []string) error {
 
 	// This will automatically setup the the connection to portworx
-	ctx, conn, err := portworx.PxConnectDefault()
+	ctx, conn, err := portworx.PxConnectDefault(c.Context())
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,284 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package apply
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/cmd"
+	"github.com/portworx/pxc/pkg/commander"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type applyOpts struct {
+	filename string
+	dryRun   bool
+	prune    bool
+	selector string
+	yes      bool
+}
+
+// volumeAction is the change needed to get a volume to the declared state
+type volumeAction struct {
+	name    string
+	id      string
+	create  *api.SdkVolumeCreateRequest
+	updates []*api.SdkVolumeUpdateRequest
+	changes []util.FieldChange
+	prune   bool
+}
+
+var (
+	aOpts    *applyOpts
+	applyCmd *cobra.Command
+)
+
+var _ = commander.RegisterCommandVar(func() {
+	aOpts = &applyOpts{}
+	applyCmd = &cobra.Command{
+		Use:   "apply",
+		Short: "Create or update volumes from a manifest file",
+		Long: `Create or update volumes from a YAML or JSON manifest file.
+
+Volumes in the manifest which do not exist are created. Volumes which
+exist are updated when the fields declared in the manifest differ from
+the volume. Fields not declared in the manifest are left unchanged.
+Labels declared in the manifest are added or updated, and labels of the
+volume which are not in the manifest are kept.`,
+		Example: `
+  # Create or update the volumes in volumes.yaml
+  pxc apply -f volumes.yaml
+
+  # Show the changes which would be done without applying them
+  pxc apply -f volumes.yaml --dry-run
+
+  # Also delete volumes with the label app=db which are not in the manifest.
+  # The volumes to delete are shown and confirmation is asked.
+  pxc apply -f volumes.yaml --prune -l app=db
+
+  # Example manifest:
+  volumes:
+  - name: db-data
+    size: 10
+    replicas: 2
+    labels:
+      app: db
+    io-profile: db
+    shared: false
+    snapshot-schedule:
+      daily: ["02:00,7"]
+    collaborators: ["user1:w"]
+    groups: ["dba:a"]`,
+		RunE: applyExec,
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	cmd.RootAddCommand(applyCmd)
+
+	applyCmd.Flags().StringVarP(&aOpts.filename, "filename", "f", "", "Manifest file with the volumes to apply. Use - to read from stdin")
+	applyCmd.Flags().BoolVar(&aOpts.dryRun, "dry-run", false, "Show the changes without applying them")
+	applyCmd.Flags().BoolVar(&aOpts.prune, "prune", false, "Delete volumes which match the selector and are not in the manifest")
+	applyCmd.Flags().BoolVar(&aOpts.yes, "yes", false, "Do not ask for confirmation before deleting the volumes pruned")
+	applyCmd.Flags().StringVarP(&aOpts.selector, "selector", "l", "", "Selector (label query) comma-separated name=value pairs of the volumes to prune")
+	applyCmd.MarkFlagRequired("filename")
+})
+
+func ApplyAddCommand(c *cobra.Command) {
+	applyCmd.AddCommand(c)
+}
+
+func applyExec(c *cobra.Command, args []string) error {
+	if aOpts.prune && len(aOpts.selector) == 0 {
+		return fmt.Errorf("Must supply a selector with --prune")
+	}
+
+	manifest, err := portworx.ReadVolumeManifest(aOpts.filename)
+	if err != nil {
+		return err
+	}
+
+	ctx, conn, err := portworx.PxConnectDefault(c.Context())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	actions, err := planActions(ctx, conn, manifest)
+	if err != nil {
+		return err
+	}
+
+	if aOpts.dryRun {
+		printDiff(actions)
+		return nil
+	}
+
+	if ok, err := confirmPrune(actions); !ok {
+		return err
+	}
+
+	return applyActions(ctx, conn, actions)
+}
+
+// confirmPrune shows the volumes which would be deleted by --prune and asks
+// for confirmation, unless --yes was provided. Returns false if nothing must
+// be applied.
+func confirmPrune(actions []*volumeAction) (bool, error) {
+	pruned := make([]*volumeAction, 0)
+	for _, action := range actions {
+		if action.prune {
+			pruned = append(pruned, action)
+		}
+	}
+	if len(pruned) == 0 || aOpts.yes {
+		return true, nil
+	}
+
+	printDiff(pruned)
+	return util.ConfirmVolumes(
+		fmt.Sprintf("Delete %d volumes which are not in the manifest?", len(pruned)), len(pruned), false)
+}
+
+// planActions compares the manifest with the volumes in the cluster
+func planActions(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	manifest *portworx.VolumeManifest,
+) ([]*volumeAction, error) {
+	volumes := api.NewOpenStorageVolumeClient(conn)
+	actions := make([]*volumeAction, 0, len(manifest.Volumes))
+	declared := make(map[string]bool)
+
+	for _, v := range manifest.Volumes {
+		declared[v.Name] = true
+		action := &volumeAction{name: v.Name}
+
+		resp, err := volumes.Inspect(ctx, &api.SdkVolumeInspectRequest{
+			VolumeId: v.Name,
+		})
+		if status.Code(err) == codes.NotFound {
+			action.create, err = v.CreateRequest()
+			if err != nil {
+				return nil, err
+			}
+		} else if err != nil {
+			return nil, util.PxErrorMessagef(err, "Failed to get volume %s", v.Name)
+		} else {
+			action.id = resp.GetVolume().GetId()
+			action.updates, action.changes, err = v.UpdateRequests(resp.GetVolume())
+			if err != nil {
+				return nil, err
+			}
+		}
+		actions = append(actions, action)
+	}
+
+	if !aOpts.prune {
+		return actions, nil
+	}
+
+	labels, err := util.CommaStringToStringMap(aOpts.selector)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse selector: %v", err)
+	}
+	resp, err := volumes.InspectWithFilters(ctx, &api.SdkVolumeInspectWithFiltersRequest{
+		Labels: labels,
+	})
+	if err != nil {
+		return nil, util.PxErrorMessage(err, "Failed to get volumes")
+	}
+
+	pruned := make([]*volumeAction, 0)
+	for _, vol := range resp.GetVolumes() {
+		// Snapshots are not managed by the manifest
+		if declared[vol.GetName()] || vol.GetVolume().GetReadonly() {
+			continue
+		}
+		pruned = append(pruned, &volumeAction{
+			name:  vol.GetName(),
+			id:    vol.GetVolume().GetId(),
+			prune: true,
+		})
+	}
+	sort.Slice(pruned, func(i, j int) bool {
+		return pruned[i].name < pruned[j].name
+	})
+
+	return append(actions, pruned...), nil
+}
+
+func applyActions(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	actions []*volumeAction,
+) error {
+	volumes := api.NewOpenStorageVolumeClient(conn)
+	for _, action := range actions {
+		switch {
+		case action.create != nil:
+			id, err := portworx.CreateVolume(ctx, conn, action.create)
+			if err != nil {
+				return util.PxErrorMessagef(err, "Failed to create volume %s", action.name)
+			}
+			util.Printf("Volume %s created with id %s\n", action.name, id)
+		case action.prune:
+			_, err := volumes.Delete(ctx, &api.SdkVolumeDeleteRequest{
+				VolumeId: action.id,
+			})
+			if err != nil {
+				return util.PxErrorMessagef(err, "Failed to delete volume %s", action.name)
+			}
+			util.Printf("Volume %s deleted\n", action.name)
+		case len(action.updates) != 0:
+			for _, req := range action.updates {
+				if err := portworx.UpdateVolume(ctx, conn, req); err != nil {
+					return util.PxErrorMessagef(err, "Failed to update volume %s", action.name)
+				}
+			}
+			util.Printf("Volume %s updated\n", action.name)
+		default:
+			util.Printf("Volume %s unchanged\n", action.name)
+		}
+	}
+	return nil
+}
+
+// printDiff shows the changes which would be done by apply
+func printDiff(actions []*volumeAction) {
+	for _, action := range actions {
+		switch {
+		case action.create != nil:
+			util.Printf("%s\n", util.Colorize(util.ColorGreen, "+ volume "+action.name))
+		case action.prune:
+			util.Printf("%s\n", util.Colorize(util.ColorRed, "- volume "+action.name))
+		case len(action.changes) != 0:
+			util.Printf("%s\n", util.Colorize(util.ColorYellow, "~ volume "+action.name))
+			util.PrintChanges("  ", action.changes)
+		default:
+			util.Printf("  volume %s\n", action.name)
+		}
+	}
+}
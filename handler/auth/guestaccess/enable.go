@@ -38,7 +38,7 @@ var _ = commander.RegisterCommandInit(func() {
 })
 
 func enableGuestAccessExec(cmd *cobra.Command, args []string) error {
-	ctx, conn, err := portworx.PxConnectDefault()
+	ctx, conn, err := portworx.PxConnectDefault(cmd.Context())
 	_ = ctx
 	if err != nil {
 		return err
@@ -51,7 +51,7 @@ func enableGuestAccessExec(cmd *cobra.Command, args []string) error {
 	authOps := cliops.NewCliAuthOps(cai)
 
 	// initialize alertOP interface
-	authOps.AuthOps = portworx.NewAuthOps()
+	authOps.AuthOps = portworx.NewAuthOps(cmd.Context())
 
 	err = authOps.AuthOps.UpdateRole(&portworx.RoleGuestEnabled)
 	if err == nil {
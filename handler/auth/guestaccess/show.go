@@ -40,7 +40,7 @@ var _ = commander.RegisterCommandInit(func() {
 })
 
 func showGuestAccessExec(cmd *cobra.Command, args []string) error {
-	ctx, conn, err := portworx.PxConnectDefault()
+	ctx, conn, err := portworx.PxConnectDefault(cmd.Context())
 	_ = ctx
 	if err != nil {
 		return err
@@ -53,11 +53,11 @@ func showGuestAccessExec(cmd *cobra.Command, args []string) error {
 	authOps := cliops.NewCliAuthOps(cai)
 
 	// initialize alertOP interface
-	authOps.AuthOps = portworx.NewAuthOps()
+	authOps.AuthOps = portworx.NewAuthOps(cmd.Context())
 
 	// Create the parser object
 	authgf := NewGuestAccessShowFormatter(authOps)
-	return util.PrintFormatted(authgf)
+	return util.PrintFormatted(cmd.Context(), authgf)
 }
 
 type guestAccessShowFormatter struct {
@@ -16,6 +16,7 @@ limitations under the License.
 package cloudmigration
 
 import (
+	"github.com/golang/protobuf/proto"
 	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
 	"github.com/portworx/pxc/pkg/commander"
 	"github.com/portworx/pxc/pkg/portworx"
@@ -71,28 +72,31 @@ func CreateAddCommand(cmd *cobra.Command) {
 }
 
 func createCloudmigrationExec(cmd *cobra.Command, args []string) error {
-	ctx, conn, err := portworx.PxConnectDefault()
+	ctx, conn, err := portworx.PxConnectDefault(cmd.Context())
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
 
+	// The options are shared when the command runs in several contexts
+	req := proto.Clone(ccmOpts.req).(*api.SdkCloudMigrateStartRequest)
+
 	// Parse input options
 	switch {
 	case ccmOpts.all:
-		ccmOpts.req.Opt = &api.SdkCloudMigrateStartRequest_AllVolumes{
+		req.Opt = &api.SdkCloudMigrateStartRequest_AllVolumes{
 			AllVolumes: &api.SdkCloudMigrateStartRequest_MigrateAllVolumes{},
 		}
 
 	case ccmOpts.groupId != "":
-		ccmOpts.req.Opt = &api.SdkCloudMigrateStartRequest_VolumeGroup{
+		req.Opt = &api.SdkCloudMigrateStartRequest_VolumeGroup{
 			VolumeGroup: &api.SdkCloudMigrateStartRequest_MigrateVolumeGroup{
 				GroupId: ccmOpts.groupId,
 			},
 		}
 
 	case ccmOpts.volumeId != "":
-		ccmOpts.req.Opt = &api.SdkCloudMigrateStartRequest_Volume{
+		req.Opt = &api.SdkCloudMigrateStartRequest_Volume{
 			Volume: &api.SdkCloudMigrateStartRequest_MigrateVolume{
 				VolumeId: ccmOpts.volumeId,
 			},
@@ -104,7 +108,7 @@ func createCloudmigrationExec(cmd *cobra.Command, args []string) error {
 
 	// Send request
 	migration := api.NewOpenStorageMigrateClient(conn)
-	resp, err := migration.Start(ctx, ccmOpts.req)
+	resp, err := migration.Start(ctx, req)
 	if err != nil {
 		return util.PxErrorMessage(err, "Failed to start volume migration")
 	}
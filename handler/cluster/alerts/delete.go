@@ -47,7 +47,7 @@ var _ = commander.RegisterCommandInit(func() {
 })
 
 func deleteAlertsExec(cmd *cobra.Command, args []string) error {
-	ctx, conn, err := portworx.PxConnectDefault()
+	ctx, conn, err := portworx.PxConnectDefault(cmd.Context())
 	_ = ctx
 	if err != nil {
 		return err
@@ -60,7 +60,7 @@ func deleteAlertsExec(cmd *cobra.Command, args []string) error {
 	alertOps := cliops.NewCliAlertOps(cai)
 
 	// initialize alertOP interface
-	alertOps.PxAlertOps = portworx.NewPxAlertOps()
+	alertOps.PxAlertOps = portworx.NewPxAlertOps(cmd.Context())
 
 	err = alertOps.PxAlertOps.DeletePxAlerts(alertOps.CliAlertInputs.AlertType)
 
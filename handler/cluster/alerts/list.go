@@ -17,11 +17,8 @@ limitations under the License.
 package alerts
 
 import (
-	"bytes"
-	"text/tabwriter"
 	"unsafe"
 
-	"github.com/cheynewallace/tabby"
 	"github.com/portworx/pxc/pkg/cliops"
 	"github.com/portworx/pxc/pkg/commander"
 	"github.com/portworx/pxc/pkg/portworx"
@@ -69,12 +66,13 @@ var _ = commander.RegisterCommandInit(func() {
 	listAlertsCmd.Flags().String("resource-id", "", "Resource ID for a specific type")
 	listAlertsCmd.Flags().StringP("start-time", "a", "", "start time span (RFC 3339)")
 	listAlertsCmd.Flags().StringP("end-time", "e", "", "end time span (RFC 3339)")
-	listAlertsCmd.Flags().StringP("output", "o", "", "Output in yaml|json|wide")
+	listAlertsCmd.Flags().StringP("output", "o", "", "Output in yaml|json|wide|csv|markdown|name|custom-columns=<spec>|jsonpath=<template>|go-template=<template>")
+	listAlertsCmd.Flags().Bool("no-headers", false, "Do not print the headers of tables")
 	listAlertsCmd.Flags().StringP("severity", "v", "notify", "Min severity value (Valid Values: [notify warn warning alarm]) (default \"notify\")")
 })
 
 func listAlertsExec(cmd *cobra.Command, args []string) error {
-	ctx, conn, err := portworx.PxConnectDefault()
+	ctx, conn, err := portworx.PxConnectDefault(cmd.Context())
 	_ = ctx
 	if err != nil {
 		return err
@@ -87,11 +85,11 @@ func listAlertsExec(cmd *cobra.Command, args []string) error {
 	alertOps := cliops.NewCliAlertOps(cai)
 
 	// initialize alertOP interface
-	alertOps.PxAlertOps = portworx.NewPxAlertOps()
+	alertOps.PxAlertOps = portworx.NewPxAlertOps(cmd.Context())
 
 	// Create the parser object
 	alertgf := NewAlertGetFormatter(alertOps)
-	return util.PrintFormatted(alertgf)
+	return util.PrintFormatted(cmd.Context(), alertgf)
 }
 
 type alertGetFormatter struct {
@@ -133,32 +131,37 @@ func (p *alertGetFormatter) DefaultFormat() (string, error) {
 	return p.toTabbed()
 }
 
-func (p *alertGetFormatter) toTabbed() (string, error) {
-	var b bytes.Buffer
-	writer := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
-	t := tabby.NewCustom(writer)
-
+// TableFormat returns the table of the alerts
+func (p *alertGetFormatter) TableFormat() (*util.Table, error) {
 	alerts, err := p.PxAlertOps.GetPxAlerts(p.CliAlertInputs)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if unsafe.Sizeof(alerts) == 0 {
 		util.Printf("No alerts found\n")
-		return "", nil
+		return nil, nil
 	}
 
+	t := &util.Table{}
 	// Start the columns
 	t.AddHeader(p.getHeader()...)
 	for _, n := range alerts.AlertResp {
 		l, err := p.getLine(n, alerts.AlertIdToName[n.GetAlertType()])
 		if err != nil {
-			return "", nil
+			return nil, nil
 		}
 		t.AddLine(l...)
 	}
-	t.Print()
-	return b.String(), nil
+	return t, nil
+}
+
+func (p *alertGetFormatter) toTabbed() (string, error) {
+	t, err := p.TableFormat()
+	if err != nil || t == nil {
+		return "", err
+	}
+	return t.Tabbed(), nil
 }
 
 func (p *alertGetFormatter) getHeader() []interface{} {
@@ -26,6 +26,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
 )
 
 var describeClusterCmd *cobra.Command
@@ -45,7 +47,7 @@ var _ = commander.RegisterCommandInit(func() {
 })
 
 func describeClusterExec(c *cobra.Command, args []string) error {
-	ctx, conn, err := portworx.PxConnectDefault()
+	ctx, conn, err := portworx.PxConnectDefault(c.Context())
 	if err != nil {
 		return err
 	}
@@ -64,7 +66,8 @@ func describeClusterExec(c *cobra.Command, args []string) error {
 
 	// Print cluster information
 	cluster := api.NewOpenStorageClusterClient(conn)
-	clusterInfo, err := cluster.InspectCurrent(ctx, &api.SdkClusterInspectCurrentRequest{})
+	var endpoint peer.Peer
+	clusterInfo, err := cluster.InspectCurrent(ctx, &api.SdkClusterInspectCurrentRequest{}, grpc.Peer(&endpoint))
 	if err != nil {
 		return util.PxErrorMessage(err, "Failed to inspect cluster")
 	}
@@ -73,12 +76,19 @@ func describeClusterExec(c *cobra.Command, args []string) error {
 		version.GetSdkVersion().GetVersion(),
 		versionDetails)
 
+	endpointStr := "Unknown"
+	if endpoint.Addr != nil {
+		endpointStr = endpoint.Addr.String()
+	}
+
 	util.Printf("Name: %s\n"+
 		"UUID: %s\n"+
-		"Status: %s\n",
+		"Status: %s\n"+
+		"Endpoint: %s\n",
 		clusterInfo.GetCluster().GetName(),
 		clusterInfo.GetCluster().GetId(),
-		util.SdkStatusToPrettyString(clusterInfo.GetCluster().GetStatus()))
+		util.SdkStatusToPrettyString(clusterInfo.GetCluster().GetStatus()),
+		endpointStr)
 
 	// Get all node Ids
 	nodes := api.NewOpenStorageNodeClient(conn)
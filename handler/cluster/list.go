@@ -16,16 +16,11 @@ limitations under the License.
 package cluster
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"math/big"
 	"sort"
 	"strings"
-	"text/tabwriter"
 
-	"github.com/cheynewallace/tabby"
-	"github.com/dustin/go-humanize"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -74,7 +69,9 @@ var _ = commander.RegisterCommandInit(func() {
 	ClusterAddCommand(clusterListCmd)
 	clusterListCmd.Flags().StringVar(&clusterListArgs.contextMatch,
 		"context-match", "", "Comma separated list of expressions match the appropriate context")
-	clusterListCmd.Flags().StringP("output", "o", "", "Output in yaml|json|wide")
+	clusterListCmd.Flags().StringP("output", "o", "", "Output in yaml|json|wide|csv|markdown|name|custom-columns=<spec>|jsonpath=<template>|go-template=<template>")
+	clusterListCmd.Flags().Bool("no-headers", false, "Do not print the headers of tables")
+	clusterListCmd.Flags().Bool("raw-units", false, "Show sizes in bytes instead of humanized")
 })
 
 func ClusterListAddCommand(cmd *cobra.Command) {
@@ -89,7 +86,7 @@ func listClusterExec(cmd *cobra.Command, args []string) error {
 	cgf := NewClustersGetFormatter(cvi, args)
 
 	// Print the details and return errors if any
-	return util.PrintFormatted(cgf)
+	return util.PrintFormatted(cmd.Context(), cgf)
 }
 
 type KubernetesClusterInfo struct {
@@ -133,7 +130,7 @@ func NewClustersGetFormatter(cli *cliops.CliInputs, args []string) *clustersGetF
 		cli:  cli,
 		args: args,
 	}
-	f.FormatType = cli.FormatType
+	f.BaseFormatOutput = cli.BaseFormatOutput
 	return f
 }
 
@@ -141,7 +138,7 @@ func (f *clustersGetFormatter) getClusters() ([]*ClusterInfo, error) {
 
 	clusterInfos := make([]*ClusterInfo, 0)
 
-	config.CM().ForEachContext(func(ctxName, clusterName string) error {
+	config.CM().ForEachContext(f.cli.Context(), func(ctx context.Context, ctxName, clusterName string) error {
 		contextMatches := strings.Split(clusterListArgs.contextMatch, ",")
 		if len(clusterListArgs.contextMatch) > 0 &&
 			!util.ListContains(contextMatches, ctxName) &&
@@ -154,7 +151,7 @@ func (f *clustersGetFormatter) getClusters() ([]*ClusterInfo, error) {
 		clusterInfos = append(clusterInfos, clusterInfo)
 
 		// Get K8S Nodes
-		_, cs, err := kubernetes.KubeConnectDefault()
+		_, cs, err := kubernetes.KubeConnectDefault(ctx)
 		if err != nil {
 			return fmt.Errorf("Unable to create a kubernetes connection: %v", err)
 		}
@@ -168,12 +165,12 @@ func (f *clustersGetFormatter) getClusters() ([]*ClusterInfo, error) {
 		clusterInfo.Kubernetes.Nodes = knodesInfo.Items
 
 		// Get StorageCluster and Nodes
-		ctx, conn, err := portworx.PxConnectDefault()
+		ctx, conn, err := portworx.PxConnectDefault(ctx)
 		if err != nil {
 			return err
 		}
 		defer conn.Close()
-		defer kubernetes.StopTunnel()
+		defer kubernetes.StopTunnel(ctx)
 
 		// cluster information
 		cluster := api.NewOpenStorageClusterClient(conn)
@@ -184,7 +181,7 @@ func (f *clustersGetFormatter) getClusters() ([]*ClusterInfo, error) {
 		clusterInfo.Portworx.Cluster = currentClusterInfo.GetCluster()
 
 		// Get all nodes
-		pxops, err := portworx.NewPxOps()
+		pxops, err := portworx.NewPxOps(ctx)
 		if err != nil {
 			return err
 		}
@@ -228,36 +225,41 @@ func (f *clustersGetFormatter) DefaultFormat() (string, error) {
 	return f.toTabbed()
 }
 
-func (f *clustersGetFormatter) toTabbed() (string, error) {
-	var b bytes.Buffer
-	writer := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
-	t := tabby.NewCustom(writer)
-
+// TableFormat returns the table of the clusters
+func (f *clustersGetFormatter) TableFormat() (*util.Table, error) {
 	clusters, _ := f.getClusters()
 
 	currentContext, err := config.CM().ConfigGetCurrentContext()
 	if err != nil {
-		return "", fmt.Errorf("Unable to get current context: %v", err)
+		return nil, fmt.Errorf("Unable to get current context: %v", err)
 	}
 
 	if len(clusters) == 0 {
 		util.Printf("No resources found\n")
-		return "", nil
+		return nil, nil
 	}
 
+	t := &util.Table{}
 	// Start the columns
 	t.AddHeader(f.getHeader()...)
 
 	for _, c := range clusters {
 		l, err := f.getLine(c, currentContext)
 		if err != nil {
-			return "", nil
+			return nil, nil
 		}
 		t.AddLine(l...)
 	}
-	t.Print()
 
-	return b.String(), nil
+	return t, nil
+}
+
+func (f *clustersGetFormatter) toTabbed() (string, error) {
+	t, err := f.TableFormat()
+	if err != nil || t == nil {
+		return "", err
+	}
+	return t.Tabbed(), nil
 }
 
 func (f *clustersGetFormatter) getHeader() []interface{} {
@@ -322,8 +324,8 @@ func (f *clustersGetFormatter) getLine(cluster *ClusterInfo, currentContext stri
 	}
 
 	used, capacity := totalCapacity(cluster.Portworx.Nodes)
-	usedStr := humanize.BigIBytes(big.NewInt(int64(used)))
-	capacityStr := humanize.BigIBytes(big.NewInt(int64(capacity)))
+	usedStr := f.FormatBytes(used)
+	capacityStr := f.FormatBytes(capacity)
 
 	if f.cli.Wide {
 		line = append(line, []interface{}{
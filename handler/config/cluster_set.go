@@ -18,9 +18,12 @@ package configcli
 import (
 	"fmt"
 	"io/ioutil"
+	"time"
 
 	"github.com/portworx/pxc/pkg/commander"
 	"github.com/portworx/pxc/pkg/config"
+	pxgrpc "github.com/portworx/pxc/pkg/grpc"
+	"github.com/portworx/pxc/pkg/portworx"
 	"github.com/portworx/pxc/pkg/util"
 	"github.com/spf13/cobra"
 )
@@ -40,6 +43,21 @@ var _ = commander.RegisterCommandVar(func() {
   # Setup simple endpoint
   pxc config cluster set --name=mycluster --endpoint=127.0.0.1:9020
 
+  # Setup multiple endpoints spreading the calls across healthy nodes
+  pxc config cluster set --name=mycluster --endpoints=10.0.0.1:9020,10.0.0.2:9020 \
+    --load-balancing=round_robin
+
+  # Setup an endpoint using the SDK REST gateway
+  pxc config cluster set --name=mycluster --endpoint=10.0.0.1:9021 --transport=rest
+
+  # Setup an endpoint only reachable through a bastion
+  pxc config cluster set --name=mycluster --endpoint=10.0.0.1:9020 \
+    --proxy-url=socks5://bastion.example.com:1080
+
+  # Setup an endpoint using mutual TLS
+  pxc config cluster set --name=mycluster --endpoint=px.example.com:9020 \
+    --cafile=ca.pem --cert=client.pem --key=client-key.pem
+
   # If in kubectl plugin mode
   pxc config cluster set --portworx-service-port=8900`,
 		RunE: clusterSetExec,
@@ -55,10 +73,37 @@ var _ = commander.RegisterCommandInit(func() {
 		"tls", false, "Enable if using TLS. Passing a CA will enable this automatically.")
 	clusterSetCmd.Flags().StringVar(&clusterSet.CACert,
 		"cafile", "", "Path to CA certificate")
+	clusterSetCmd.Flags().StringVar(&clusterSet.ClientCert,
+		"cert", "", "Path to client certificate for mutual TLS")
+	clusterSetCmd.Flags().StringVar(&clusterSet.ClientKey,
+		"key", "", "Path to client key for mutual TLS")
+	clusterSetCmd.Flags().StringVar(&clusterSet.TLSServerName,
+		"tls-server-name", "", "Server name to use when verifying the server certificate. "+
+			"If not provided, the hostname of the endpoint is used.")
+	clusterSetCmd.Flags().BoolVar(&clusterSet.InsecureSkipTLSVerify,
+		"insecure-skip-tls-verify", false, "Do not verify the server certificate. "+
+			"This makes the connection insecure.")
+	clusterSetCmd.Flags().StringVar(&clusterSet.KeepaliveTime,
+		"keepalive-time", "", "Interval to send keepalive pings to the server when idle. Example: 30s")
+	clusterSetCmd.Flags().StringVar(&clusterSet.KeepaliveTimeout,
+		"keepalive-timeout", "", "Time to wait for a keepalive ping response before closing the connection. Example: 10s")
 	clusterSetCmd.Flags().StringVar(&clusterSet.Endpoint,
 		"endpoint", "", "Direct connection to a Portworx node gRPC endpoint. "+
 			"This endpoint would be used instead of the Kubernetes Portworx API service. "+
 			"Example: 1.1.1.1:9020")
+	clusterSetCmd.Flags().StringVar(&clusterSet.Transport,
+		"transport", "", "Protocol used to connect to the endpoints: grpc or rest (default grpc). "+
+			"When using rest, the endpoints must point to the Portworx SDK REST gateway.")
+	clusterSetCmd.Flags().StringSliceVar(&clusterSet.Endpoints,
+		"endpoints", []string{}, "Comma separated list of additional gRPC endpoints used for failover. "+
+			"A name in the form dns:///px.example.com:9020 is resolved to all of its addresses.")
+	clusterSetCmd.Flags().StringVar(&clusterSet.LoadBalancing,
+		"load-balancing", "", "Load balancing policy across endpoints: round_robin or pick_first (default pick_first)")
+	clusterSetCmd.Flags().StringVar(&clusterSet.ProxyURL,
+		"proxy-url", "", "Proxy used to connect to the endpoints. "+
+			"Supports http, https, and socks5 proxies. Example: socks5://bastion.example.com:1080")
+	clusterSetCmd.Flags().BoolVar(&clusterSet.DisableProxyEnv,
+		"disable-proxy-env", false, "Ignore the HTTPS_PROXY and NO_PROXY environment variables")
 
 	if util.InKubectlPluginMode() {
 		clusterSetCmd.Flags().StringVar(&clusterSet.TunnelServiceNamespace,
@@ -72,14 +117,65 @@ var _ = commander.RegisterCommandInit(func() {
 
 func clusterSetExec(cmd *cobra.Command, args []string) error {
 
+	var err error
 	if len(clusterSet.CACert) != 0 {
-		var err error
 		clusterSet.CACertData, err = ioutil.ReadFile(clusterSet.CACert)
 		if err != nil {
 			return fmt.Errorf("Failed to read %s: %v", clusterSet.CACert, err)
 		}
 	}
 
+	if (len(clusterSet.ClientCert) == 0) != (len(clusterSet.ClientKey) == 0) {
+		return fmt.Errorf("Both --cert and --key must be provided")
+	}
+	if len(clusterSet.ClientCert) != 0 {
+		clusterSet.ClientCertData, err = ioutil.ReadFile(clusterSet.ClientCert)
+		if err != nil {
+			return fmt.Errorf("Failed to read %s: %v", clusterSet.ClientCert, err)
+		}
+		clusterSet.ClientKeyData, err = ioutil.ReadFile(clusterSet.ClientKey)
+		if err != nil {
+			return fmt.Errorf("Failed to read %s: %v", clusterSet.ClientKey, err)
+		}
+	}
+
+	switch clusterSet.Transport {
+	case "", config.TransportGrpc, config.TransportRest:
+	default:
+		return fmt.Errorf("Unsupported transport %s. Must be %s or %s",
+			clusterSet.Transport, config.TransportGrpc, config.TransportRest)
+	}
+
+	if len(clusterSet.LoadBalancing) != 0 {
+		if err := pxgrpc.ValidateLoadBalancing(clusterSet.LoadBalancing); err != nil {
+			return err
+		}
+	}
+
+	if len(clusterSet.ProxyURL) != 0 {
+		if err := pxgrpc.ValidateProxyURL(clusterSet.ProxyURL); err != nil {
+			return err
+		}
+	}
+
+	if len(clusterSet.KeepaliveTime) != 0 {
+		if _, err := time.ParseDuration(clusterSet.KeepaliveTime); err != nil {
+			return fmt.Errorf("Invalid value for --keepalive-time: %v", err)
+		}
+	}
+	if len(clusterSet.KeepaliveTimeout) != 0 {
+		if _, err := time.ParseDuration(clusterSet.KeepaliveTimeout); err != nil {
+			return fmt.Errorf("Invalid value for --keepalive-timeout: %v", err)
+		}
+	}
+
+	// Validate the TLS settings before saving them
+	if clusterSet.UsesTLS() {
+		if _, err := portworx.PxTLSConfig(clusterSet); err != nil {
+			return err
+		}
+	}
+
 	if err := config.CM().ConfigSaveCluster(clusterSet); err != nil {
 		return err
 	}
@@ -65,7 +65,7 @@ func whoAmIExec(cmd *cobra.Command, args []string) error {
 		if len(authInfo.KubernetesAuthInfo.SecretName) != 0 &&
 			len(authInfo.KubernetesAuthInfo.SecretNamespace) != 0 {
 			var err error
-			token, err = portworx.PxGetTokenFromSecret(authInfo.KubernetesAuthInfo.SecretName, authInfo.KubernetesAuthInfo.SecretNamespace)
+			token, err = portworx.PxGetTokenFromSecret(cmd.Context(), authInfo.KubernetesAuthInfo.SecretName, authInfo.KubernetesAuthInfo.SecretNamespace)
 			if err != nil {
 				return fmt.Errorf("Unable to retreive token from Kubernetes: %v", err)
 			}
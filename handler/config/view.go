@@ -59,6 +59,12 @@ func viewExec(cmd *cobra.Command, args []string) error {
 		if len(cluster.CACertData) != 0 {
 			cluster.CACertData = []byte("<REDACTED>")
 		}
+		if len(cluster.ClientCertData) != 0 {
+			cluster.ClientCertData = []byte("<REDACTED>")
+		}
+		if len(cluster.ClientKeyData) != 0 {
+			cluster.ClientKeyData = []byte("<REDACTED>")
+		}
 	}
 
 	util.PrintYaml(configInfo)
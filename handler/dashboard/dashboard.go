@@ -0,0 +1,310 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package dashboard
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/cmd"
+	"github.com/portworx/pxc/pkg/commander"
+	prototime "github.com/portworx/pxc/pkg/openstorage/proto/time"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/tui"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// topVolumes is the number of volumes in the top volume tables
+	topVolumes = 10
+	// maxAlerts is the number of alerts in the alert feed
+	maxAlerts = 50
+)
+
+var dashboardCmd *cobra.Command
+
+var _ = commander.RegisterCommandVar(func() {
+	dashboardCmd = &cobra.Command{
+		Use:   "dashboard",
+		Short: "Show a full screen dashboard of the cluster",
+		Long: `Show the status and capacity of the cluster, the nodes, the most recent
+alerts, the busiest and slowest volumes, and the resyncs, cloud backups,
+restores and migrations in progress. Each panel is refreshed on its own
+interval, so a slow request only delays its own panel.`,
+		Example: `
+  # Show the dashboard
+  pxc dashboard
+
+  # Refresh the volume stats every 5 seconds
+  pxc dashboard --stats-interval 5s`,
+		RunE: dashboardExec,
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	cmd.RootAddCommand(dashboardCmd)
+	dashboardCmd.Flags().Duration("interval", 10*time.Second, "Refresh interval of the cluster, nodes and operations")
+	dashboardCmd.Flags().Duration("stats-interval", 2*time.Second, "Refresh interval of the top volumes")
+	dashboardCmd.Flags().Duration("alerts-interval", 5*time.Second, "Refresh interval of the alerts")
+})
+
+func dashboardExec(c *cobra.Command, args []string) error {
+	interval, _ := c.Flags().GetDuration("interval")
+	statsInterval, _ := c.Flags().GetDuration("stats-interval")
+	alertsInterval, _ := c.Flags().GetDuration("alerts-interval")
+	for _, i := range []time.Duration{interval, statsInterval, alertsInterval} {
+		if i < time.Second {
+			return fmt.Errorf("Intervals should not be less than 1s")
+		}
+	}
+
+	pxops, err := portworx.NewPxOps(c.Context())
+	if err != nil {
+		return err
+	}
+	defer pxops.Close()
+
+	d := tui.NewDashboard(&dashboardModel{
+		pxops:    pxops,
+		alertOps: portworx.NewPxAlertOps(c.Context()),
+	}, tui.DashboardIntervals{
+		Cluster:    interval,
+		Nodes:      interval,
+		Alerts:     alertsInterval,
+		Volumes:    statsInterval,
+		Operations: interval,
+	})
+	return d.Display()
+}
+
+// dashboardModel gets the data of the dashboard. Its methods are called
+// concurrently by the panels, which share pxops. PxOps is safe for
+// concurrent use with both the gRPC and the REST transports.
+type dashboardModel struct {
+	pxops    portworx.PxOps
+	alertOps portworx.PxAlertOps
+}
+
+func (m *dashboardModel) HumanizeBytes(b uint64) string {
+	return humanize.BigIBytes(big.NewInt(int64(b)))
+}
+
+func (m *dashboardModel) GetClusterSummary() (*tui.ClusterSummary, error) {
+	s := &tui.ClusterSummary{Status: "Unknown"}
+	if conn := m.pxops.GetConn(); conn != nil {
+		resp, err := api.NewOpenStorageClusterClient(conn).InspectCurrent(
+			m.pxops.GetCtx(), &api.SdkClusterInspectCurrentRequest{})
+		if err != nil {
+			return nil, err
+		}
+		s.Name = resp.GetCluster().GetName()
+		s.Status = util.SdkStatusToPrettyString(resp.GetCluster().GetStatus())
+	}
+
+	nodes, err := portworx.NewNodes(m.pxops, &portworx.NodeSpec{}).GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	s.Nodes = len(nodes)
+	for _, n := range nodes {
+		if n.GetStatus() == api.Status_STATUS_OK {
+			s.NodesUp++
+		}
+		used, total := portworx.GetTotalCapacity(n)
+		s.Used += used
+		s.Total += total
+	}
+
+	vols, err := portworx.NewVolumes(m.pxops, &portworx.VolumeSpec{}).GetVolumes()
+	if err != nil {
+		return nil, err
+	}
+	s.Volumes = len(vols)
+	return s, nil
+}
+
+func (m *dashboardModel) GetNodes() (*tui.DashboardTable, error) {
+	nodes, err := portworx.NewNodes(m.pxops, &portworx.NodeSpec{}).GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].GetHostname() < nodes[j].GetHostname()
+	})
+
+	t := &tui.DashboardTable{
+		Headers: []string{"Name", "Status", "Pools", "Used", "Capacity"},
+	}
+	for _, n := range nodes {
+		used, total := portworx.GetTotalCapacity(n)
+		t.Rows = append(t.Rows, []string{
+			n.GetHostname(),
+			util.SdkStatusToPrettyString(n.GetStatus()),
+			fmt.Sprintf("%d", len(n.GetPools())),
+			m.HumanizeBytes(used),
+			m.HumanizeBytes(total),
+		})
+	}
+	return t, nil
+}
+
+func (m *dashboardModel) GetAlerts() ([]string, error) {
+	resp, err := m.alertOps.GetPxAlerts(portworx.CliAlertInputs{AlertType: "all"})
+	if err != nil {
+		return nil, err
+	}
+
+	// The alerts are sorted from the oldest
+	alerts := make([]string, 0, maxAlerts)
+	for i := len(resp.AlertResp) - 1; i >= 0 && len(alerts) < maxAlerts; i-- {
+		a := resp.AlertResp[i]
+		if a.GetCleared() {
+			continue
+		}
+		alerts = append(alerts, fmt.Sprintf("%s %-6s %s",
+			prototime.TimestampToTime(a.GetTimestamp()).Format(util.TimeFormat),
+			portworx.SeverityString(a.GetSeverity()),
+			a.GetMessage()))
+	}
+	return alerts, nil
+}
+
+type volumeIo struct {
+	name     string
+	iops     uint64
+	readLat  uint64
+	writeLat uint64
+}
+
+func (m *dashboardModel) GetTopVolumes() (*tui.DashboardTable, *tui.DashboardTable, error) {
+	vols, err := portworx.NewVolumes(m.pxops, &portworx.VolumeSpec{}).GetVolumes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attached := portworx.AttachedVolumes(vols)
+	stats, err := portworx.GetVolumesStats(m.pxops, attached, portworx.StatsWorkers)
+	if err != nil {
+		return nil, nil, err
+	}
+	ios := make([]*volumeIo, len(attached))
+	for i, s := range stats {
+		if s == nil {
+			continue
+		}
+		ios[i] = &volumeIo{
+			name:     attached[i].GetLocator().GetName(),
+			iops:     portworx.Iops(s),
+			readLat:  portworx.ReadLatency(s),
+			writeLat: portworx.WriteLatency(s),
+		}
+	}
+
+	found := make([]*volumeIo, 0, len(ios))
+	for _, io := range ios {
+		if io != nil {
+			found = append(found, io)
+		}
+	}
+
+	headers := []string{"Name", "IOPS", "Read Lat", "Write Lat"}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].iops > found[j].iops
+	})
+	byIops := &tui.DashboardTable{Headers: headers, Rows: volumeIoRows(found)}
+	sort.SliceStable(found, func(i, j int) bool {
+		return maxUint64(found[i].readLat, found[i].writeLat) > maxUint64(found[j].readLat, found[j].writeLat)
+	})
+	byLatency := &tui.DashboardTable{Headers: headers, Rows: volumeIoRows(found)}
+	return byIops, byLatency, nil
+}
+
+func volumeIoRows(ios []*volumeIo) [][]string {
+	rows := make([][]string, 0, topVolumes)
+	for i := 0; i < len(ios) && i < topVolumes; i++ {
+		rows = append(rows, []string{
+			ios[i].name,
+			fmt.Sprintf("%d", ios[i].iops),
+			latencyString(ios[i].readLat),
+			latencyString(ios[i].writeLat),
+		})
+	}
+	return rows
+}
+
+func latencyString(micro uint64) string {
+	return (time.Duration(micro) * time.Microsecond).String()
+}
+
+func maxUint64(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func (m *dashboardModel) GetOperations() (*tui.DashboardTable, error) {
+	vols, err := portworx.NewVolumes(m.pxops, &portworx.VolumeSpec{}).GetVolumes()
+	if err != nil {
+		return nil, err
+	}
+	ops := portworx.ResyncOperations(vols)
+
+	// Cloud backups and migrations are only available with gRPC
+	if conn := m.pxops.GetConn(); conn != nil {
+		names := make(map[string]string)
+		for _, v := range vols {
+			names[v.GetId()] = v.GetLocator().GetName()
+		}
+		// Show the resyncs even when the cloud backup or migration status
+		// cannot be read, for example when no cloud credentials are set
+		backups, err := portworx.GetBackupOperations(m.pxops.GetCtx(), conn, names)
+		if err != nil {
+			backups = []*portworx.Operation{operationError(portworx.OperationBackup, err)}
+		}
+		migrations, err := portworx.GetMigrationOperations(m.pxops.GetCtx(), conn)
+		if err != nil {
+			migrations = []*portworx.Operation{operationError(portworx.OperationMigration, err)}
+		}
+		ops = append(append(ops, backups...), migrations...)
+	}
+
+	t := &tui.DashboardTable{
+		Headers: []string{"Operation", "Volume", "Status", "Progress"},
+	}
+	for _, op := range ops {
+		progress := ""
+		if op.BytesTotal != 0 {
+			progress = fmt.Sprintf("%d%% of %s", op.BytesDone*100/op.BytesTotal, m.HumanizeBytes(op.BytesTotal))
+		} else if op.BytesDone != 0 {
+			progress = m.HumanizeBytes(op.BytesDone)
+		}
+		t.Rows = append(t.Rows, []string{op.Kind, op.Volume, op.Status, progress})
+	}
+	return t, nil
+}
+
+func operationError(kind string, err error) *portworx.Operation {
+	return &portworx.Operation{
+		Kind:   kind,
+		Status: "Failed to get status: " + util.PxError(err).Error(),
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package exporter
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/portworx/pxc/cmd"
+	"github.com/portworx/pxc/pkg/commander"
+	"github.com/portworx/pxc/pkg/exporter"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var exporterCmd *cobra.Command
+
+var _ = commander.RegisterCommandVar(func() {
+	exporterCmd = &cobra.Command{
+		Use:   "exporter",
+		Short: "Expose volume, node and alert metrics to Prometheus",
+		Long: `Periodically collect the IOPS, throughput, latency and usage of every
+volume, the status and capacity of the nodes and their pools, and the number
+of alerts by severity, and serve them as Prometheus metrics.
+
+Volume metrics have the volume, volume_id, pvc and namespace labels. The
+metrics of the last successful collection are served when a collection fails.`,
+		Example: `
+  # Serve the metrics on port 9101
+  pxc exporter --listen :9101
+
+  # Collect the metrics every minute
+  pxc exporter --listen :9101 --interval 1m`,
+		RunE: exporterExec,
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	cmd.RootAddCommand(exporterCmd)
+	exporterCmd.Flags().String("listen", ":9101", "Address to serve the metrics on")
+	exporterCmd.Flags().String("path", "/metrics", "Path of the metrics")
+	exporterCmd.Flags().Duration("interval", 30*time.Second, "Time between collections")
+	exporterCmd.Flags().Int("workers", 8, "Number of volume stats requested at the same time")
+})
+
+func exporterExec(c *cobra.Command, args []string) error {
+	listen, _ := c.Flags().GetString("listen")
+	path, _ := c.Flags().GetString("path")
+	interval, _ := c.Flags().GetDuration("interval")
+	if interval < time.Second {
+		return fmt.Errorf("--interval should not be less than 1s")
+	}
+	workers, _ := c.Flags().GetInt("workers")
+
+	pxops, err := portworx.NewPxOps(c.Context())
+	if err != nil {
+		return err
+	}
+	defer pxops.Close()
+
+	collector := exporter.NewCollector(pxops, portworx.NewPxAlertOps(c.Context()), workers)
+	stop := make(chan struct{})
+	defer close(stop)
+	go collector.Run(interval, stop)
+
+	mux := http.NewServeMux()
+	mux.Handle(path, collector)
+	util.Eprintf("Serving metrics on %s%s\n", listen, path)
+	return http.ListenAndServe(listen, mux)
+}
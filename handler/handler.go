@@ -17,18 +17,24 @@ package handler
 
 import (
 	// import all handlers to register them
+	_ "github.com/portworx/pxc/handler/apply"
 	_ "github.com/portworx/pxc/handler/auth"
 	_ "github.com/portworx/pxc/handler/auth/guestaccess"
 	_ "github.com/portworx/pxc/handler/cluster"
 	_ "github.com/portworx/pxc/handler/cluster/alerts"
 	_ "github.com/portworx/pxc/handler/config"
+	_ "github.com/portworx/pxc/handler/dashboard"
+	_ "github.com/portworx/pxc/handler/exporter"
 	_ "github.com/portworx/pxc/handler/login"
 	_ "github.com/portworx/pxc/handler/node"
 	_ "github.com/portworx/pxc/handler/plugin"
 	_ "github.com/portworx/pxc/handler/pvc"
+	_ "github.com/portworx/pxc/handler/report"
 	_ "github.com/portworx/pxc/handler/script"
 	_ "github.com/portworx/pxc/handler/utilities"
 	_ "github.com/portworx/pxc/handler/volume"
+	_ "github.com/portworx/pxc/handler/volume/fsck"
+	_ "github.com/portworx/pxc/handler/volume/fstrim"
 	// The following features will not be released until further work
 	//	_ "github.com/portworx/pxc/handler/cloudmigration"
 	//	_ "github.com/portworx/pxc/handler/clusterpair"
@@ -56,7 +56,7 @@ var _ = commander.RegisterCommandInit(func() {
 })
 
 func describeNodeExec(c *cobra.Command, args []string) error {
-	ctx, conn, err := portworx.PxConnectDefault()
+	ctx, conn, err := portworx.PxConnectDefault(c.Context())
 	if err != nil {
 		return err
 	}
@@ -16,13 +16,8 @@ limitations under the License.
 package node
 
 import (
-	"bytes"
 	"fmt"
-	"math/big"
-	"text/tabwriter"
 
-	"github.com/cheynewallace/tabby"
-	humanize "github.com/dustin/go-humanize"
 	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
 	"github.com/portworx/pxc/pkg/cliops"
 	"github.com/portworx/pxc/pkg/commander"
@@ -45,7 +40,12 @@ var _ = commander.RegisterCommandVar(func() {
 
 var _ = commander.RegisterCommandInit(func() {
 	NodeAddCommand(getNodesCmd)
-	getNodesCmd.Flags().StringP("output", "o", "", "Output in yaml|json|wide")
+	getNodesCmd.Flags().StringP("output", "o", "", "Output in yaml|json|wide|csv|markdown|name|custom-columns=<spec>|jsonpath=<template>|go-template=<template>")
+	getNodesCmd.Flags().Bool("no-headers", false, "Do not print the headers of tables")
+	getNodesCmd.Flags().Bool("raw-units", false, "Show sizes in bytes instead of humanized")
+	getNodesCmd.Flags().String("sort-by", "", "Sort on a field like name, used, capacity or status. Prefix with - to sort in descending order")
+	getNodesCmd.Flags().String("field-selector", "", "Select nodes on fields, comma-separated field<operator>value like status=ready,used>100GiB")
+	getNodesCmd.Flags().Int("limit", 0, "Maximum number of nodes shown. 0 shows all the nodes")
 	getNodesCmd.Flags().Bool("show-labels", false, "Show labels in the last column of the output")
 })
 
@@ -71,7 +71,7 @@ func getNodesExec(cmd *cobra.Command, args []string) error {
 	ngf := NewNodesGetFormatter(cliOps)
 
 	// Print the details and return errors if any
-	return util.PrintFormatted(ngf)
+	return util.PrintFormatted(cmd.Context(), ngf)
 
 }
 
@@ -88,7 +88,7 @@ func NewNodesGetFormatter(cliOps cliops.CliOps) *nodesGetFormatter {
 		nodeIdentifiers: cliOps.CliInputs().Args,
 		nodes:           portworx.NewNodes(cliOps.PxOps(), &portworx.NodeSpec{}),
 	}
-	n.FormatType = cliOps.CliInputs().FormatType
+	n.BaseFormatOutput = cliOps.CliInputs().BaseFormatOutput
 	return n
 }
 
@@ -128,7 +128,7 @@ func (p *nodesGetFormatter) getNodes() ([]*api.StorageNode, error) {
 			}
 		}
 	}
-	return nodes, nil
+	return portworx.SelectNodes(nodes, &p.cliOps.CliInputs().ListOptions)
 }
 
 // YamlFormat returns the yaml representation of the object
@@ -159,34 +159,39 @@ func (p *nodesGetFormatter) DefaultFormat() (string, error) {
 	return p.toTabbed()
 }
 
-func (p *nodesGetFormatter) toTabbed() (string, error) {
-	var b bytes.Buffer
-	writer := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
-	t := tabby.NewCustom(writer)
-
+// TableFormat returns the table of the nodes
+func (p *nodesGetFormatter) TableFormat() (*util.Table, error) {
 	nodes, err := p.getNodes()
 	if err != nil {
-		return "", util.PxErrorMessage(err, "Failed to get node list")
+		return nil, util.PxErrorMessage(err, "Failed to get node list")
 	}
 
 	if len(nodes) == 0 {
 		util.Printf("No resources found\n")
-		return "", nil
+		return nil, nil
 	}
 
+	t := &util.Table{}
 	// Start the columns
 	t.AddHeader(p.getHeader()...)
 
 	for _, n := range nodes {
 		l, err := p.getLine(n)
 		if err != nil {
-			return "", nil
+			return nil, nil
 		}
 		t.AddLine(l...)
 	}
-	t.Print()
 
-	return b.String(), nil
+	return t, nil
+}
+
+func (p *nodesGetFormatter) toTabbed() (string, error) {
+	t, err := p.TableFormat()
+	if err != nil || t == nil {
+		return "", err
+	}
+	return t.Tabbed(), nil
 }
 
 func (p *nodesGetFormatter) getHeader() []interface{} {
@@ -213,8 +218,8 @@ func (p *nodesGetFormatter) getLine(n *api.StorageNode) ([]interface{}, error) {
 		capacity += pool.GetTotalSize()
 	}
 
-	usedStr := humanize.BigIBytes(big.NewInt(int64(used)))
-	capacityStr := humanize.BigIBytes(big.NewInt(int64(capacity)))
+	usedStr := p.FormatBytes(used)
+	capacityStr := p.FormatBytes(capacity)
 
 	// Return a line
 	var line []interface{}
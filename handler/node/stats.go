@@ -0,0 +1,133 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package node
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/portworx/pxc/pkg/cliops"
+	"github.com/portworx/pxc/pkg/commander"
+	"github.com/portworx/pxc/pkg/tui"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var nodeStatsCmd *cobra.Command
+
+var _ = commander.RegisterCommandVar(func() {
+	nodeStatsCmd = &cobra.Command{
+		Use:   "stats [NODE_ID]",
+		Short: "Get stats of Portworx nodes",
+		Long: `Show the CPU, memory and pool usage of the nodes, and the number of volumes
+attached on each node with the sum of their IOPS and throughput.`,
+		Example: `
+  # Show the stats of all the nodes
+  pxc node stats
+
+  # Monitor the stats sorted by the used pool capacity
+  pxc node stats --watch --sort-on "Pool Used"`,
+		RunE: nodeStatsExec,
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	NodeAddCommand(nodeStatsCmd)
+
+	headers := strings.Join(allHeaders, "|")
+	sortMsg := fmt.Sprintf("Specify one of '%s' to sort on", headers)
+	nodeStatsCmd.Flags().String("sort-on", allHeaders[int(DEFAULT_SORT_COLUMN)], sortMsg)
+	nodeStatsCmd.Flags().String("sort-order", "desc", "Sort in ascending or descending order. Specify one of asc|desc")
+	nodeStatsCmd.Flags().BoolP("watch", "w", false, "Monitor stats at a periodic interval")
+	nodeStatsCmd.Flags().DurationP("interval", "i", time.Second*2, "Specify refresh interval")
+	nodeStatsCmd.Flags().Bool("no-graphs", false, "Don't show graphs")
+	nodeStatsCmd.Flags().MarkHidden("no-graphs")
+})
+
+func nodeStatsExec(cmd *cobra.Command, args []string) error {
+	sortOn, _ := cmd.Flags().GetString("sort-on")
+	if util.ListContains(allHeaders, sortOn) == false {
+		return fmt.Errorf("Unknown column %s to sort on", sortOn)
+	}
+
+	sortOrder, _ := cmd.Flags().GetString("sort-order")
+	so := false
+	switch sortOrder {
+	case "asc":
+		so = true
+	case "desc":
+		so = false
+	default:
+		return fmt.Errorf("sort-order should be one of asc or desc")
+	}
+
+	cliOps := cliops.NewCliOps(cliops.NewCliInputs(cmd, args))
+	if err := cliOps.Connect(); err != nil {
+		return err
+	}
+	defer cliOps.Close()
+
+	nsd := NewNodeStats(cliOps.PxOps(), args)
+	nsd.SetSortInfo(sortOn, so)
+
+	watch, _ := cmd.Flags().GetBool("watch")
+	if !watch {
+		nsd.ShowSortMarker(false)
+		return printNodeStats(nsd)
+	}
+
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval < 2*time.Second {
+		return fmt.Errorf("--interval should not be less than 2s")
+	}
+	numGraphs := len(graphTitles)
+	if noGraphs, _ := cmd.Flags().GetBool("no-graphs"); noGraphs {
+		numGraphs = 0
+	}
+	nsd.ShowSortMarker(true)
+	return tui.NewStatsView(numGraphs).Display(nsd, interval)
+}
+
+func printNodeStats(nsd NodeStats) error {
+	if err := nsd.Refresh(); err != nil {
+		return err
+	}
+
+	t := util.NewTabby()
+	h := nsd.GetHeaders()
+	hi := make([]interface{}, len(h))
+	for i := range h {
+		hi[i] = h[i]
+	}
+	t.AddHeader(hi...)
+	for {
+		line, err := nsd.NextRow()
+		if err != nil {
+			return err
+		}
+		if len(line) == 0 {
+			break
+		}
+		l := make([]interface{}, len(line))
+		for i := range line {
+			l[i] = line[i]
+		}
+		t.AddLine(l...)
+	}
+	t.Print()
+	return nil
+}
@@ -0,0 +1,321 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package node
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/tui"
+	"github.com/portworx/pxc/pkg/util"
+)
+
+type ColNum int
+
+const (
+	NODE_NAME ColNum = iota
+	STATUS
+	CPU
+	MEM_USED
+	MEM_TOTAL
+	POOL_USED
+	POOL_TOTAL
+	VOLUMES
+	IOPS
+	READ_TPUT
+	WRITE_TPUT
+)
+
+const (
+	DEFAULT_SORT_COLUMN = IOPS
+)
+
+var (
+	allHeaders = []string{
+		"Name",
+		"Status",
+		"CPU",
+		"Mem Used",
+		"Mem Total",
+		"Pool Used",
+		"Pool Total",
+		"Volumes",
+		"IOPS",
+		"Read Tput",
+		"Write Tput",
+	}
+
+	graphTitles = []string{
+		"IOPS",
+		"Read Throughput",
+		"Write Throughput",
+		"Avg. CPU",
+		"Pool Used",
+	}
+)
+
+type NodeStats interface {
+	tui.StatsModel
+	// Set if we need to show the sort marker in the column header
+	ShowSortMarker(fg bool)
+}
+
+type nodeStatsData struct {
+	pxops      portworx.PxOps
+	nodeNames  []string
+	curStats   []*nodeStatsRow
+	curIndex   int
+	sortInfo   *nodeStatsSorterInfo
+	curTotal   *nodeStatsRow
+	sortMarker bool
+}
+
+type nodeStatsSorterInfo struct {
+	ascending bool
+	column    ColNum
+}
+
+type nodeStatsRow struct {
+	name      string
+	status    string
+	cpu       float64
+	memUsed   uint64
+	memTotal  uint64
+	poolUsed  uint64
+	poolTotal uint64
+	volumes   uint64
+	iops      uint64
+	readTput  uint64
+	writeTput uint64
+}
+
+func NewNodeStats(pxops portworx.PxOps, nodeNames []string) NodeStats {
+	return &nodeStatsData{
+		pxops:     pxops,
+		nodeNames: nodeNames,
+		sortInfo: &nodeStatsSorterInfo{
+			ascending: false,
+			column:    DEFAULT_SORT_COLUMN,
+		},
+		curTotal:   &nodeStatsRow{},
+		sortMarker: true,
+	}
+}
+
+func (nsd *nodeStatsData) ShowSortMarker(fg bool) {
+	nsd.sortMarker = fg
+}
+
+// Refresh gets the nodes and the stats of all the volumes. The IO of each
+// node is the sum of the IO of the volumes attached on it.
+func (nsd *nodeStatsData) Refresh() error {
+	nodes, err := portworx.NewNodes(nsd.pxops, &portworx.NodeSpec{NodeNames: nsd.nodeNames}).GetNodes()
+	if err != nil {
+		return err
+	}
+	vols, err := portworx.NewVolumes(nsd.pxops, &portworx.VolumeSpec{}).GetVolumes()
+	if err != nil {
+		return err
+	}
+
+	attached := portworx.AttachedVolumes(vols)
+	stats, err := portworx.GetVolumesStats(nsd.pxops, attached, portworx.StatsWorkers)
+	if err != nil {
+		return err
+	}
+
+	rows := make(map[string]*nodeStatsRow)
+	nsd.curStats = make([]*nodeStatsRow, len(nodes))
+	nsd.curTotal = &nodeStatsRow{}
+	nsd.curIndex = 0
+	for i, n := range nodes {
+		row := &nodeStatsRow{
+			name:     n.GetHostname(),
+			status:   util.SdkStatusToPrettyString(n.GetStatus()),
+			cpu:      n.GetCpu(),
+			memUsed:  n.GetMemUsed(),
+			memTotal: n.GetMemTotal(),
+		}
+		row.poolUsed, row.poolTotal = portworx.GetTotalCapacity(n)
+		rows[n.GetId()] = row
+		nsd.curStats[i] = row
+
+		nsd.curTotal.cpu += row.cpu
+		nsd.curTotal.poolUsed += row.poolUsed
+	}
+	for i, v := range attached {
+		row, ok := rows[v.GetAttachedOn()]
+		if !ok {
+			continue
+		}
+		row.volumes++
+		if stats[i] == nil {
+			continue
+		}
+		row.iops += portworx.Iops(stats[i])
+		row.readTput += portworx.ReadThroughput(stats[i])
+		row.writeTput += portworx.WriteThroughput(stats[i])
+
+		nsd.curTotal.iops += portworx.Iops(stats[i])
+		nsd.curTotal.readTput += portworx.ReadThroughput(stats[i])
+		nsd.curTotal.writeTput += portworx.WriteThroughput(stats[i])
+	}
+	if len(nodes) != 0 {
+		nsd.curTotal.cpu /= float64(len(nodes))
+	}
+
+	sort.SliceStable(nsd.curStats, func(i, j int) bool {
+		if nsd.sortInfo.ascending {
+			return nsd.less(nsd.curStats[i], nsd.curStats[j])
+		}
+		return nsd.less(nsd.curStats[j], nsd.curStats[i])
+	})
+	return nil
+}
+
+func (nsd *nodeStatsData) less(a, b *nodeStatsRow) bool {
+	switch nsd.sortInfo.column {
+	case NODE_NAME:
+		return a.name < b.name
+	case STATUS:
+		return a.status < b.status
+	case CPU:
+		return a.cpu < b.cpu
+	case MEM_USED:
+		return a.memUsed < b.memUsed
+	case MEM_TOTAL:
+		return a.memTotal < b.memTotal
+	case POOL_USED:
+		return a.poolUsed < b.poolUsed
+	case POOL_TOTAL:
+		return a.poolTotal < b.poolTotal
+	case VOLUMES:
+		return a.volumes < b.volumes
+	case IOPS:
+		return a.iops < b.iops
+	case READ_TPUT:
+		return a.readTput < b.readTput
+	case WRITE_TPUT:
+		return a.writeTput < b.writeTput
+	}
+	return false
+}
+
+func (nsd *nodeStatsData) GetTitle() string {
+	return "Node Stats (Press ? for help)"
+}
+
+func (nsd *nodeStatsData) GetHeaders() []string {
+	tmp := make([]string, len(allHeaders))
+	copy(tmp, allHeaders)
+	if nsd.sortMarker == true {
+		i := int(nsd.sortInfo.column)
+		tmp[i] = fmt.Sprintf("*%s", tmp[i])
+	}
+	return tmp
+}
+
+func (nsd *nodeStatsData) NextRow() ([]string, error) {
+	if nsd.curIndex >= len(nsd.curStats) {
+		return make([]string, 0), nil
+	}
+
+	row := nsd.curStats[nsd.curIndex]
+	nsd.curIndex++
+	cols := make([]string, len(allHeaders))
+	cols[NODE_NAME] = row.name
+	cols[STATUS] = row.status
+	cols[CPU] = fmt.Sprintf("%.1f%%", row.cpu)
+	cols[MEM_USED] = humanize.IBytes(row.memUsed)
+	cols[MEM_TOTAL] = humanize.IBytes(row.memTotal)
+	cols[POOL_USED] = humanize.IBytes(row.poolUsed)
+	cols[POOL_TOTAL] = humanize.IBytes(row.poolTotal)
+	cols[VOLUMES] = fmt.Sprintf("%v", row.volumes)
+	cols[IOPS] = fmt.Sprintf("%v", row.iops)
+	cols[READ_TPUT] = fmt.Sprintf("%v/s", humanize.Bytes(row.readTput))
+	cols[WRITE_TPUT] = fmt.Sprintf("%v/s", humanize.Bytes(row.writeTput))
+	return cols, nil
+}
+
+func (nsd *nodeStatsData) SetSortInfo(colName string, ascending bool) {
+	nsd.sortInfo.column = nsd.colNameToNum(colName)
+	nsd.sortInfo.ascending = ascending
+}
+
+func (nsd *nodeStatsData) GetSortInfo() (string, bool) {
+	return allHeaders[int(nsd.sortInfo.column)], nsd.sortInfo.ascending
+}
+
+func (nsd *nodeStatsData) MoveSortColumnNext() {
+	nsd.sortInfo.column = ColNum((int(nsd.sortInfo.column) + 1) % len(allHeaders))
+}
+
+func (nsd *nodeStatsData) MoveSortColumnPrev() {
+	nsd.sortInfo.column = ColNum((int(nsd.sortInfo.column) + len(allHeaders) - 1) % len(allHeaders))
+}
+
+func (nsd *nodeStatsData) colNameToNum(str string) ColNum {
+	s := strings.TrimPrefix(str, "*")
+	for i, r := range allHeaders {
+		if r == s {
+			return ColNum(i)
+		}
+	}
+	return DEFAULT_SORT_COLUMN
+}
+
+func (nsd *nodeStatsData) GetGraphTitle(index int) (string, error) {
+	if index >= len(graphTitles) {
+		return "", fmt.Errorf("Unknown index %d for graph title", index)
+	}
+	return graphTitles[index], nil
+}
+
+func (nsd *nodeStatsData) GetGraphData(index int) (float64, error) {
+	switch index {
+	case 0:
+		return float64(nsd.curTotal.iops), nil
+	case 1:
+		return float64(nsd.curTotal.readTput), nil
+	case 2:
+		return float64(nsd.curTotal.writeTput), nil
+	case 3:
+		return nsd.curTotal.cpu, nil
+	case 4:
+		return float64(nsd.curTotal.poolUsed), nil
+	}
+	return 0.0, fmt.Errorf("Unknown index %d for graph title", index)
+}
+
+func (nsd *nodeStatsData) Humanize(index int, val float64) (string, error) {
+	if val == 0.0 {
+		return " ", nil
+	}
+	switch index {
+	case 0:
+		return fmt.Sprintf("%0.0f", val), nil
+	case 1, 2:
+		return fmt.Sprintf("%v/s", humanize.Bytes(uint64(val))), nil
+	case 3:
+		return fmt.Sprintf("%.1f%%", val), nil
+	case 4:
+		return humanize.IBytes(uint64(val)), nil
+	}
+	return "", fmt.Errorf("Unknown index %d for graph title", index)
+}
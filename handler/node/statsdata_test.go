@@ -0,0 +1,189 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package node
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakePxOps returns the nodes, volumes and the stats of the volumes by id
+type fakePxOps struct {
+	lock  sync.Mutex
+	nodes []*api.StorageNode
+	vols  []*api.Volume
+	stats map[string]*api.Stats
+}
+
+func (f *fakePxOps) Close() {}
+
+func (f *fakePxOps) GetVolumesBySpec(vs *portworx.VolumeSpec) ([]*api.SdkVolumeInspectResponse, error) {
+	resps := make([]*api.SdkVolumeInspectResponse, len(f.vols))
+	for i, v := range f.vols {
+		resps[i] = &api.SdkVolumeInspectResponse{Volume: v, Name: v.GetLocator().GetName()}
+	}
+	return resps, nil
+}
+
+func (f *fakePxOps) GetVolumeById(id string) (*api.SdkVolumeInspectResponse, error) {
+	return nil, status.Errorf(codes.NotFound, "Volume %s not found", id)
+}
+
+func (f *fakePxOps) GetStats(v *api.Volume, notCumulative bool) (*api.Stats, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if s, ok := f.stats[v.GetId()]; ok {
+		return s, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "Volume %s not found", v.GetId())
+}
+
+func (f *fakePxOps) EnumerateNodes() ([]string, error) {
+	ids := make([]string, len(f.nodes))
+	for i, n := range f.nodes {
+		ids[i] = n.GetId()
+	}
+	return ids, nil
+}
+
+func (f *fakePxOps) GetNode(id string) (*api.StorageNode, error) {
+	for _, n := range f.nodes {
+		if n.GetId() == id {
+			return n, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "Node %s not found", id)
+}
+
+func (f *fakePxOps) GetCtx() context.Context {
+	return context.Background()
+}
+
+func (f *fakePxOps) GetConn() *grpc.ClientConn {
+	return nil
+}
+
+func newFakeNodeStatsPxOps() *fakePxOps {
+	return &fakePxOps{
+		nodes: []*api.StorageNode{
+			{
+				Id:       "n1",
+				Hostname: "host1",
+				Status:   api.Status_STATUS_OK,
+				Cpu:      10,
+				Pools:    []*api.StoragePool{{Used: 10, TotalSize: 100}},
+			},
+			{
+				Id:       "n2",
+				Hostname: "host2",
+				Status:   api.Status_STATUS_OK,
+				Cpu:      30,
+				Pools:    []*api.StoragePool{{Used: 20, TotalSize: 100}, {Used: 30, TotalSize: 100}},
+			},
+		},
+		vols: []*api.Volume{
+			{Id: "1", AttachedOn: "n1"},
+			{Id: "2", AttachedOn: "n1"},
+			{Id: "3", AttachedOn: "n2"},
+			// Detached volumes have no IO
+			{Id: "4"},
+			// The volume was deleted after it was listed
+			{Id: "5", AttachedOn: "n2"},
+		},
+		stats: map[string]*api.Stats{
+			"1": {Reads: 10, Writes: 10, ReadBytes: 1000, IntervalMs: 1000},
+			"2": {Reads: 20, Writes: 0, WriteBytes: 4000, IntervalMs: 2000},
+			"3": {Reads: 5, Writes: 0, ReadBytes: 500, IntervalMs: 1000},
+		},
+	}
+}
+
+// rows returns the rows of the model in order
+func rows(t *testing.T, nsd *nodeStatsData) [][]string {
+	rows := make([][]string, 0)
+	for {
+		row, err := nsd.NextRow()
+		assert.NoError(t, err)
+		if len(row) == 0 {
+			return rows
+		}
+		rows = append(rows, row)
+	}
+}
+
+func TestNodeStatsRefresh(t *testing.T) {
+	nsd := NewNodeStats(newFakeNodeStatsPxOps(), nil).(*nodeStatsData)
+	assert.NoError(t, nsd.Refresh())
+
+	// Sorted on IOPS in descending order by default
+	assert.Len(t, nsd.curStats, 2)
+	n1, n2 := nsd.curStats[0], nsd.curStats[1]
+	assert.Equal(t, "host1", n1.name)
+	assert.Equal(t, uint64(2), n1.volumes)
+	assert.Equal(t, uint64(30), n1.iops)
+	assert.Equal(t, uint64(1000), n1.readTput)
+	assert.Equal(t, uint64(2000), n1.writeTput)
+	assert.Equal(t, uint64(10), n1.poolUsed)
+
+	assert.Equal(t, "host2", n2.name)
+	assert.Equal(t, uint64(2), n2.volumes)
+	assert.Equal(t, uint64(5), n2.iops)
+	assert.Equal(t, uint64(50), n2.poolUsed)
+	assert.Equal(t, uint64(200), n2.poolTotal)
+
+	// The graphs show the total of the cluster and the average CPU
+	iops, _ := nsd.GetGraphData(0)
+	assert.Equal(t, 35.0, iops)
+	cpu, _ := nsd.GetGraphData(3)
+	assert.Equal(t, 20.0, cpu)
+	poolUsed, _ := nsd.GetGraphData(4)
+	assert.Equal(t, 60.0, poolUsed)
+
+	r := rows(t, nsd)
+	assert.Len(t, r, 2)
+	assert.Equal(t, "host1", r[0][NODE_NAME])
+	assert.Equal(t, "30", r[0][IOPS])
+}
+
+func TestNodeStatsSort(t *testing.T) {
+	nsd := NewNodeStats(newFakeNodeStatsPxOps(), nil).(*nodeStatsData)
+
+	nsd.SetSortInfo("Pool Used", false)
+	assert.NoError(t, nsd.Refresh())
+	assert.Equal(t, "host2", nsd.curStats[0].name)
+
+	nsd.SetSortInfo("*CPU", true)
+	assert.NoError(t, nsd.Refresh())
+	assert.Equal(t, "host1", nsd.curStats[0].name)
+	column, ascending := nsd.GetSortInfo()
+	assert.Equal(t, "CPU", column)
+	assert.True(t, ascending)
+
+	a := &nodeStatsRow{name: "a", volumes: 2}
+	b := &nodeStatsRow{name: "b", volumes: 1}
+	nsd.SetSortInfo("Volumes", true)
+	assert.True(t, nsd.less(b, a))
+	nsd.SetSortInfo("Name", true)
+	assert.True(t, nsd.less(a, b))
+}
@@ -79,7 +79,7 @@ func describePvcExec(cmd *cobra.Command, args []string) error {
 	}
 
 	// Print details and return any errors found during parsing
-	return util.PrintFormatted(pdf)
+	return util.PrintFormatted(cmd.Context(), pdf)
 }
 
 type pvcDescribeFormatter struct {
@@ -92,7 +92,7 @@ func NewPvcDescribeFormatter(cliOps cliops.CliOps, pvcNames []string) (*pvcDescr
 	vcf := volume.NewVolumeDescribeFormatter(cliOps)
 
 	// Get namespace
-	ns, _, err := config.KM().Namespace()
+	ns, _, err := config.CMFromContext(cliOps.CliInputs().Context()).KM().Namespace()
 	if err != nil {
 		return nil, err
 	}
@@ -16,14 +16,9 @@ limitations under the License.
 package pvc
 
 import (
-	"bytes"
 	"fmt"
-	"math/big"
 	"strings"
-	"text/tabwriter"
 
-	"github.com/cheynewallace/tabby"
-	humanize "github.com/dustin/go-humanize"
 	"github.com/portworx/pxc/pkg/cliops"
 	"github.com/portworx/pxc/pkg/commander"
 	"github.com/portworx/pxc/pkg/config"
@@ -58,7 +53,12 @@ var _ = commander.RegisterCommandVar(func() {
 var _ = commander.RegisterCommandInit(func() {
 	PvcAddCommand(getPvcCmd)
 	getPvcCmd.Flags().Bool("all-namespaces", false, "Kubernetes namespace")
-	getPvcCmd.Flags().StringP("output", "o", "", "Output in yaml|json|wide")
+	getPvcCmd.Flags().StringP("output", "o", "", "Output in yaml|json|wide|csv|markdown|name|custom-columns=<spec>|jsonpath=<template>|go-template=<template>")
+	getPvcCmd.Flags().Bool("no-headers", false, "Do not print the headers of tables")
+	getPvcCmd.Flags().Bool("raw-units", false, "Show sizes in bytes instead of humanized")
+	getPvcCmd.Flags().String("sort-by", "", "Sort on a field like size, used, ha, created, node or status of the volume. Prefix with - to sort in descending order")
+	getPvcCmd.Flags().String("field-selector", "", "Select PVCs on fields of their volume, comma-separated field<operator>value like status=down,ha<3,attached=false")
+	getPvcCmd.Flags().Int("limit", 0, "Maximum number of PVCs shown. 0 shows all the PVCs")
 	getPvcCmd.Flags().Bool("show-labels", false, "Show labels in the last column of the output")
 })
 
@@ -83,7 +83,7 @@ func getPvcExec(cmd *cobra.Command, args []string) error {
 	}
 
 	// Print the details and return errors if any
-	return util.PrintFormatted(pgf)
+	return util.PrintFormatted(cmd.Context(), pgf)
 }
 
 type pvcGetFormatter struct {
@@ -96,7 +96,7 @@ type pvcGetFormatter struct {
 
 func NewPvcGetFormatter(cliOps cliops.CliOps) (*pvcGetFormatter, error) {
 	// Get namespace
-	ns, _, err := config.KM().Namespace()
+	ns, _, err := config.CMFromContext(cliOps.CliInputs().Context()).KM().Namespace()
 	if err != nil {
 		return nil, err
 	}
@@ -113,7 +113,7 @@ func NewPvcGetFormatter(cliOps cliops.CliOps) (*pvcGetFormatter, error) {
 		pvcNames: cliOps.CliInputs().Args,
 		pvcs:     pvcs,
 	}
-	p.FormatType = cliOps.CliInputs().FormatType
+	p.BaseFormatOutput = cliOps.CliInputs().BaseFormatOutput
 	return p, nil
 }
 
@@ -142,12 +142,30 @@ func filterPxPvcs(
 	return filtered, nil
 }
 
-func (p *pvcGetFormatter) getPvcs() ([]*v1.PersistentVolumeClaim, error) {
+// getPxPvcs returns the named PVCs selected, sorted and limited by the list
+// options
+func (p *pvcGetFormatter) getPxPvcs() ([]*kubernetes.PxPvc, error) {
 	allPxPvcs, err := p.pvcs.GetPxPvcs()
 	if err != nil {
-		return make([]*v1.PersistentVolumeClaim, 0), err
+		return nil, err
 	}
 	pxpvcs, err := filterPxPvcs(allPxPvcs, p.pvcNames)
+	if err != nil {
+		return nil, err
+	}
+	opts := &p.cliOps.CliInputs().ListOptions
+	if opts.IsEmpty() {
+		return pxpvcs, nil
+	}
+	nodes, err := portworx.NewNodesForPxPvcs(p.cliOps.PxOps(), pxpvcs)
+	if err != nil {
+		return nil, err
+	}
+	return portworx.SelectPxPvcs(pxpvcs, nodes, opts)
+}
+
+func (p *pvcGetFormatter) getPvcs() ([]*v1.PersistentVolumeClaim, error) {
+	pxpvcs, err := p.getPxPvcs()
 	if err != nil {
 		return make([]*v1.PersistentVolumeClaim, 0), err
 	}
@@ -187,42 +205,43 @@ func (p *pvcGetFormatter) DefaultFormat() (string, error) {
 	return p.toTabbed()
 }
 
-func (p *pvcGetFormatter) toTabbed() (string, error) {
-	var b bytes.Buffer
-	writer := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
-	t := tabby.NewCustom(writer)
-
-	allPvcs, err := p.pvcs.GetPxPvcs()
+// TableFormat returns the table of the persistent volume claims
+func (p *pvcGetFormatter) TableFormat() (*util.Table, error) {
+	pvcs, err := p.getPxPvcs()
 	if err != nil {
-		return "", err
-	}
-	pvcs, err := filterPxPvcs(allPvcs, p.pvcNames)
-	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if len(pvcs) == 0 {
 		util.Printf("No resources found\n")
-		return "", nil
+		return nil, nil
 	}
 
 	p.nodes, err = portworx.NewNodesForPxPvcs(p.cliOps.PxOps(), pvcs)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
+	t := &util.Table{}
 	// Start the columns
 	t.AddHeader(p.getHeader()...)
 	for _, n := range pvcs {
 		l, err := p.getLine(n)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		t.AddLine(l...)
 	}
-	t.Print()
 
-	return b.String(), err
+	return t, err
+}
+
+func (p *pvcGetFormatter) toTabbed() (string, error) {
+	t, err := p.TableFormat()
+	if err != nil || t == nil {
+		return "", err
+	}
+	return t.Tabbed(), nil
 }
 
 func (p *pvcGetFormatter) getHeader() []interface{} {
@@ -255,7 +274,7 @@ func (p *pvcGetFormatter) getLine(pxpvc *kubernetes.PxPvc) ([]interface{}, error
 	if err != nil {
 		return line, err
 	}
-	size := humanize.BigIBytes(big.NewInt(int64(spec.GetSize())))
+	size := p.FormatBytes(spec.GetSize())
 	pods := strings.Join(pxpvc.PodNames, ",")
 
 	/*
@@ -0,0 +1,202 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package report
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/portworx/pxc/pkg/commander"
+	"github.com/portworx/pxc/pkg/config"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var capacityCmd *cobra.Command
+
+var _ = commander.RegisterCommandVar(func() {
+	capacityCmd = &cobra.Command{
+		Use:   "capacity",
+		Short: "Forecast when the storage pools and the cluster will be full",
+		Long: `Record the used and total bytes of every storage pool into a local history
+file and fit a linear growth trend to the recorded samples. The forecast shows
+the number of days until each pool and the whole cluster reach 80%, 90% and
+100% of their capacity, and flags the pools which would need to be expanded.
+
+By default a single sample is recorded each time the command runs. Use
+--count and --interval to take several samples, or --record=false to only
+forecast from a previously recorded history file.`,
+		Example: `
+  # Record a sample and show the forecast
+  pxc report capacity
+
+  # Record a sample every hour for a day
+  pxc report capacity --count 24 --interval 1h
+
+  # Forecast from a history file recorded on another machine
+  pxc report capacity --record=false --history capacity.jsonl
+
+  # Flag the pools which will reach 70% within 2 weeks
+  pxc report capacity --expand-threshold 70 --expand-within 14`,
+		RunE: capacityExec,
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	ReportAddCommand(capacityCmd)
+	capacityCmd.Flags().String("history", "", "History file of the samples. Defaults to a file per cluster in the config directory")
+	capacityCmd.Flags().Bool("record", true, "Record samples of the current usage into the history file")
+	capacityCmd.Flags().Int("count", 1, "Number of samples to record")
+	capacityCmd.Flags().Duration("interval", time.Hour, "Time between samples when recording more than one")
+	capacityCmd.Flags().Int("expand-threshold", 80, "Usage percentage at which a pool is expanded")
+	capacityCmd.Flags().Int("expand-within", 30, "Flag the pools reaching the expand threshold within this number of days")
+	capacityCmd.Flags().StringP("output", "o", "", "Output in yaml|json")
+})
+
+func capacityExec(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	if output != "" && output != util.FORMAT_YAML && output != util.FORMAT_JSON {
+		return fmt.Errorf("Invalid output format %s. Valid values are yaml or json", output)
+	}
+	threshold, _ := cmd.Flags().GetInt("expand-threshold")
+	if threshold <= 0 || threshold > 100 {
+		return fmt.Errorf("The expand threshold must be between 1 and 100")
+	}
+	within, _ := cmd.Flags().GetInt("expand-within")
+
+	history, _ := cmd.Flags().GetString("history")
+	if len(history) == 0 {
+		history = defaultCapacityHistory(cmd.Context())
+	}
+
+	if record, _ := cmd.Flags().GetBool("record"); record {
+		count, _ := cmd.Flags().GetInt("count")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if err := recordCapacity(cmd.Context(), history, count, interval); err != nil {
+			return err
+		}
+	}
+
+	samples, err := portworx.ReadCapacityHistory(history)
+	if err != nil {
+		return fmt.Errorf("Failed to read capacity history: %v", err)
+	}
+	forecasts := portworx.ForecastCapacity(samples, threshold, float64(within))
+
+	switch output {
+	case util.FORMAT_JSON:
+		util.PrintJson(forecasts)
+		util.Printf("\n")
+		return nil
+	case util.FORMAT_YAML:
+		util.PrintYaml(forecasts)
+		return nil
+	}
+
+	if len(forecasts) == 0 {
+		util.Printf("No storage pools found\n")
+		return nil
+	}
+	if len(samples) < 2 || samples[0].Time.Equal(samples[len(samples)-1].Time) {
+		util.Eprintf("Not enough history to forecast the growth. Record more samples over time\n")
+	}
+	printCapacityTable(forecasts)
+	return nil
+}
+
+// defaultCapacityHistory returns the history file of the current cluster
+func defaultCapacityHistory(ctx context.Context) string {
+	cm := config.CMFromContext(ctx)
+	name := "default"
+	if c := cm.GetCurrentCluster(); c != nil && len(c.Name) != 0 {
+		name = c.Name
+	}
+	return filepath.Join(cm.GetFlags().ConfigDir, "capacity-"+name+".jsonl")
+}
+
+// recordCapacity appends count samples of the pools to the history file
+func recordCapacity(ctx context.Context, history string, count int, interval time.Duration) error {
+	pxops, err := portworx.NewPxOps(ctx)
+	if err != nil {
+		return err
+	}
+	defer pxops.Close()
+
+	nodes := portworx.NewNodes(pxops, &portworx.NodeSpec{})
+	for i := 0; i < count; i++ {
+		if i != 0 {
+			time.Sleep(interval)
+			nodes.Reset()
+		}
+		n, err := nodes.GetNodes()
+		if err != nil {
+			return err
+		}
+		s := portworx.NewCapacitySample(time.Now(), n)
+		if err := portworx.AppendCapacitySample(history, s); err != nil {
+			return fmt.Errorf("Failed to record capacity sample: %v", err)
+		}
+		if count > 1 {
+			util.Eprintf("Recorded sample %d of %d\n", i+1, count)
+		}
+	}
+	return nil
+}
+
+func daysString(days *float64) string {
+	if days == nil {
+		return "never"
+	}
+	return fmt.Sprintf("%.1f", *days)
+}
+
+func printCapacityTable(forecasts []*portworx.CapacityForecast) {
+	header := []interface{}{"Pool", "Used", "Total", "Usage", "Growth/Day"}
+	for _, t := range portworx.CapacityThresholds {
+		header = append(header, fmt.Sprintf("Days To %d%%", t))
+	}
+	header = append(header, "Expand")
+
+	t := util.NewTabby()
+	t.AddHeader(header...)
+	for _, f := range forecasts {
+		usage := 0.0
+		if f.Total != 0 {
+			usage = float64(f.Used) * 100 / float64(f.Total)
+		}
+		growth := "0 B"
+		if f.GrowthPerDay > 0 {
+			growth = bytesString(uint64(f.GrowthPerDay))
+		} else if f.GrowthPerDay < 0 {
+			growth = "-" + bytesString(uint64(-f.GrowthPerDay))
+		}
+		line := []interface{}{f.Name, bytesString(f.Used), bytesString(f.Total),
+			fmt.Sprintf("%.1f%%", usage), growth}
+		for _, d := range f.DaysUntil {
+			line = append(line, daysString(d))
+		}
+		expand := ""
+		if f.Expand {
+			expand = "yes"
+		}
+		line = append(line, expand)
+		t.AddLine(line...)
+	}
+	t.Print()
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/portworx/pxc/pkg/cliops"
+	"github.com/portworx/pxc/pkg/commander"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+const formatName = "name"
+
+var orphansCmd *cobra.Command
+
+var _ = commander.RegisterCommandVar(func() {
+	orphansCmd = &cobra.Command{
+		Use:   "orphans",
+		Short: "Find volumes, snapshots and PVCs which may no longer be needed",
+		Long: `Find volumes which are not used by a PVC, volumes which have been detached
+for a long time, snapshots whose parent volume was deleted and PVCs whose
+Portworx volume no longer exists.
+
+PVCs are only checked when pxc runs as a kubectl plugin. Use -o name to get
+the names of the orphaned volumes and snapshots, which can be reviewed and
+then deleted with pxc volume delete -.`,
+		Example: `
+  # Show the orphaned volumes, snapshots and PVCs
+  pxc report orphans
+
+  # Only report volumes detached for more than 30 days
+  pxc report orphans --stale-days 30
+
+  # Delete the orphaned volumes and snapshots after reviewing them
+  pxc report orphans -o name > orphans.txt
+  pxc volume delete - < orphans.txt`,
+		RunE: orphansExec,
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	ReportAddCommand(orphansCmd)
+	orphansCmd.Flags().Int("stale-days", 90, "Report volumes detached for more than this number of days. Use 0 to disable")
+	orphansCmd.Flags().StringP("output", "o", "", "Output in yaml|json|name")
+})
+
+func orphansExec(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	switch output {
+	case "", util.FORMAT_YAML, util.FORMAT_JSON, formatName:
+	default:
+		return fmt.Errorf("Invalid output format %s. Valid values are yaml, json or name", output)
+	}
+	staleDays, _ := cmd.Flags().GetInt("stale-days")
+
+	cliOps := cliops.NewCliOps(cliops.NewCliInputs(cmd, args))
+	if err := cliOps.Connect(); err != nil {
+		return err
+	}
+	defer cliOps.Close()
+
+	vols, err := portworx.NewVolumes(cliOps.PxOps(), &portworx.VolumeSpec{}).GetVolumes()
+	if err != nil {
+		return err
+	}
+
+	spec := &portworx.OrphanSpec{
+		CheckPvcs:  util.InKubectlPluginMode(),
+		StaleAfter: time.Duration(staleDays) * 24 * time.Hour,
+		Now:        time.Now(),
+	}
+	if spec.CheckPvcs {
+		spec.Pvcs, err = cliOps.COps().GetPvcsByLabels("", "")
+		if err != nil {
+			return err
+		}
+		spec.Pvs, err = cliOps.COps().GetPvsByLabels("")
+		if err != nil {
+			return err
+		}
+	} else {
+		util.Eprintf("PVCs are not checked when pxc is not running as a kubectl plugin\n")
+	}
+
+	orphans := portworx.FindOrphans(vols, spec)
+	switch output {
+	case util.FORMAT_JSON:
+		util.PrintJson(orphans)
+		util.Printf("\n")
+	case util.FORMAT_YAML:
+		util.PrintYaml(orphans)
+	case formatName:
+		for _, o := range orphans {
+			if o.Kind != portworx.OrphanKindPvc {
+				util.Printf("%s\n", o.Name)
+			}
+		}
+	default:
+		if len(orphans) == 0 {
+			util.Printf("No orphans found\n")
+			return nil
+		}
+		t := util.NewTabby()
+		t.AddHeader("Kind", "Name", "Volume Id", "Reason")
+		for _, o := range orphans {
+			name := o.Name
+			if len(o.Namespace) != 0 {
+				name = o.Namespace + "/" + o.Name
+			}
+			t.AddLine(o.Kind, name, o.VolumeId, strings.Join(o.Reasons, ", "))
+		}
+		t.Print()
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package report
+
+import (
+	"fmt"
+	"math/big"
+
+	humanize "github.com/dustin/go-humanize"
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/cliops"
+	"github.com/portworx/pxc/pkg/commander"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var usageCmd *cobra.Command
+
+var _ = commander.RegisterCommandVar(func() {
+	usageCmd = &cobra.Command{
+		Use:   "usage",
+		Short: "Show the storage used per namespace, owner or label",
+		Long: `Show the provisioned and used bytes, the number of volumes and snapshots,
+and the bytes used by snapshots per Kubernetes namespace, volume owner or
+label value. Snapshots are accounted in the group of their parent volume.`,
+		Example: `
+  # Show the usage per Kubernetes namespace
+  pxc report usage
+
+  # Show the 5 owners using the most storage
+  pxc report usage --by owner --top 5
+
+  # Show the usage per value of the label team as csv
+  pxc report usage --by label=team -o csv`,
+		RunE: usageExec,
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	ReportAddCommand(usageCmd)
+	usageCmd.Flags().String("by", portworx.UsageByNamespace, "Group the usage by namespace, owner or label=KEY")
+	usageCmd.Flags().Int("top", 0, "Only show the N groups using the most storage")
+	usageCmd.Flags().StringP("output", "o", "", "Output in csv|json")
+	usageCmd.Flags().Bool("no-headers", false, "Do not print the headers of tables")
+})
+
+func usageExec(cmd *cobra.Command, args []string) error {
+	by, _ := cmd.Flags().GetString("by")
+	labelKey, err := portworx.ParseUsageBy(by)
+	if err != nil {
+		return err
+	}
+	output, _ := cmd.Flags().GetString("output")
+	if output != "" && output != util.FORMAT_CSV && output != util.FORMAT_JSON {
+		return fmt.Errorf("Invalid output format %s. Valid values are csv or json", output)
+	}
+
+	cliOps := cliops.NewCliOps(cliops.NewCliInputs(cmd, args))
+	if err := cliOps.Connect(); err != nil {
+		return err
+	}
+	defer cliOps.Close()
+
+	vols, err := portworx.NewVolumes(cliOps.PxOps(), &portworx.VolumeSpec{}).GetVolumes()
+	if err != nil {
+		return err
+	}
+
+	var groupOf func(v *api.Volume) string
+	switch by {
+	case portworx.UsageByNamespace:
+		pxpvcs, err := portworx.NewPvcs(cliOps.PxOps(), cliOps.COps(), &portworx.PvcSpec{}).GetPxPvcs()
+		if err != nil {
+			return err
+		}
+		groupOf = portworx.UsageGroupByNamespace(pxpvcs)
+	case portworx.UsageByOwner:
+		groupOf = portworx.UsageGroupByOwner
+	default:
+		groupOf = portworx.UsageGroupByLabel(labelKey)
+	}
+
+	report := portworx.NewUsageReport(by, vols, groupOf)
+	top, _ := cmd.Flags().GetInt("top")
+	report.Top(top)
+
+	switch output {
+	case util.FORMAT_JSON:
+		util.PrintJson(report)
+		util.Printf("\n")
+		return nil
+	case util.FORMAT_CSV:
+		noHeaders, _ := cmd.Flags().GetBool("no-headers")
+		return printUsageCsv(report, noHeaders)
+	}
+	printUsageTable(report)
+	return nil
+}
+
+func bytesString(b uint64) string {
+	return humanize.BigIBytes(big.NewInt(int64(b)))
+}
+
+func printUsageTable(report *portworx.UsageReport) {
+	t := util.NewTabby()
+	t.AddHeader(report.By, "Volumes", "Snapshots", "Provisioned", "Used", "Snapshot Usage")
+	for _, u := range append(report.Groups, report.Total) {
+		t.AddLine(u.Name, u.Volumes, u.Snapshots,
+			bytesString(u.ProvisionedBytes), bytesString(u.UsedBytes), bytesString(u.SnapshotBytes))
+	}
+	t.Print()
+}
+
+// printUsageCsv prints the usage in bytes as csv. The total is not printed
+// so that the rows can be added up.
+func printUsageCsv(report *portworx.UsageReport, noHeaders bool) error {
+	t := &util.Table{}
+	t.AddHeader(report.By, "volumes", "snapshots", "provisioned_bytes", "used_bytes", "snapshot_bytes")
+	for _, u := range report.Groups {
+		t.AddLine(u.Name, u.Volumes, u.Snapshots, u.ProvisionedBytes, u.UsedBytes, u.SnapshotBytes)
+	}
+	s, err := t.Csv(noHeaders)
+	if err != nil {
+		return err
+	}
+	util.Printf("%s\n", s)
+	return nil
+}
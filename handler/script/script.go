@@ -120,13 +120,14 @@ func runScriptExec(cmd *cobra.Command, args []string) error {
 func pythonScriptExec(cmd *cobra.Command, args []string) error {
 
 	// Setup a connetion to Portworx
-	clusterInfo := config.CM().GetCurrentCluster()
-	authInfo := config.CM().GetCurrentAuthInfo()
+	cm := config.CMFromContext(cmd.Context())
+	clusterInfo := cm.GetCurrentCluster()
+	authInfo := cm.GetCurrentAuthInfo()
 
 	// Check if we need to tunnel
-	if len(config.CM().GetEndpoint()) == 0 &&
+	if len(cm.GetEndpoint()) == 0 &&
 		util.InKubectlPluginMode() {
-		err := kubernetes.StartTunnel()
+		err := kubernetes.StartTunnel(cmd.Context())
 		if err != nil {
 			return err
 		}
@@ -135,7 +136,7 @@ func pythonScriptExec(cmd *cobra.Command, args []string) error {
 	logrus.Infof("args: %+v", args)
 	scriptCmd := exec.Command("python3", args...)
 	scriptCmd.Env = append(os.Environ(),
-		EvEndpoint+"="+config.CM().GetEndpoint(),
+		EvEndpoint+"="+cm.GetEndpoint(),
 		EvCAFile+"="+clusterInfo.CACert,
 	)
 
@@ -0,0 +1,156 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package volume
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/portworx/pxc/pkg/cliops"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// bulkStdin is the name used to read the names of the volumes from stdin
+	bulkStdin = "-"
+
+	defaultBulkWorkers          = 4
+	defaultBulkConfirmThreshold = 10
+)
+
+// addBulkFlags adds the flags to run the command on multiple volumes
+func addBulkFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("selector", "l", "", "Run on the volumes matching the selector (label query) comma-separated name=value pairs")
+	cmd.Flags().String("owner", "", "Run on the volumes owned by the owner")
+	cmd.Flags().Int("workers", defaultBulkWorkers, "Number of volumes processed at the same time")
+	cmd.Flags().Bool("continue-on-error", false, "Continue with the rest of the volumes when one fails")
+	cmd.Flags().Bool("yes", false, "Do not ask for confirmation")
+	cmd.Flags().Int("confirm-threshold", defaultBulkConfirmThreshold,
+		"Ask for confirmation when more than this number of volumes are affected")
+}
+
+// isBulk returns true when the command must run on the volumes matching
+// a selector or owner, or on the names read from stdin
+func isBulk(cmd *cobra.Command, args []string) bool {
+	selector, _ := cmd.Flags().GetString("selector")
+	owner, _ := cmd.Flags().GetString("owner")
+	return len(selector) != 0 || len(owner) != 0 ||
+		(len(args) == 1 && args[0] == bulkStdin)
+}
+
+// validateBulkArgs checks that volume names are not provided with a
+// selector or owner
+func validateBulkArgs(cmd *cobra.Command, args []string) error {
+	owner, _ := cmd.Flags().GetString("owner")
+	if len(owner) != 0 && len(args) != 0 {
+		return fmt.Errorf("Volume names cannot be provided when an owner is specified")
+	}
+	return cliops.ValidateCliInput(cmd, args)
+}
+
+// getBulkVolumeNames returns the names of the volumes from stdin or
+// matching the selector and owner
+func getBulkVolumeNames(
+	cmd *cobra.Command,
+	args []string,
+	pxops portworx.PxOps,
+) ([]string, error) {
+	if len(args) == 1 && args[0] == bulkStdin {
+		data, err := ioutil.ReadAll(util.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read volume names from stdin: %v", err)
+		}
+		return strings.Fields(string(data)), nil
+	}
+
+	cvi := cliops.NewCliInputs(cmd, args)
+	vols, err := portworx.NewVolumes(pxops, &portworx.VolumeSpec{
+		Labels: cvi.Labels,
+		Owner:  cvi.Owner,
+	}).GetVolumes()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(vols))
+	for _, vol := range vols {
+		names = append(names, vol.GetLocator().GetName())
+	}
+	return names, nil
+}
+
+// confirmBulk returns true if the command must run on the volumes. The user
+// is asked for confirmation, unless --yes was provided, when more volumes
+// than the threshold are affected.
+func confirmBulk(cmd *cobra.Command, action string, names []string) (bool, error) {
+	if len(names) == 0 {
+		util.Printf("No volumes found\n")
+		return false, nil
+	}
+
+	yes, _ := cmd.Flags().GetBool("yes")
+	threshold, _ := cmd.Flags().GetInt("confirm-threshold")
+	if len(names) <= threshold {
+		return true, nil
+	}
+	return util.ConfirmVolumes(fmt.Sprintf("%s %d volumes?", action, len(names)), len(names), yes)
+}
+
+// runBulk calls f for each volume using the worker pool and prints the
+// result of each volume. f returns the result to show for the volume.
+func runBulk(
+	cmd *cobra.Command,
+	action string,
+	names []string,
+	f func(name string) (string, error),
+) error {
+	workers, _ := cmd.Flags().GetInt("workers")
+	continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+	results := make([]string, len(names))
+	errs := util.ForEach(len(names), workers, continueOnError, func(i int) error {
+		var err error
+		results[i], err = f(names[i])
+		return err
+	})
+
+	failed, skipped := 0, 0
+	t := util.NewTabby()
+	t.AddHeader("Volume", "Result")
+	for i, name := range names {
+		switch {
+		case errs[i] == util.ErrNotStarted:
+			skipped++
+			t.AddLine(name, "Skipped")
+		case errs[i] != nil:
+			failed++
+			t.AddLine(name, "Failed: "+util.PxError(errs[i]).Error())
+		default:
+			t.AddLine(name, results[i])
+		}
+	}
+	t.Print()
+
+	util.Printf("\n%d succeeded, %d failed, %d skipped\n",
+		len(names)-failed-skipped, failed, skipped)
+	if failed != 0 {
+		return fmt.Errorf("Failed to %s %d of %d volumes",
+			strings.ToLower(action), failed, len(names))
+	}
+	return nil
+}
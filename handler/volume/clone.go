@@ -46,6 +46,7 @@ var _ = commander.RegisterCommandVar(func() {
 
 var _ = commander.RegisterCommandInit(func() {
 	VolumeAddCommand(volumeCloneCmd)
+	addWaitFlags(volumeCloneCmd, "the clone is up")
 })
 
 func VolumeCloneAddCommand(cmd *cobra.Command) {
@@ -53,7 +54,7 @@ func VolumeCloneAddCommand(cmd *cobra.Command) {
 }
 
 func volumeCloneExec(cmd *cobra.Command, args []string) error {
-	ctx, conn, err := portworx.PxConnectDefault()
+	ctx, conn, err := portworx.PxConnectDefault(cmd.Context())
 	if err != nil {
 		return err
 	}
@@ -74,6 +75,11 @@ func volumeCloneExec(cmd *cobra.Command, args []string) error {
 		return util.PxErrorMessage(err, "Failed to create clone")
 	}
 
+	err = waitIfRequested(cmd, name, portworx.NewVolumeStatusCondition(portworx.VolumeStatusUp))
+	if err != nil {
+		return err
+	}
+
 	msg := fmt.Sprintf("Clone of %s created with id %s",
 		ccReq.GetParentId(),
 		resp.GetVolumeId())
@@ -83,5 +89,5 @@ func volumeCloneExec(cmd *cobra.Command, args []string) error {
 		Desc: msg,
 		Id:   []string{resp.GetVolumeId()},
 	}
-	return util.PrintFormatted(formattedOut)
+	return util.PrintFormatted(cmd.Context(), formattedOut)
 }
@@ -16,16 +16,19 @@ limitations under the License.
 package volume
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 
+	"github.com/golang/protobuf/proto"
 	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
 	"github.com/portworx/pxc/pkg/commander"
-	sched "github.com/portworx/pxc/pkg/openstorage/sched"
 	"github.com/portworx/pxc/pkg/portworx"
 	"github.com/portworx/pxc/pkg/util"
 	"github.com/spf13/cobra"
+
+	"google.golang.org/grpc"
 )
 
 type createVolumeOpts struct {
@@ -40,11 +43,8 @@ type createVolumeOpts struct {
 	earlyAck           bool
 	asyncIo            bool
 	passPhrase         string
-	periodic           string
-	daily              []string
-	weekly             []string
-	monthly            []string
-	policy             string
+	schedule           portworx.SnapshotScheduleOpts
+	filename           string
 }
 
 var (
@@ -80,9 +80,19 @@ var _ = commander.RegisterCommandVar(func() {
   pxc volume create snapvol --weekly monday@00:12,2
 
   # Create a volume with monthly snapshot on 25th of every month at 10h:10m with retain=2 (maintaing two snapshot copies at a given time):
-  pxc volume create snapvol --monthly 25@10:10,2`,
+  pxc volume create snapvol --monthly 25@10:10,2
+
+  # Create the volumes in a manifest exported with pxc volume export:
+  pxc volume create -f volumes.yaml`,
 
 		Args: func(cmd *cobra.Command, args []string) error {
+			filename, _ := cmd.Flags().GetString("filename")
+			if len(filename) != 0 {
+				if len(args) != 0 {
+					return fmt.Errorf("Volume names cannot be supplied with --filename")
+				}
+				return nil
+			}
 			if len(args) < 1 {
 				return fmt.Errorf("Must supply a name for volume")
 			}
@@ -116,189 +126,192 @@ var _ = commander.RegisterCommandInit(func() {
 	createVolumeCmd.Flags().BoolVar(&cvOpts.req.Spec.GroupEnforced, "group-enforced", false, "Enforce group during provision")
 	createVolumeCmd.Flags().Uint32Var(&cvOpts.req.Spec.Scale, "scale", 1, "auto scale to max number (Valid Range: [1 1024])")
 	createVolumeCmd.Flags().StringVar(&cvOpts.passPhrase, "passphrase", "", "Passphrase for an encrypted volume")
-	createVolumeCmd.Flags().StringVar(&cvOpts.periodic, "periodic", "", "periodic snapshot interval in mins,k (keeps 5 by default), 0 disables all schedule snapshots")
-	createVolumeCmd.Flags().StringSliceVar(&cvOpts.daily, "daily", []string{}, "daily snapshot at specified hh:mm,k (keeps 7 by default)")
-	createVolumeCmd.Flags().StringSliceVar(&cvOpts.weekly, "weekly", []string{}, "weekly snapshot at specified weekday@hh:mm,k (keeps 5 by default)")
-	createVolumeCmd.Flags().StringSliceVar(&cvOpts.monthly, "monthly", []string{}, "monthly snapshot at specified day@hh:mm,k (keeps 12 by default)")
-	createVolumeCmd.Flags().StringVar(&cvOpts.policy, "policy", "", "Schedule policy names separated by comma")
+	createVolumeCmd.Flags().StringVar(&cvOpts.schedule.Periodic, "periodic", "", "periodic snapshot interval in mins,k (keeps 5 by default), 0 disables all schedule snapshots")
+	createVolumeCmd.Flags().StringSliceVar(&cvOpts.schedule.Daily, "daily", []string{}, "daily snapshot at specified hh:mm,k (keeps 7 by default)")
+	createVolumeCmd.Flags().StringSliceVar(&cvOpts.schedule.Weekly, "weekly", []string{}, "weekly snapshot at specified weekday@hh:mm,k (keeps 5 by default)")
+	createVolumeCmd.Flags().StringSliceVar(&cvOpts.schedule.Monthly, "monthly", []string{}, "monthly snapshot at specified day@hh:mm,k (keeps 12 by default)")
+	createVolumeCmd.Flags().StringVar(&cvOpts.schedule.Policy, "policy", "", "Schedule policy names separated by comma")
+	addWaitFlags(createVolumeCmd, "the volume is up")
+	createVolumeCmd.Flags().StringVarP(&cvOpts.filename, "filename", "f", "", "Create the volumes in a manifest file instead. Use - to read from stdin")
 
 	createVolumeCmd.Flags().SortFlags = false
 })
 
 func createVolumeExec(c *cobra.Command, args []string) error {
-	ctx, conn, err := portworx.PxConnectDefault()
+	ctx, conn, err := portworx.PxConnectDefault(c.Context())
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
 
+	if len(cvOpts.filename) != 0 {
+		return createVolumesFromManifest(ctx, c, conn, cvOpts.filename)
+	}
+
+	// The options are shared when the command runs in several contexts
+	opts := *cvOpts
+	opts.req = proto.Clone(cvOpts.req).(*api.SdkVolumeCreateRequest)
+
 	// Get name
-	cvOpts.req.Name = args[0]
+	opts.req.Name = args[0]
+
+	if err := buildCreateRequest(&opts); err != nil {
+		return err
+	}
+
+	// Send request
+	id, err := portworx.CreateVolume(ctx, conn, opts.req)
+	if err != nil {
+		return util.PxErrorMessage(err, "Failed Create a volume")
+	}
 
+	err = waitIfRequested(c, opts.req.GetName(), portworx.NewVolumeStatusCondition(portworx.VolumeStatusUp))
+	if err != nil {
+		return err
+	}
+
+	// Show user information
+	msg := fmt.Sprintf("Volume %s created with id %s\n",
+		opts.req.GetName(),
+		id)
+
+	formattedOut := &util.DefaultFormatOutput{
+		Cmd:  "create volume",
+		Desc: msg,
+		Id:   []string{id},
+	}
+	util.PrintFormatted(c.Context(), formattedOut)
+	return nil
+}
+
+// createVolumesFromManifest creates all the volumes in the manifest file
+func createVolumesFromManifest(
+	ctx context.Context,
+	c *cobra.Command,
+	conn *grpc.ClientConn,
+	filename string,
+) error {
+	manifest, err := portworx.ReadVolumeManifest(filename)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(manifest.Volumes))
+	msg := ""
+	for _, v := range manifest.Volumes {
+		req, err := v.CreateRequest()
+		if err != nil {
+			return err
+		}
+		id, err := portworx.CreateVolume(ctx, conn, req)
+		if err != nil {
+			return util.PxErrorMessagef(err, "Failed to create volume %s", v.Name)
+		}
+		ids = append(ids, id)
+		msg += fmt.Sprintf("Volume %s created with id %s\n", v.Name, id)
+	}
+
+	for _, v := range manifest.Volumes {
+		err = waitIfRequested(c, v.Name, portworx.NewVolumeStatusCondition(portworx.VolumeStatusUp))
+		if err != nil {
+			return err
+		}
+	}
+
+	formattedOut := &util.DefaultFormatOutput{
+		Cmd:  "create volume",
+		Desc: msg,
+		Id:   ids,
+	}
+	return util.PrintFormatted(c.Context(), formattedOut)
+}
+
+// buildCreateRequest sets the values of the create request from the
+// options provided in the command line
+func buildCreateRequest(opts *createVolumeOpts) error {
 	// Get labels
-	if len(cvOpts.labelsAsString) != 0 {
+	if len(opts.labelsAsString) != 0 {
 		var err error
-		cvOpts.req.Labels, err = util.CommaStringToStringMap(cvOpts.labelsAsString)
+		opts.req.Labels, err = util.CommaStringToStringMap(opts.labelsAsString)
 		if err != nil {
 			return fmt.Errorf("Failed to parse labels: %v\n", err)
 		}
 	}
 
-	if len(cvOpts.collaborators) != 0 || len(cvOpts.groups) != 0 {
-		cvOpts.req.Spec.Ownership = &api.Ownership{}
-		cvOpts.req.Spec.Ownership.Acls = &api.Ownership_AccessControl{}
+	if len(opts.collaborators) != 0 || len(opts.groups) != 0 {
+		opts.req.Spec.Ownership = &api.Ownership{}
+		opts.req.Spec.Ownership.Acls = &api.Ownership_AccessControl{}
 	}
 	// Get collaborators
-	if len(cvOpts.collaborators) != 0 {
-		collaborators, err := util.GetAclMapFromString(cvOpts.collaborators)
+	if len(opts.collaborators) != 0 {
+		collaborators, err := util.GetAclMapFromString(opts.collaborators)
 		if err != nil {
 			return err
 		}
-		cvOpts.req.Spec.Ownership.Acls.Collaborators = collaborators
+		opts.req.Spec.Ownership.Acls.Collaborators = collaborators
 	}
 
 	// Get groups
-	if len(cvOpts.groups) != 0 {
-		groups, err := util.GetAclMapFromString(cvOpts.groups)
+	if len(opts.groups) != 0 {
+		groups, err := util.GetAclMapFromString(opts.groups)
 		if err != nil {
 			return err
 		}
-		cvOpts.req.Spec.Ownership.Acls.Groups = groups
+		opts.req.Spec.Ownership.Acls.Groups = groups
 	}
 
 	// Convert size to bytes in uint64
-	cvOpts.req.Spec.Size = uint64(cvOpts.sizeInGi) * uint64(util.Gi)
+	opts.req.Spec.Size = uint64(opts.sizeInGi) * uint64(util.Gi)
 
 	// Add fs to request
-	switch {
-	case cvOpts.filesystemAsString == "ext4":
-		cvOpts.req.Spec.Format = api.FSType_FS_TYPE_EXT4
-	case cvOpts.filesystemAsString == "none":
-		cvOpts.req.Spec.Format = api.FSType_FS_TYPE_NONE
-	default:
+	fs, err := portworx.GetFSType(opts.filesystemAsString)
+	if err != nil {
 		return fmt.Errorf("Error: --fs valid values are [none, ext4]\n")
 	}
-
-	// Update default EXT4, if it fs is 'none' and shared volume
-	if cvOpts.req.Spec.Format == api.FSType_FS_TYPE_NONE && cvOpts.req.Spec.Shared {
-		cvOpts.req.Spec.Format = api.FSType_FS_TYPE_EXT4
-	}
+	opts.req.Spec.Format = fs
 
 	// setting replica set nodes if provided
-	if len(cvOpts.replicaSet) != 0 {
-		cvOpts.req.Spec.ReplicaSet = &api.ReplicaSet{
-			Nodes: cvOpts.replicaSet,
+	if len(opts.replicaSet) != 0 {
+		opts.req.Spec.ReplicaSet = &api.ReplicaSet{
+			Nodes: opts.replicaSet,
 		}
 	}
 
 	// setting IO profile if provided.
-	if len(cvOpts.IoProfile) > 0 {
-		switch cvOpts.IoProfile {
-		case "db":
-			cvOpts.req.Spec.IoProfile = api.IoProfile_IO_PROFILE_DB
-		case "cms":
-			cvOpts.req.Spec.IoProfile = api.IoProfile_IO_PROFILE_CMS
-		case "db_remote":
-			cvOpts.req.Spec.IoProfile = api.IoProfile_IO_PROFILE_DB_REMOTE
-		case "sync_shared":
-			cvOpts.req.Spec.IoProfile = api.IoProfile_IO_PROFILE_SYNC_SHARED
-		case "sequential":
-			cvOpts.req.Spec.IoProfile = api.IoProfile_IO_PROFILE_SEQUENTIAL
-		default:
-			flagError := errors.New("Invalid IO profile")
-			return flagError
+	if len(opts.IoProfile) > 0 {
+		ioProfile, err := portworx.GetIoProfile(opts.IoProfile)
+		if err != nil {
+			return errors.New("Invalid IO profile")
 		}
-	}
-
-	if cvOpts.req.Spec.QueueDepth < 1 || cvOpts.req.Spec.QueueDepth > 256 {
-		return fmt.Errorf("Queuedepth has to be in the range of 1 to 256.")
+		opts.req.Spec.IoProfile = ioProfile
 	}
 
 	// Setting Iostrategy
 	ioStrategy := &api.IoStrategy{
-		AsyncIo:  cvOpts.asyncIo,
-		EarlyAck: cvOpts.earlyAck,
+		AsyncIo:  opts.asyncIo,
+		EarlyAck: opts.earlyAck,
 	}
-	cvOpts.req.Spec.IoStrategy = ioStrategy
+	opts.req.Spec.IoStrategy = ioStrategy
 
 	// Setting passphrase
-	if len(cvOpts.passPhrase) != 0 {
-		cvOpts.req.Spec.Passphrase = cvOpts.passPhrase
+	if len(opts.passPhrase) != 0 {
+		opts.req.Spec.Passphrase = opts.passPhrase
 	}
 	// Checking if snapshot schedule is set
-	schedule, snapErr := makeSnapSchedule(cvOpts)
+	schedule, snapErr := makeSnapSchedule(&opts.schedule)
 	if snapErr != nil {
 		return snapErr
 	}
-	cvOpts.req.Spec.SnapshotSchedule = schedule
-
-	// Send request
-	volumes := api.NewOpenStorageVolumeClient(conn)
-	resp, err := volumes.Create(ctx, cvOpts.req)
-	if err != nil {
-		return util.PxErrorMessage(err, "Failed Create a volume")
-	}
-
-	// Show user information
-	msg := fmt.Sprintf("Volume %s created with id %s\n",
-		cvOpts.req.GetName(),
-		resp.GetVolumeId())
+	opts.req.Spec.SnapshotSchedule = schedule
 
-	formattedOut := &util.DefaultFormatOutput{
-		Cmd:  "create volume",
-		Desc: msg,
-		Id:   []string{resp.GetVolumeId()},
-	}
-	util.PrintFormatted(formattedOut)
-	return nil
+	return portworx.ValidateVolumeCreateRequest(opts.req)
 }
 
-func makeSnapSchedule(snapOpts *createVolumeOpts) (string, error) {
-	updates := []sched.RetainIntervalSpec{}
-	var err error
-	if len(snapOpts.periodic) > 0 {
-		s, err := sched.ParsePeriodic(snapOpts.periodic)
-		if err != nil {
-			return "", err
-		}
-		updates = append(updates, s)
-		if s.Period == 0 {
-			return sched.ScheduleString(updates, nil)
-		}
-	}
-
-	for freq, parse := range sched.ParseCLI {
-		var items []string
-		switch freq {
-		case sched.DailyType:
-			items = snapOpts.daily
-		case sched.WeeklyType:
-			items = snapOpts.weekly
-		case sched.MonthlyType:
-			items = snapOpts.monthly
-		default:
-			return "", fmt.Errorf("unknown periodicity")
-		}
-
-		// fix items if they have been split during CLI parsing due to comma in the format string.
-		items = util.FixCommaBasedStringSliceInput(items, os.Args)
-
-		for _, item := range items {
-			var s sched.RetainIntervalSpec
-			s, err = parse(item)
-			if err != nil {
-				break
-			}
-			updates = append(updates, s)
-		}
-	}
-
-	if err != nil {
-		return "", err
-	}
-	p, err := sched.NewPolicyTags(snapOpts.policy)
-	if err != nil {
-		return "", err
-	}
-	return sched.ScheduleString(updates, p)
+func makeSnapSchedule(schedule *portworx.SnapshotScheduleOpts) (string, error) {
+	// fix items if they have been split during CLI parsing due to comma in the format string.
+	s := *schedule
+	s.Daily = util.FixCommaBasedStringSliceInput(s.Daily, os.Args)
+	s.Weekly = util.FixCommaBasedStringSliceInput(s.Weekly, os.Args)
+	s.Monthly = util.FixCommaBasedStringSliceInput(s.Monthly, os.Args)
+	return s.ScheduleString()
 }
@@ -15,6 +15,7 @@
 package volume
 
 import (
+	"context"
 	"fmt"
 
 	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
@@ -33,12 +34,18 @@ var _ = commander.RegisterCommandVar(func() {
 		Short: "Delete a volume in Portworx",
 		Example: `
   # Delete the volume by name muvolume
-  pxc volume delete myvolume`,
+  pxc volume delete myvolume
+
+  # Delete all the volumes with the label ns=test, 8 at a time, without confirmation
+  pxc volume delete -l ns=test --workers 8 --yes
+
+  # Delete the volumes listed in a file, even if some fail
+  cat volumes.txt | pxc volume delete - --continue-on-error`,
 		Args: func(cmd *cobra.Command, args []string) error {
-			if len(args) < 1 {
+			if len(args) < 1 && !isBulk(cmd, args) {
 				return fmt.Errorf("Must supply a volume name")
 			}
-			return nil
+			return validateBulkArgs(cmd, args)
 		},
 		RunE: deleteVolumeExec,
 	}
@@ -46,6 +53,8 @@ var _ = commander.RegisterCommandVar(func() {
 
 var _ = commander.RegisterCommandInit(func() {
 	VolumeAddCommand(deleteVolumeCmd)
+	addWaitFlags(deleteVolumeCmd, "the volume is deleted")
+	addBulkFlags(deleteVolumeCmd)
 })
 
 func DeleteAddCommand(cmd *cobra.Command) {
@@ -53,19 +62,31 @@ func DeleteAddCommand(cmd *cobra.Command) {
 }
 
 func deleteVolumeExec(cmd *cobra.Command, args []string) error {
-	ctx, conn, err := portworx.PxConnectDefault()
+	ctx, conn, err := portworx.PxConnectDefault(cmd.Context())
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
-
-	// Send request
 	volumes := api.NewOpenStorageVolumeClient(conn)
+
+	if isBulk(cmd, args) {
+		names, err := getBulkVolumeNames(cmd, args, portworx.NewPxOpsWithConn(ctx, conn))
+		if err != nil {
+			return err
+		}
+		if ok, err := confirmBulk(cmd, "Delete", names); !ok {
+			return err
+		}
+		return runBulk(cmd, "Delete", names, func(name string) (string, error) {
+			if err := deleteVolume(ctx, cmd, volumes, name); err != nil {
+				return "", err
+			}
+			return "Deleted", nil
+		})
+	}
+
 	name := args[0]
-	_, err = volumes.Delete(ctx, &api.SdkVolumeDeleteRequest{
-		VolumeId: name,
-	})
-	if err != nil {
+	if err := deleteVolume(ctx, cmd, volumes, name); err != nil {
 		return util.PxErrorMessage(err, "Failed to delete volume")
 	}
 
@@ -76,5 +97,22 @@ func deleteVolumeExec(cmd *cobra.Command, args []string) error {
 		Desc: msg,
 		Id:   []string{name},
 	}
-	return util.PrintFormatted(formattedOut)
+	return util.PrintFormatted(cmd.Context(), formattedOut)
+}
+
+// deleteVolume deletes the volume and waits for it if --wait was provided
+func deleteVolume(
+	ctx context.Context,
+	cmd *cobra.Command,
+	volumes api.OpenStorageVolumeClient,
+	name string,
+) error {
+	_, err := volumes.Delete(ctx, &api.SdkVolumeDeleteRequest{
+		VolumeId: name,
+	})
+	if err != nil {
+		return err
+	}
+
+	return waitIfRequested(cmd, name, portworx.NewVolumeStatusCondition(portworx.VolumeStatusDeleted))
 }
@@ -87,7 +87,7 @@ func describeVolumesExec(cmd *cobra.Command, args []string) error {
 	vcf := NewVolumeDescribeFormatter(cliOps)
 
 	// Print details and return any errors found during parsing
-	return util.PrintFormatted(vcf)
+	return util.PrintFormatted(cmd.Context(), vcf)
 }
 
 type VolumeDescribeFormatter struct {
@@ -109,7 +109,7 @@ func NewVolumeDescribeFormatter(cliOps cliops.CliOps) *VolumeDescribeFormatter {
 		nodes:   portworx.NewNodes(cliOps.PxOps(), &portworx.NodeSpec{}),
 		pods:    portworx.NewPods(cliOps.COps(), &portworx.PodSpec{}),
 	}
-	d.FormatType = cliOps.CliInputs().FormatType
+	d.BaseFormatOutput = cliOps.CliInputs().BaseFormatOutput
 	return d
 }
 
@@ -196,7 +196,7 @@ func (p *VolumeDescribeFormatter) addVolumeAlerts(
 	alertInfo := &cliops.CliAlertOps{}
 	alertInfo.AlertType = "volume"
 	alertInfo.ResourceId = v.GetId()
-	alertInfo.PxAlertOps = portworx.NewPxAlertOps()
+	alertInfo.PxAlertOps = portworx.NewPxAlertOps(p.cliOps.CliInputs().Context())
 	f := alerts.NewAlertGetFormatter(alertInfo)
 
 	lines, err := f.DefaultFormat()
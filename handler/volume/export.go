@@ -0,0 +1,109 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package volume
+
+import (
+	"fmt"
+
+	"github.com/portworx/pxc/pkg/cliops"
+	"github.com/portworx/pxc/pkg/commander"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var exportVolumesCmd *cobra.Command
+
+var _ = commander.RegisterCommandVar(func() {
+	exportVolumesCmd = &cobra.Command{
+		Use:   "export [NAME]",
+		Short: "Export volumes as a manifest",
+		Long: `Export the configuration of volumes as a manifest which can be used
+with pxc volume create -f or pxc apply -f. Runtime information is not
+exported and fields with default values are omitted.`,
+		Example: `
+  # Export the volume myvolume
+  pxc volume export myvolume > myvolume.yaml
+
+  # Export all the volumes with the label app=db as json
+  pxc volume export -l app=db -o json
+
+  # Copy the volumes to the cluster in the context other
+  pxc volume export -l app=db | pxc volume create -f - --pxc.context=other`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			selector, _ := cmd.Flags().GetString("selector")
+			if len(args) == 0 && len(selector) == 0 {
+				return fmt.Errorf("Must supply the names of the volumes or a selector")
+			}
+			return nil
+		},
+		RunE: exportVolumesExec,
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	VolumeAddCommand(exportVolumesCmd)
+	exportVolumesCmd.Flags().StringP("output", "o", "yaml", "Output in yaml|json")
+	exportVolumesCmd.Flags().StringP("selector", "l", "", "Selector (label query) comma-separated name=value pairs")
+})
+
+func ExportAddCommand(cmd *cobra.Command) {
+	exportVolumesCmd.AddCommand(cmd)
+}
+
+func exportVolumesExec(cmd *cobra.Command, args []string) error {
+	cvi := cliops.NewCliInputs(cmd, args)
+	if cvi.FormatType != util.FORMAT_YAML && cvi.FormatType != util.FORMAT_JSON {
+		return fmt.Errorf("Invalid output format %s. Valid values are yaml or json", cvi.FormatType)
+	}
+
+	cliOps := cliops.NewCliOps(cvi)
+	if err := cliOps.Connect(); err != nil {
+		return err
+	}
+	defer cliOps.Close()
+
+	vols, err := portworx.NewVolumes(cliOps.PxOps(), &portworx.VolumeSpec{
+		VolNames: args,
+		Labels:   cvi.Labels,
+	}).GetVolumes()
+	if err != nil {
+		return err
+	}
+
+	manifest := &portworx.VolumeManifest{
+		Volumes: make([]*portworx.ManifestVolume, 0, len(vols)),
+	}
+	for _, vol := range vols {
+		// Snapshots are only exported when requested by name
+		if len(args) == 0 && vol.GetReadonly() {
+			continue
+		}
+		v, err := portworx.NewManifestVolume(vol)
+		if err != nil {
+			return err
+		}
+		manifest.Volumes = append(manifest.Volumes, v)
+	}
+
+	if cvi.FormatType == util.FORMAT_JSON {
+		util.PrintJson(manifest)
+		util.Printf("\n")
+	} else {
+		util.PrintYaml(manifest)
+	}
+	return nil
+}
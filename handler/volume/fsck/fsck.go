@@ -0,0 +1,64 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fsck
+
+import (
+	"context"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/handler/volume"
+	"github.com/portworx/pxc/pkg/commander"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+
+	"google.golang.org/grpc"
+)
+
+// fsckCmd represents the fsck command
+var fsckCmd *cobra.Command
+
+var _ = commander.RegisterCommandVar(func() {
+	fsckCmd = &cobra.Command{
+		Use:   "fsck",
+		Short: "Manage filesystem check operations on unmounted volumes",
+		Long: `Filesystem check verifies and repairs the filesystem of a volume. It runs
+in the background on volumes which are not mounted.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.Printf("Please see pxc volume fsck --help for more commands\n")
+		},
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	volume.VolumeAddCommand(fsckCmd)
+})
+
+func FsckAddCommand(cmd *cobra.Command) {
+	fsckCmd.AddCommand(cmd)
+}
+
+// fsckOp gets the status of the filesystem checks
+var fsckOp = &volume.FilesystemOp{
+	Name: "filesystem check",
+	// Filesystem checks only run on unmounted volumes
+	RunsOn: func(v *api.Volume) bool {
+		return len(v.GetAttachPath()) == 0
+	},
+	Status: func(ctx context.Context, conn *grpc.ClientConn, name, path string) (*portworx.FilesystemOpStatus, error) {
+		return portworx.GetFsCheckStatus(ctx, conn, name)
+	},
+}
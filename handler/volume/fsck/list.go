@@ -0,0 +1,39 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fsck
+
+import (
+	"github.com/portworx/pxc/pkg/commander"
+	"github.com/spf13/cobra"
+)
+
+var listCmd *cobra.Command
+
+var _ = commander.RegisterCommandVar(func() {
+	listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the filesystem checks running in the cluster",
+		Example: `
+  # List the volumes with a filesystem check in progress
+  pxc volume fsck list`,
+		RunE: fsckOp.ListExec,
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	FsckAddCommand(listCmd)
+	listCmd.Flags().Int("workers", 4, "Number of volumes checked at the same time")
+})
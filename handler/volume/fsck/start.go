@@ -0,0 +1,78 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fsck
+
+import (
+	"strings"
+
+	"github.com/portworx/pxc/handler/volume"
+	"github.com/portworx/pxc/pkg/commander"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var startCmd *cobra.Command
+
+var _ = commander.RegisterCommandVar(func() {
+	startCmd = &cobra.Command{
+		Use:   "start [NAME]",
+		Short: "Start a filesystem check on an unmounted volume",
+		Long: `Start a filesystem check on an unmounted volume. The modes are:
+
+  check_health  Check the filesystem without changing it
+  fix_safe      Fix the problems which can be fixed without losing data
+  fix_all       Fix all the problems, which may lose data`,
+		Example: `
+  # Check the health of the filesystem of the volume myvolume
+  pxc volume fsck start myvolume
+
+  # Fix the filesystem of the volume myvolume and wait until it completes
+  pxc volume fsck start myvolume --mode fix_safe --wait`,
+		Args: volume.ValidateVolumeArg,
+		RunE: startExec,
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	FsckAddCommand(startCmd)
+	startCmd.Flags().String("mode", portworx.FsCheckModeCheckHealth,
+		"Mode of the filesystem check (Valid Values: ["+strings.Join(portworx.FsCheckModes, " ")+"])")
+	startCmd.Flags().Bool("wait", false, "Wait until the filesystem check completes")
+	startCmd.Flags().Duration("timeout", portworx.DefaultVolumeWaitTimeout, "Time to wait when --wait is provided")
+})
+
+func startExec(cmd *cobra.Command, args []string) error {
+	mode, _ := cmd.Flags().GetString("mode")
+	if err := portworx.ValidateFsCheckMode(mode); err != nil {
+		return err
+	}
+
+	ctx, conn, err := portworx.PxConnectDefault(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	name := args[0]
+	s, err := portworx.StartFsCheck(ctx, conn, name, mode)
+	if err != nil {
+		return util.PxErrorMessage(err, "Failed to start filesystem check")
+	}
+	util.Printf("Filesystem check on volume %s %s\n", name, s.Status)
+
+	return fsckOp.Wait(ctx, conn, cmd, name, "")
+}
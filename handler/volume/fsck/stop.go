@@ -0,0 +1,57 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fsck
+
+import (
+	"github.com/portworx/pxc/handler/volume"
+	"github.com/portworx/pxc/pkg/commander"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var stopCmd *cobra.Command
+
+var _ = commander.RegisterCommandVar(func() {
+	stopCmd = &cobra.Command{
+		Use:   "stop [NAME]",
+		Short: "Stop the filesystem check on a volume",
+		Example: `
+  # Stop the filesystem check on the volume myvolume
+  pxc volume fsck stop myvolume`,
+		Args: volume.ValidateVolumeArg,
+		RunE: stopExec,
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	FsckAddCommand(stopCmd)
+})
+
+func stopExec(cmd *cobra.Command, args []string) error {
+	ctx, conn, err := portworx.PxConnectDefault(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	name := args[0]
+	if err := portworx.StopFsCheck(ctx, conn, name); err != nil {
+		return util.PxErrorMessage(err, "Failed to stop filesystem check")
+	}
+	util.Printf("Filesystem check on volume %s stopped\n", name)
+	return nil
+}
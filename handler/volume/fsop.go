@@ -0,0 +1,204 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package volume
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+
+	"google.golang.org/grpc"
+)
+
+// FilesystemOp is a filesystem operation which runs in the background on a
+// volume, like a filesystem trim or check. It implements the list and status
+// commands and the wait of the operation.
+type FilesystemOp struct {
+	// Name of the operation in messages, like "filesystem trim"
+	Name string
+
+	// RunsOn returns true if the operation can run on the volume. Some
+	// operations only run on mounted volumes and others on unmounted ones.
+	RunsOn func(v *api.Volume) bool
+
+	// Status returns the status of the operation on the volume mounted on
+	// path. The path is empty if MountPath is not set.
+	Status func(ctx context.Context, conn *grpc.ClientConn, volume, path string) (*portworx.FilesystemOpStatus, error)
+
+	// MountPath returns the path where the volume is mounted, if the
+	// operation needs it
+	MountPath func(ctx context.Context, conn *grpc.ClientConn, cmd *cobra.Command, volume string) (string, error)
+}
+
+// ValidateVolumeArg checks that the name of the volume was provided and
+// that the timeout to wait for the operation, if any, is positive
+func ValidateVolumeArg(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("Must supply the name of the volume")
+	}
+	if cmd.Flags().Lookup("timeout") != nil {
+		if timeout, _ := cmd.Flags().GetDuration("timeout"); timeout <= 0 {
+			return fmt.Errorf("--timeout must be greater than 0")
+		}
+	}
+	return nil
+}
+
+// ListExec prints the volumes where the operation is running. The number of
+// volumes queried at the same time is set by the workers flag.
+func (op *FilesystemOp) ListExec(cmd *cobra.Command, args []string) error {
+	ctx, conn, err := portworx.PxConnectDefault(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	resp, err := api.NewOpenStorageVolumeClient(conn).InspectWithFilters(ctx,
+		&api.SdkVolumeInspectWithFiltersRequest{})
+	if err != nil {
+		return util.PxErrorMessage(err, "Failed to get volumes")
+	}
+	vols := make([]*api.Volume, 0, len(resp.GetVolumes()))
+	for _, v := range resp.GetVolumes() {
+		vols = append(vols, v.GetVolume())
+	}
+
+	workers, _ := cmd.Flags().GetInt("workers")
+	running, err := portworx.ListRunningFilesystemOps(vols, workers,
+		func(v *api.Volume) (*portworx.FilesystemOpStatus, error) {
+			if !op.RunsOn(v) {
+				return nil, nil
+			}
+			path := ""
+			if len(v.GetAttachPath()) != 0 {
+				path = v.GetAttachPath()[0]
+			}
+			return op.Status(ctx, conn, v.GetLocator().GetName(), path)
+		})
+	if err != nil {
+		return err
+	}
+
+	if len(running) == 0 {
+		util.Printf("No %ss running\n", op.Name)
+		return nil
+	}
+	t := util.NewTabby()
+	t.AddHeader("Volume", "Status", "Message")
+	for _, s := range running {
+		t.AddLine(s.Volume, s.Status, s.Message)
+	}
+	t.Print()
+	return nil
+}
+
+// StatusExec prints the status of the operation on the volume. With the
+// wait flag it waits until the operation is no longer running.
+func (op *FilesystemOp) StatusExec(cmd *cobra.Command, args []string) error {
+	ctx, conn, err := portworx.PxConnectDefault(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	name := args[0]
+	path, err := op.mountPath(ctx, conn, cmd, name)
+	if err != nil {
+		return err
+	}
+
+	getStatus := func() (*portworx.FilesystemOpStatus, error) {
+		return op.Status(ctx, conn, name, path)
+	}
+	var s *portworx.FilesystemOpStatus
+	if wait, _ := cmd.Flags().GetBool("wait"); wait {
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		s, err = portworx.WaitForFilesystemOp(name, timeout, getStatus)
+	} else {
+		s, err = getStatus()
+	}
+	if err != nil {
+		return util.PxErrorMessage(err, fmt.Sprintf("Failed to get %s status", op.Name))
+	}
+
+	PrintFilesystemOpStatus(s)
+	return nil
+}
+
+// Wait waits until the operation started on the volume completes if the wait
+// flag was provided. Returns an error if the operation failed.
+func (op *FilesystemOp) Wait(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	cmd *cobra.Command,
+	name, path string,
+) error {
+	if wait, _ := cmd.Flags().GetBool("wait"); !wait {
+		return nil
+	}
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	s, err := portworx.WaitForFilesystemOp(name, timeout, func() (*portworx.FilesystemOpStatus, error) {
+		return op.Status(ctx, conn, name, path)
+	})
+	if err != nil {
+		return util.PxErrorMessage(err, fmt.Sprintf("Failed to wait for %s", op.Name))
+	}
+	PrintFilesystemOpStatus(s)
+	if s.Failed {
+		return fmt.Errorf("%s on volume %s failed", op.title(), name)
+	}
+	return nil
+}
+
+func (op *FilesystemOp) mountPath(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	cmd *cobra.Command,
+	name string,
+) (string, error) {
+	if op.MountPath == nil {
+		return "", nil
+	}
+	return op.MountPath(ctx, conn, cmd, name)
+}
+
+// title returns the name of the operation to start a sentence
+func (op *FilesystemOp) title() string {
+	return strings.ToUpper(op.Name[:1]) + op.Name[1:]
+}
+
+// PrintFilesystemOpStatus prints the status of a filesystem operation on a
+// volume
+func PrintFilesystemOpStatus(s *portworx.FilesystemOpStatus) {
+	t := util.NewTabby()
+	t.AddLine("Volume:", s.Volume)
+	t.AddLine("Status:", s.Status)
+	if len(s.Mode) != 0 {
+		t.AddLine("Mode:", s.Mode)
+	}
+	if len(s.Health) != 0 {
+		t.AddLine("Health:", s.Health)
+	}
+	if len(s.Message) != 0 {
+		t.AddLine("Message:", s.Message)
+	}
+	t.Print()
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fstrim
+
+import (
+	"context"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/handler/volume"
+	"github.com/portworx/pxc/pkg/commander"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+
+	"google.golang.org/grpc"
+)
+
+// fstrimCmd represents the fstrim command
+var fstrimCmd *cobra.Command
+
+var _ = commander.RegisterCommandVar(func() {
+	fstrimCmd = &cobra.Command{
+		Use:   "fstrim",
+		Short: "Manage filesystem trim operations on mounted volumes",
+		Long: `Filesystem trim frees the space of deleted files in the volume back to
+the storage pool. It runs in the background on mounted volumes.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.Printf("Please see pxc volume fstrim --help for more commands\n")
+		},
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	volume.VolumeAddCommand(fstrimCmd)
+})
+
+func FstrimAddCommand(cmd *cobra.Command) {
+	fstrimCmd.AddCommand(cmd)
+}
+
+// fstrimOp gets the status of the filesystem trims
+var fstrimOp = &volume.FilesystemOp{
+	Name: "filesystem trim",
+	// Filesystem trims only run on mounted volumes
+	RunsOn: func(v *api.Volume) bool {
+		return len(v.GetAttachPath()) != 0
+	},
+	Status:    portworx.GetFsTrimStatus,
+	MountPath: getMountPath,
+}
+
+// getMountPath returns the path provided with --path or the path where
+// the volume is mounted
+func getMountPath(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	cmd *cobra.Command,
+	name string,
+) (string, error) {
+	if path, _ := cmd.Flags().GetString("path"); len(path) != 0 {
+		return path, nil
+	}
+
+	resp, err := api.NewOpenStorageVolumeClient(conn).Inspect(ctx, &api.SdkVolumeInspectRequest{
+		VolumeId: name,
+	})
+	if err != nil {
+		return "", util.PxErrorMessagef(err, "Failed to get volume %s", name)
+	}
+	return portworx.GetFsTrimMountPath(resp.GetVolume())
+}
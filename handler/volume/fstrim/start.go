@@ -0,0 +1,70 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fstrim
+
+import (
+	"github.com/portworx/pxc/handler/volume"
+	"github.com/portworx/pxc/pkg/commander"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var startCmd *cobra.Command
+
+var _ = commander.RegisterCommandVar(func() {
+	startCmd = &cobra.Command{
+		Use:   "start [NAME]",
+		Short: "Start a filesystem trim on a mounted volume",
+		Example: `
+  # Start a filesystem trim on the volume myvolume
+  pxc volume fstrim start myvolume
+
+  # Start a filesystem trim and wait until it completes
+  pxc volume fstrim start myvolume --wait --timeout=1h`,
+		Args: volume.ValidateVolumeArg,
+		RunE: startExec,
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	FstrimAddCommand(startCmd)
+	startCmd.Flags().String("path", "", "Path where the volume is mounted (default is the mount path of the volume)")
+	startCmd.Flags().Bool("wait", false, "Wait until the filesystem trim completes")
+	startCmd.Flags().Duration("timeout", portworx.DefaultVolumeWaitTimeout, "Time to wait when --wait is provided")
+})
+
+func startExec(cmd *cobra.Command, args []string) error {
+	ctx, conn, err := portworx.PxConnectDefault(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	name := args[0]
+	path, err := getMountPath(ctx, conn, cmd, name)
+	if err != nil {
+		return err
+	}
+
+	s, err := portworx.StartFsTrim(ctx, conn, name, path)
+	if err != nil {
+		return util.PxErrorMessage(err, "Failed to start filesystem trim")
+	}
+	util.Printf("Filesystem trim on volume %s %s\n", name, s.Status)
+
+	return fstrimOp.Wait(ctx, conn, cmd, name, path)
+}
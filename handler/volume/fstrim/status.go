@@ -0,0 +1,47 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fstrim
+
+import (
+	"github.com/portworx/pxc/handler/volume"
+	"github.com/portworx/pxc/pkg/commander"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd *cobra.Command
+
+var _ = commander.RegisterCommandVar(func() {
+	statusCmd = &cobra.Command{
+		Use:   "status [NAME]",
+		Short: "Show the status of the filesystem trim on a volume",
+		Example: `
+  # Show the status of the filesystem trim on the volume myvolume
+  pxc volume fstrim status myvolume
+
+  # Show the progress until the filesystem trim completes
+  pxc volume fstrim status myvolume --wait`,
+		Args: volume.ValidateVolumeArg,
+		RunE: fstrimOp.StatusExec,
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	FstrimAddCommand(statusCmd)
+	statusCmd.Flags().String("path", "", "Path where the volume is mounted (default is the mount path of the volume)")
+	statusCmd.Flags().Bool("wait", false, "Wait until the filesystem trim is no longer running")
+	statusCmd.Flags().Duration("timeout", portworx.DefaultVolumeWaitTimeout, "Time to wait when --wait is provided")
+})
@@ -16,13 +16,8 @@ limitations under the License.
 package volume
 
 import (
-	"bytes"
-	"math/big"
 	"strings"
-	"text/tabwriter"
 
-	"github.com/cheynewallace/tabby"
-	humanize "github.com/dustin/go-humanize"
 	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
 	"github.com/portworx/pxc/pkg/cliops"
 	"github.com/portworx/pxc/pkg/commander"
@@ -40,7 +35,22 @@ var _ = commander.RegisterCommandVar(func() {
 		Short:   "Get information about Portworx volumes",
 		Example: `
   # Get informtation about the portworx volumes
-  pxc volume list`,
+  pxc volume list
+
+  # Show the name and HA level of each volume
+  pxc volume list -o custom-columns=NAME:.locator.name,HA:.spec.haLevel
+
+  # Print the id of each volume on its own line
+  pxc volume list -o jsonpath='{range .items[*]}{.id}{"\n"}{end}'
+
+  # Print the names of the volumes
+  pxc volume list -o name
+
+  # Show the 10 largest volumes which are not attached
+  pxc volume list --field-selector attached=false --sort-by=-size --limit 10
+
+  # Save the volumes with their sizes in bytes as csv for a spreadsheet
+  pxc volume list -o csv --raw-units > volumes.csv`,
 		RunE: getVolumesExec,
 	}
 })
@@ -51,7 +61,12 @@ var _ = commander.RegisterCommandInit(func() {
 	getVolumesCmd.Flags().String("volumegroup", "", "Volume group id")
 	//getVolumesCmd.Flags().Bool("deep", false, "Collect more information, this may delay the request")
 	getVolumesCmd.Flags().Bool("show-k8s-info", false, "Show kubernetes information")
-	getVolumesCmd.Flags().StringP("output", "o", "", "Output in yaml|json|wide")
+	getVolumesCmd.Flags().StringP("output", "o", "", "Output in yaml|json|wide|csv|markdown|name|custom-columns=<spec>|jsonpath=<template>|go-template=<template>")
+	getVolumesCmd.Flags().Bool("no-headers", false, "Do not print the headers of tables")
+	getVolumesCmd.Flags().Bool("raw-units", false, "Show sizes in bytes instead of humanized")
+	getVolumesCmd.Flags().String("sort-by", "", "Sort on a field like size, used, ha, created, node or status. Prefix with - to sort in descending order")
+	getVolumesCmd.Flags().String("field-selector", "", "Select volumes on fields, comma-separated field<operator>value like status=down,ha<3,attached=false,node=host1,encrypted=true")
+	getVolumesCmd.Flags().Int("limit", 0, "Maximum number of volumes shown. 0 shows all the volumes")
 	getVolumesCmd.Flags().Bool("show-labels", false, "Show labels in the last column of the output")
 	getVolumesCmd.Flags().StringP("selector", "l", "", "Selector (label query) comma-separated name=value pairs")
 	// TODO: Place here support for selectors and move the flags from the rootCmd
@@ -84,7 +99,7 @@ func getVolumesExec(cmd *cobra.Command, args []string) error {
 	vgf := NewVolumeGetFormatter(cliOps)
 
 	// Print the details and return errors if any
-	return util.PrintFormatted(vgf)
+	return util.PrintFormatted(cmd.Context(), vgf)
 }
 
 type volumeGetFormatter struct {
@@ -106,13 +121,31 @@ func NewVolumeGetFormatter(cliOps cliops.CliOps) *volumeGetFormatter {
 		volumes: portworx.NewVolumes(cliOps.PxOps(), volSpec),
 		pods:    portworx.NewPods(cliOps.COps(), &portworx.PodSpec{}),
 	}
-	v.FormatType = cliOps.CliInputs().FormatType
+	v.BaseFormatOutput = cliOps.CliInputs().BaseFormatOutput
 	return v
 }
 
+// getVolumes returns the volumes selected, sorted and limited by the list
+// options
+func (p *volumeGetFormatter) getVolumes() ([]*api.Volume, error) {
+	vols, err := p.volumes.GetVolumes()
+	if err != nil {
+		return nil, err
+	}
+	opts := &p.cliOps.CliInputs().ListOptions
+	if opts.IsEmpty() {
+		return vols, nil
+	}
+	nodes, err := portworx.NewNodesForVolumes(p.cliOps.PxOps(), vols)
+	if err != nil {
+		return nil, err
+	}
+	return portworx.SelectVolumes(vols, nodes, opts)
+}
+
 // YamlFormat returns the yaml representation of the object
 func (p *volumeGetFormatter) YamlFormat() (string, error) {
-	vols, err := p.volumes.GetVolumes()
+	vols, err := p.getVolumes()
 	if err != nil {
 		return "", err
 	}
@@ -121,7 +154,7 @@ func (p *volumeGetFormatter) YamlFormat() (string, error) {
 
 // JsonFormat returns the json representation of the object
 func (p *volumeGetFormatter) JsonFormat() (string, error) {
-	vols, err := p.volumes.GetVolumes()
+	vols, err := p.getVolumes()
 	if err != nil {
 		return "", err
 	}
@@ -138,46 +171,56 @@ func (p *volumeGetFormatter) DefaultFormat() (string, error) {
 	return p.toTabbed()
 }
 
-func (p *volumeGetFormatter) toTabbed() (string, error) {
-	var b bytes.Buffer
-	writer := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
-	t := tabby.NewCustom(writer)
-
-	vols, err := p.volumes.GetVolumes()
+// TableFormat returns the table of the volumes
+func (p *volumeGetFormatter) TableFormat() (*util.Table, error) {
+	vols, err := p.getVolumes()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if len(vols) == 0 {
 		util.Printf("No resources found\n")
-		return "", nil
+		return nil, nil
 	}
 
 	p.nodes, err = portworx.NewNodesForVolumes(p.cliOps.PxOps(), vols)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	t := &util.Table{}
 	// Start the columns
 	t.AddHeader(p.getHeader()...)
 
 	for _, n := range vols {
 		l, err := p.getLine(n)
 		if err != nil {
-			return "", nil
+			return nil, nil
 		}
 		t.AddLine(l...)
 	}
-	t.Print()
 
-	return b.String(), nil
+	return t, nil
+}
+
+func (p *volumeGetFormatter) toTabbed() (string, error) {
+	t, err := p.TableFormat()
+	if err != nil || t == nil {
+		return "", err
+	}
+	return t.Tabbed(), nil
 }
 
 func (p *volumeGetFormatter) getHeader() []interface{} {
+	size := "Size"
+	if p.RawUnits {
+		size = "Size (bytes)"
+	}
+
 	var header []interface{}
 	if p.cliOps.CliInputs().Wide {
-		header = []interface{}{"Id", "Name", "Size Gi", "HA", "Shared", "Encrypted", "Io Profile", "Status", "State", "Snap Enabled"}
+		header = []interface{}{"Id", "Name", size, "HA", "Shared", "Encrypted", "Io Profile", "Status", "State", "Snap Enabled"}
 	} else {
-		header = []interface{}{"Name", "Size", "HA", "Shared", "Status", "State"}
+		header = []interface{}{"Name", size, "HA", "Shared", "Status", "State"}
 	}
 	if util.InKubectlPluginMode() {
 		header = append(header, "Pods")
@@ -199,7 +242,7 @@ func (p *volumeGetFormatter) getLine(v *api.Volume) ([]interface{}, error) {
 		return line, err
 	}
 
-	size := humanize.BigIBytes(big.NewInt(int64(spec.GetSize())))
+	size := p.FormatBytes(spec.GetSize())
 	if p.cliOps.CliInputs().Wide {
 		line = []interface{}{
 			v.GetId(), v.GetLocator().GetName(), size, spec.GetHaLevel(),
@@ -24,10 +24,11 @@ import (
 	"github.com/portworx/pxc/pkg/portworx"
 	"github.com/portworx/pxc/pkg/util"
 	"github.com/spf13/cobra"
+
+	"github.com/golang/protobuf/proto"
 )
 
 type volumeUpdateOpts struct {
-	req                    *api.SdkVolumeUpdateRequest
 	halevel                int64
 	replicaSet             []string
 	size                   uint64
@@ -44,19 +45,18 @@ type volumeUpdateOpts struct {
 	asyncIo                string
 	ioProfile              string
 	noDiscard              string
+	dryRun                 bool
+	confirm                bool
 }
 
 var (
 	updateReq      *volumeUpdateOpts
 	patchVolumeCmd *cobra.Command
-	cliOps         cliops.CliOps
 )
 
 // updateVolumeCmd represents the updateVolume command
 var _ = commander.RegisterCommandVar(func() {
-	updateReq = &volumeUpdateOpts{
-		req: &api.SdkVolumeUpdateRequest{},
-	}
+	updateReq = &volumeUpdateOpts{}
 
 	patchVolumeCmd = &cobra.Command{
 		Use:     "update [NAME]",
@@ -80,13 +80,29 @@ var _ = commander.RegisterCommandVar(func() {
   pxc volume update xyz --add-collaborators user4:r,user5:w, --remove-groups group1:r
 
   # Update the access type of the existing collaborators and groups
-  pxc volume update xyz --add-collaborators user1:a --add-groups group1:a`,
+  pxc volume update xyz --add-collaborators user1:a --add-groups group1:a
+
+  #### Review Changes ####
+
+  # Show the changes to the volume spec without updating the volume
+  pxc volume update xyz --replicas 3 --nodes node1,node2,node3 --dry-run
+
+  # Show the changes and ask for confirmation before updating the volume
+  pxc volume update xyz --remove-groups group1 --confirm
+
+  #### Update Multiple Volumes ####
+
+  # Set the io profile of all the volumes with the label app=db
+  pxc volume update -l app=db --io-profile db --yes
+
+  # Increase the size of the volumes listed in a file, 2 at a time
+  cat volumes.txt | pxc volume update - --size 20 --workers 2`,
 
 		Args: func(cmd *cobra.Command, args []string) error {
-			if len(args) < 1 {
+			if len(args) < 1 && !isBulk(cmd, args) {
 				return fmt.Errorf("Must supply a name for the volume")
 			}
-			return nil
+			return validateBulkArgs(cmd, args)
 		},
 		RunE: updateVolume,
 	}
@@ -111,6 +127,10 @@ var _ = commander.RegisterCommandInit(func() {
 	patchVolumeCmd.Flags().StringVar(&updateReq.asyncIo, "async-io", "", "Enable async IO to backing storage (Valid Values: [on off]) (default \"off\")")
 	patchVolumeCmd.Flags().StringVar(&updateReq.ioProfile, "io-profile", "", "IO Profile (Valid Values: [sequential cms db db_remote sync_shared]) (default \"sequential\")")
 	patchVolumeCmd.Flags().StringVar(&updateReq.noDiscard, "nodiscard", "", "Disable discard support for this volume (Valid Values: [on off]) (default \"off\")")
+	addWaitFlags(patchVolumeCmd, "the volume has the new HA level and size")
+	addBulkFlags(patchVolumeCmd)
+	patchVolumeCmd.Flags().BoolVar(&updateReq.dryRun, "dry-run", false, "Show the changes to the volume spec without updating the volume")
+	patchVolumeCmd.Flags().BoolVar(&updateReq.confirm, "confirm", false, "Show the changes to the volume spec and ask for confirmation before updating the volume")
 	patchVolumeCmd.Flags().SortFlags = false
 })
 
@@ -151,33 +171,140 @@ func PatchAddCommand(cmd *cobra.Command) {
 }
 
 func updateVolume(cmd *cobra.Command, args []string) error {
-
-	changed := false
 	// Parse out all of the common cli volume flags
 	cvi := cliops.NewCliInputs(cmd, args)
 	// Create a CliVolumeOps object
-	cliOps = cliops.NewCliOps(cvi)
+	cliOps := cliops.NewCliOps(cvi)
 	// Connect to px and k8s (if needed)
 	err := cliOps.Connect()
-
+	if err != nil {
+		return err
+	}
 	defer cliOps.Close()
 
+	// Check whether the flag options are in valid combination.
+	err = validateVolumeUpdateOptions()
+	if err != nil {
+		return err
+	}
+
+	if isBulk(cmd, args) {
+		return updateVolumes(cmd, args, cliOps.PxOps())
+	}
+
 	// fetch the volume name from args
-	updateReq.req.VolumeId = args[0]
+	name := args[0]
+	vol, err := readCurrentVolume(cliOps.PxOps(), name)
+	if err != nil {
+		return err
+	}
+	// The acls of the volume are modified by the request
+	currentSpec := proto.Clone(vol.GetSpec()).(*api.VolumeSpec)
+	req, err := buildUpdateRequest(name, vol)
+	if err != nil {
+		return err
+	}
+
+	if updateReq.dryRun || updateReq.confirm {
+		newSpec := portworx.ApplyVolumeSpecUpdate(currentSpec, req.Spec)
+		util.PrintChanges("", portworx.VolumeSpecChanges(currentSpec, newSpec))
+		if updateReq.dryRun {
+			return nil
+		}
+
+		ok, err := util.Confirm(fmt.Sprintf("Update volume %s?", name))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			util.Printf("Volume %s not updated\n", name)
+			return nil
+		}
+	}
 
-	updateReq.req.Spec = &api.VolumeSpecUpdate{}
-	currentGroups := make(map[string]api.Ownership_AccessType)
-	currentCollaborators := make(map[string]api.Ownership_AccessType)
+	if err := sendUpdateRequest(cmd, cliOps.PxOps(), req); err != nil {
+		return util.PxErrorMessage(err, "Failed to patch volume")
+	}
+	util.Printf("Volume %s parameter updated successfully\n", name)
+	return nil
+}
 
-	// Fetch the current acls for the volume
-	acls, err := readCurrentAcls()
-	currentCollaborators = acls.GetCollaborators()
-	currentGroups = acls.GetGroups()
+// updateVolumes updates the volumes matching the selector or owner, or the
+// volumes with the names read from stdin
+func updateVolumes(cmd *cobra.Command, args []string, pxops portworx.PxOps) error {
+	// Check the flags before going through the volumes
+	if _, err := buildUpdateRequest("", &api.Volume{Spec: &api.VolumeSpec{}}); err != nil {
+		return err
+	}
 
+	names, err := getBulkVolumeNames(cmd, args, pxops)
 	if err != nil {
 		return err
 	}
 
+	if updateReq.dryRun || updateReq.confirm {
+		if len(names) == 0 {
+			util.Printf("No volumes found\n")
+			return nil
+		}
+		for _, name := range names {
+			vol, err := readCurrentVolume(pxops, name)
+			if err != nil {
+				return err
+			}
+			currentSpec := proto.Clone(vol.GetSpec()).(*api.VolumeSpec)
+			req, err := buildUpdateRequest(name, vol)
+			if err != nil {
+				return err
+			}
+			newSpec := portworx.ApplyVolumeSpecUpdate(currentSpec, req.Spec)
+			util.Printf("%s\n", util.Colorize(util.ColorYellow, "~ volume "+name))
+			util.PrintChanges("  ", portworx.VolumeSpecChanges(currentSpec, newSpec))
+		}
+		if updateReq.dryRun {
+			return nil
+		}
+
+		yes, _ := cmd.Flags().GetBool("yes")
+		ok, err := util.ConfirmVolumes(fmt.Sprintf("Update %d volumes?", len(names)), len(names), yes)
+		if !ok {
+			return err
+		}
+	} else if ok, err := confirmBulk(cmd, "Update", names); !ok {
+		return err
+	}
+
+	return runBulk(cmd, "Update", names, func(name string) (string, error) {
+		vol, err := readCurrentVolume(pxops, name)
+		if err != nil {
+			return "", err
+		}
+		req, err := buildUpdateRequest(name, vol)
+		if err != nil {
+			return "", err
+		}
+		if err := sendUpdateRequest(cmd, pxops, req); err != nil {
+			return "", err
+		}
+		return "Updated", nil
+	})
+}
+
+// buildUpdateRequest returns the request to update the volume with the
+// values of the flags
+func buildUpdateRequest(name string, vol *api.Volume) (*api.SdkVolumeUpdateRequest, error) {
+	changed := false
+	req := &api.SdkVolumeUpdateRequest{
+		VolumeId: name,
+		Spec:     &api.VolumeSpecUpdate{},
+	}
+
+	// The acls are modified below, so keep a copy of the current spec
+	currentSpec := proto.Clone(vol.GetSpec()).(*api.VolumeSpec)
+	acls := vol.GetSpec().GetOwnership().GetAcls()
+	currentCollaborators := acls.GetCollaborators()
+	currentGroups := acls.GetGroups()
+
 	if len(updateReq.addCollaborators) != 0 || len(updateReq.addGroups) != 0 ||
 		len(updateReq.removeCollaborators) != 0 || len(updateReq.removeGroups) != 0 ||
 		updateReq.removeAllCollaborators || updateReq.removeAllGroups {
@@ -185,11 +312,11 @@ func updateVolume(cmd *cobra.Command, args []string) error {
 		// For addCollaborators, addGroups, removeCollaborators and removeGroups,
 		// Intialize a empty Ownership and update the values later.
 		if acls == nil {
-			updateReq.req.Spec.Ownership = &api.Ownership{
+			req.Spec.Ownership = &api.Ownership{
 				Acls: &api.Ownership_AccessControl{},
 			}
 		} else {
-			updateReq.req.Spec.Ownership = &api.Ownership{
+			req.Spec.Ownership = &api.Ownership{
 				Acls: acls,
 			}
 		}
@@ -199,13 +326,13 @@ func updateVolume(cmd *cobra.Command, args []string) error {
 
 	// Removing all collaborators
 	if updateReq.removeAllCollaborators {
-		updateReq.req.Spec.Ownership.Acls.Collaborators = map[string]api.Ownership_AccessType{}
+		req.Spec.Ownership.Acls.Collaborators = map[string]api.Ownership_AccessType{}
 		changed = true
 	}
 
 	// Removing all groups
 	if updateReq.removeAllGroups {
-		updateReq.req.Spec.Ownership.Acls.Groups = map[string]api.Ownership_AccessType{}
+		req.Spec.Ownership.Acls.Groups = map[string]api.Ownership_AccessType{}
 		changed = true
 	}
 
@@ -213,7 +340,7 @@ func updateVolume(cmd *cobra.Command, args []string) error {
 	if len(updateReq.addCollaborators) != 0 {
 		newCollaborators, err := util.GetAclMapFromString(updateReq.addCollaborators)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if currentCollaborators == nil {
 			// If the currentCollaborators list is empty, directly assign the new set of collaborators
@@ -224,7 +351,7 @@ func updateVolume(cmd *cobra.Command, args []string) error {
 				currentCollaborators[key] = value
 			}
 		}
-		updateReq.req.Spec.Ownership.Acls.Collaborators = currentCollaborators
+		req.Spec.Ownership.Acls.Collaborators = currentCollaborators
 		changed = true
 	}
 
@@ -232,7 +359,7 @@ func updateVolume(cmd *cobra.Command, args []string) error {
 	if len(updateReq.addGroups) != 0 {
 		newGroups, err := util.GetAclMapFromString(updateReq.addGroups)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if currentGroups == nil {
 			// If the currentGroups list is empty, directly assign the new set of Groups
@@ -243,7 +370,7 @@ func updateVolume(cmd *cobra.Command, args []string) error {
 				currentGroups[key] = value
 			}
 		}
-		updateReq.req.Spec.Ownership.Acls.Groups = currentGroups
+		req.Spec.Ownership.Acls.Groups = currentGroups
 		changed = true
 	}
 
@@ -251,13 +378,13 @@ func updateVolume(cmd *cobra.Command, args []string) error {
 	if len(updateReq.removeCollaborators) != 0 {
 		removeCollaborators, err := util.GetAclMapFromString(updateReq.removeCollaborators)
 		if err != nil {
-			return nil
+			return nil, err
 		}
 
 		for key, _ := range removeCollaborators {
 			delete(currentCollaborators, key)
 		}
-		updateReq.req.Spec.Ownership.Acls.Collaborators = currentCollaborators
+		req.Spec.Ownership.Acls.Collaborators = currentCollaborators
 		changed = true
 	}
 
@@ -265,32 +392,32 @@ func updateVolume(cmd *cobra.Command, args []string) error {
 	if len(updateReq.removeGroups) != 0 {
 		removeGroups, err := util.GetAclMapFromString(updateReq.removeGroups)
 		if err != nil {
-			return nil
+			return nil, err
 		}
 		for key, _ := range removeGroups {
 			delete(currentGroups, key)
 		}
-		updateReq.req.Spec.Ownership.Acls.Groups = currentGroups
+		req.Spec.Ownership.Acls.Groups = currentGroups
 		changed = true
 	}
 
 	// check if halevel providied is valid one
 	if updateReq.halevel > 0 {
-		updateReq.req.Spec.HaLevelOpt = &api.VolumeSpecUpdate_HaLevel{
+		req.Spec.HaLevelOpt = &api.VolumeSpecUpdate_HaLevel{
 			HaLevel: int64(updateReq.halevel),
 		}
 		// Replicaset needs to be passed due to know volume driver issue (pwx-9500)
 		// If user provides one it will be overriden and need to have additional checks.
-		updateReq.req.Spec.ReplicaSet = &api.ReplicaSet{
+		req.Spec.ReplicaSet = &api.ReplicaSet{
 			Nodes: updateReq.replicaSet,
 		}
 		changed = true
 	}
 
 	// check prvoide size is valid
-	if updateReq.size >= 0 {
+	if updateReq.size > 0 {
 		//Provided size has to be converted to bytes
-		updateReq.req.Spec.SizeOpt = &api.VolumeSpecUpdate_Size{
+		req.Spec.SizeOpt = &api.VolumeSpecUpdate_Size{
 			Size: (updateReq.size * 1024 * 1024 * 1024),
 		}
 		changed = true
@@ -300,15 +427,15 @@ func updateVolume(cmd *cobra.Command, args []string) error {
 	if len(updateReq.shared) != 0 {
 		switch updateReq.shared {
 		case "on":
-			updateReq.req.Spec.SharedOpt = &api.VolumeSpecUpdate_Shared{
+			req.Spec.SharedOpt = &api.VolumeSpecUpdate_Shared{
 				Shared: true,
 			}
 		case "off":
-			updateReq.req.Spec.SharedOpt = &api.VolumeSpecUpdate_Shared{
+			req.Spec.SharedOpt = &api.VolumeSpecUpdate_Shared{
 				Shared: false,
 			}
 		default:
-			return fmt.Errorf("Invalid input given for shared flag. Valid values are \"on\" or \"off\"")
+			return nil, fmt.Errorf("Invalid input given for shared flag. Valid values are \"on\" or \"off\"")
 		}
 		changed = true
 	}
@@ -317,15 +444,15 @@ func updateVolume(cmd *cobra.Command, args []string) error {
 	if len(updateReq.sharedv4) != 0 {
 		switch updateReq.sharedv4 {
 		case "on":
-			updateReq.req.Spec.Sharedv4Opt = &api.VolumeSpecUpdate_Sharedv4{
+			req.Spec.Sharedv4Opt = &api.VolumeSpecUpdate_Sharedv4{
 				Sharedv4: true,
 			}
 		case "off":
-			updateReq.req.Spec.Sharedv4Opt = &api.VolumeSpecUpdate_Sharedv4{
+			req.Spec.Sharedv4Opt = &api.VolumeSpecUpdate_Sharedv4{
 				Sharedv4: false,
 			}
 		default:
-			return fmt.Errorf("Invalid input given for shared flag. Valid values are \"on\" or \"off\"")
+			return nil, fmt.Errorf("Invalid input given for shared flag. Valid values are \"on\" or \"off\"")
 		}
 		changed = true
 	}
@@ -334,29 +461,34 @@ func updateVolume(cmd *cobra.Command, args []string) error {
 	if len(updateReq.sticky) != 0 {
 		switch updateReq.sticky {
 		case "on":
-			updateReq.req.Spec.StickyOpt = &api.VolumeSpecUpdate_Sticky{
+			req.Spec.StickyOpt = &api.VolumeSpecUpdate_Sticky{
 				Sticky: true,
 			}
 		case "off":
-			updateReq.req.Spec.StickyOpt = &api.VolumeSpecUpdate_Sticky{
+			req.Spec.StickyOpt = &api.VolumeSpecUpdate_Sticky{
 				Sticky: false,
 			}
 		default:
-			return fmt.Errorf("Invalid input given for sticky flag. Valid values are \"on\" or \"off\"")
+			return nil, fmt.Errorf("Invalid input given for sticky flag. Valid values are \"on\" or \"off\"")
 		}
 		changed = true
 	}
 
-	// Setting IoStrategy
-	updateReq.req.Spec.IoStrategy = &api.IoStrategy{}
+	// Setting IoStrategy. Keep the current value of the settings not provided.
+	if len(updateReq.earlyAck) != 0 || len(updateReq.asyncIo) != 0 {
+		req.Spec.IoStrategy = &api.IoStrategy{
+			EarlyAck: currentSpec.GetIoStrategy().GetEarlyAck(),
+			AsyncIo:  currentSpec.GetIoStrategy().GetAsyncIo(),
+		}
+	}
 	if len(updateReq.earlyAck) != 0 {
 		switch updateReq.earlyAck {
 		case "on":
-			updateReq.req.Spec.IoStrategy.EarlyAck = true
+			req.Spec.IoStrategy.EarlyAck = true
 		case "off":
-			updateReq.req.Spec.IoStrategy.EarlyAck = false
+			req.Spec.IoStrategy.EarlyAck = false
 		default:
-			return fmt.Errorf("Invalid input given for early-ack. Valid values are \"on\" or \"off\"")
+			return nil, fmt.Errorf("Invalid input given for early-ack. Valid values are \"on\" or \"off\"")
 		}
 		changed = true
 	}
@@ -364,40 +496,23 @@ func updateVolume(cmd *cobra.Command, args []string) error {
 	if len(updateReq.asyncIo) != 0 {
 		switch updateReq.asyncIo {
 		case "on":
-			updateReq.req.Spec.IoStrategy.AsyncIo = true
+			req.Spec.IoStrategy.AsyncIo = true
 		case "off":
-			updateReq.req.Spec.IoStrategy.AsyncIo = false
+			req.Spec.IoStrategy.AsyncIo = false
 		default:
-			return fmt.Errorf("Invalid input given for async-io. Valid values are \"on\" or \"off\"")
+			return nil, fmt.Errorf("Invalid input given for async-io. Valid values are \"on\" or \"off\"")
 		}
 		changed = true
 	}
 
 	// Setting IoProfile
 	if len(updateReq.ioProfile) > 0 {
-		switch updateReq.ioProfile {
-		case "db":
-			updateReq.req.Spec.IoProfileOpt = &api.VolumeSpecUpdate_IoProfile{
-				IoProfile: api.IoProfile_IO_PROFILE_DB,
-			}
-		case "cms":
-			updateReq.req.Spec.IoProfileOpt = &api.VolumeSpecUpdate_IoProfile{
-				IoProfile: api.IoProfile_IO_PROFILE_CMS,
-			}
-		case "db_remote":
-			updateReq.req.Spec.IoProfileOpt = &api.VolumeSpecUpdate_IoProfile{
-				IoProfile: api.IoProfile_IO_PROFILE_DB_REMOTE,
-			}
-		case "sync_shared":
-			updateReq.req.Spec.IoProfileOpt = &api.VolumeSpecUpdate_IoProfile{
-				IoProfile: api.IoProfile_IO_PROFILE_SYNC_SHARED,
-			}
-		case "sequential":
-			updateReq.req.Spec.IoProfileOpt = &api.VolumeSpecUpdate_IoProfile{
-				IoProfile: api.IoProfile_IO_PROFILE_SEQUENTIAL,
-			}
-		default:
-			return fmt.Errorf("Invalid input given for IoProfile. Please see help.")
+		ioProfile, err := portworx.GetIoProfile(updateReq.ioProfile)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid input given for IoProfile. Please see help.")
+		}
+		req.Spec.IoProfileOpt = &api.VolumeSpecUpdate_IoProfile{
+			IoProfile: ioProfile,
 		}
 		changed = true
 	}
@@ -406,48 +521,70 @@ func updateVolume(cmd *cobra.Command, args []string) error {
 	if len(updateReq.noDiscard) != 0 {
 		switch updateReq.noDiscard {
 		case "on":
-			updateReq.req.Spec.NodiscardOpt = &api.VolumeSpecUpdate_Nodiscard{
+			req.Spec.NodiscardOpt = &api.VolumeSpecUpdate_Nodiscard{
 				Nodiscard: true,
 			}
 		case "off":
-			updateReq.req.Spec.NodiscardOpt = &api.VolumeSpecUpdate_Nodiscard{
+			req.Spec.NodiscardOpt = &api.VolumeSpecUpdate_Nodiscard{
 				Nodiscard: false,
 			}
 		default:
-			return fmt.Errorf("Invalid input given for nodiscard. Valid values are \"on\" or \"off\"")
+			return nil, fmt.Errorf("Invalid input given for nodiscard. Valid values are \"on\" or \"off\"")
 		}
 		changed = true
 	}
 
 	if !changed {
-		return fmt.Errorf("Error: Must supply any one of the flags with valid parameters. " +
+		return nil, fmt.Errorf("Error: Must supply any one of the flags with valid parameters. " +
 			"Please see help for more info")
 	}
 
-	// Check whether the flag options are in valid combination.
-	err = validateVolumeUpdateOptions()
+	if err := portworx.ValidateVolumeSpec(req.Spec); err != nil {
+		return nil, err
+	}
+	return req, nil
+
+}
+
+// sendUpdateRequest updates the volume and waits for the update to complete
+// if --wait was provided
+func sendUpdateRequest(
+	cmd *cobra.Command,
+	pxops portworx.PxOps,
+	req *api.SdkVolumeUpdateRequest,
+) error {
+	if pxops.GetConn() == nil {
+		return portworx.ErrNotSupportedByTransport
+	}
+	err := portworx.UpdateVolume(pxops.GetCtx(), pxops.GetConn(), req)
 	if err != nil {
 		return err
 	}
 
-	volumes := api.NewOpenStorageVolumeClient(cliOps.PxOps().GetConn())
-	_, err = volumes.Update(cliOps.PxOps().GetCtx(), updateReq.req)
-	if err != nil {
-		return util.PxErrorMessage(err, "Failed to patch volume")
+	return waitIfRequested(cmd, req.GetVolumeId(), updateCondition())
+}
+
+// updateCondition returns the condition met by the volume when the update
+// has completed
+func updateCondition() *portworx.VolumeCondition {
+	if updateReq.halevel > 0 {
+		return portworx.NewVolumeHaCondition(updateReq.halevel)
 	}
-	util.Printf("Volume %s parameter updated successfully\n", updateReq.req.VolumeId)
-	return nil
+	if updateReq.size > 0 {
+		return portworx.NewVolumeSizeCondition(updateReq.size * uint64(util.Gi))
+	}
+	return portworx.NewVolumeStatusCondition(portworx.VolumeStatusUp)
 }
 
-// Reads current acls for the given volume
-func readCurrentAcls() (*api.Ownership_AccessControl, error) {
+// Reads the current volume
+func readCurrentVolume(pxops portworx.PxOps, name string) (*api.Volume, error) {
 	volNames := make([]string, 1, 1)
 	// Assign the user given volume Name
-	volNames[0] = updateReq.req.VolumeId
+	volNames[0] = name
 	volSpec := &portworx.VolumeSpec{
 		VolNames: volNames,
 	}
-	vo := portworx.NewVolumes(cliOps.PxOps(), volSpec)
+	vo := portworx.NewVolumes(pxops, volSpec)
 
 	// Get the current copy of the volume spec
 	vols, err := vo.GetVolumes()
@@ -455,10 +592,8 @@ func readCurrentAcls() (*api.Ownership_AccessControl, error) {
 		return nil, err
 	}
 	if len(vols) == 0 {
-		return nil, fmt.Errorf("Error: Volume: %s not found\n", updateReq.req.VolumeId)
+		return nil, fmt.Errorf("Error: Volume: %s not found\n", name)
 	}
-	spec := vols[0].GetSpec()
-	acls := spec.GetOwnership().GetAcls()
 
-	return acls, nil
+	return vols[0], nil
 }
@@ -16,7 +16,9 @@ package volume
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
 	"github.com/portworx/pxc/pkg/commander"
 	"github.com/portworx/pxc/pkg/portworx"
@@ -27,6 +29,7 @@ import (
 type volumeSnapshotOpts struct {
 	req            *api.SdkVolumeSnapshotCreateRequest
 	labelsAsString string
+	suffix         string
 }
 
 var (
@@ -46,12 +49,15 @@ var _ = commander.RegisterCommandVar(func() {
 		Long:    `Create a snapshot for the specified volume`,
 		Example: `
   # Create a snapshot named mysnap for the specified volume "myvol":
-  pxc create volumesnapshot mysnap --labels color=blue,fabric=wool --volume myvol`,
+  pxc volume snapshot myvol mysnap --labels color=blue,fabric=wool
+
+  # Create a snapshot named <volume>-nightly of all the volumes with the label app=db
+  pxc volume snapshot -l app=db --suffix nightly`,
 		Args: func(cmd *cobra.Command, args []string) error {
-			if len(args) < 2 {
+			if len(args) < 2 && !isBulk(cmd, args) {
 				return fmt.Errorf("Must supply the volume to snap and a new name for the snapshot")
 			}
-			return nil
+			return validateBulkArgs(cmd, args)
 		},
 		RunE: volumeSnapshotExec,
 	}
@@ -60,6 +66,8 @@ var _ = commander.RegisterCommandVar(func() {
 var _ = commander.RegisterCommandInit(func() {
 	VolumeAddCommand(volumeSnapshotCmd)
 	volumeSnapshotCmd.Flags().StringVar(&csOpts.labelsAsString, "labels", "", "Comma separated list of labels as key-value pairs: 'k1=v1,k2=v2'")
+	volumeSnapshotCmd.Flags().StringVar(&csOpts.suffix, "suffix", "", "Suffix of the snapshot names when snapping multiple volumes. Snapshots are named <volume>-<suffix> (default is the current time)")
+	addBulkFlags(volumeSnapshotCmd)
 })
 
 func VolumeSnapshotAddCommand(cmd *cobra.Command) {
@@ -67,35 +75,64 @@ func VolumeSnapshotAddCommand(cmd *cobra.Command) {
 }
 
 func volumeSnapshotExec(cmd *cobra.Command, args []string) error {
-	ctx, conn, err := portworx.PxConnectDefault()
+	ctx, conn, err := portworx.PxConnectDefault(cmd.Context())
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
 
-	// Get name
-	csOpts.req.VolumeId = args[0]
-	csOpts.req.Name = args[1]
+	// The options are shared when the command runs in several contexts
+	req := proto.Clone(csOpts.req).(*api.SdkVolumeSnapshotCreateRequest)
 
 	// Get labels
 	if len(csOpts.labelsAsString) != 0 {
 		var err error
-		csOpts.req.Labels, err = util.CommaStringToStringMap(csOpts.labelsAsString)
+		req.Labels, err = util.CommaStringToStringMap(csOpts.labelsAsString)
 		if err != nil {
 			return fmt.Errorf("Failed to parse labels: %v\n", err)
 		}
 	}
 
-	// Send request
 	volumes := api.NewOpenStorageVolumeClient(conn)
-	resp, err := volumes.SnapshotCreate(ctx, csOpts.req)
+
+	if isBulk(cmd, args) {
+		names, err := getBulkVolumeNames(cmd, args, portworx.NewPxOpsWithConn(ctx, conn))
+		if err != nil {
+			return err
+		}
+		if ok, err := confirmBulk(cmd, "Snapshot", names); !ok {
+			return err
+		}
+		suffix := csOpts.suffix
+		if len(suffix) == 0 {
+			suffix = time.Now().Format("20060102-150405")
+		}
+		return runBulk(cmd, "Snapshot", names, func(name string) (string, error) {
+			resp, err := volumes.SnapshotCreate(ctx, &api.SdkVolumeSnapshotCreateRequest{
+				VolumeId: name,
+				Name:     name + "-" + suffix,
+				Labels:   req.GetLabels(),
+			})
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Snapshot %s created with id %s", name+"-"+suffix, resp.GetSnapshotId()), nil
+		})
+	}
+
+	// Get name
+	req.VolumeId = args[0]
+	req.Name = args[1]
+
+	// Send request
+	resp, err := volumes.SnapshotCreate(ctx, req)
 	if err != nil {
 		return util.PxErrorMessage(err, "Failed to create snapshot")
 	}
 
 	// Show user information
 	msg := fmt.Sprintf("Snapshot of %s created with id %s",
-		csOpts.req.GetVolumeId(),
+		req.GetVolumeId(),
 		resp.GetSnapshotId())
 
 	formattedOut := &util.DefaultFormatOutput{
@@ -104,5 +141,5 @@ func volumeSnapshotExec(cmd *cobra.Command, args []string) error {
 		Id:   []string{resp.GetSnapshotId()},
 	}
 
-	return util.PrintFormatted(formattedOut)
+	return util.PrintFormatted(cmd.Context(), formattedOut)
 }
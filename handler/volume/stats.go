@@ -16,13 +16,10 @@ limitations under the License.
 package volume
 
 import (
-	"bytes"
 	"fmt"
 	"strings"
-	"text/tabwriter"
 	"time"
 
-	"github.com/cheynewallace/tabby"
 	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
 	"github.com/portworx/pxc/pkg/cliops"
 	"github.com/portworx/pxc/pkg/commander"
@@ -38,7 +35,16 @@ var _ = commander.RegisterCommandVar(func() {
 	volumeStatsCmd = &cobra.Command{
 		Use:   "stats [NAME]",
 		Short: "Get stats of Portworx volumes",
-		RunE:  volumeStatsExec,
+		Example: `
+  # Monitor the stats of all volumes
+  pxc volume stats --watch
+
+  # Monitor the stats and record every refresh to a file
+  pxc volume stats --watch --record stats.jsonl
+
+  # Replay a recording at 10 times the recorded speed
+  pxc volume stats --replay stats.jsonl --speed 10`,
+		RunE: volumeStatsExec,
 	}
 })
 
@@ -48,11 +54,15 @@ var _ = commander.RegisterCommandInit(func() {
 	headers := strings.Join(allHeaders, "|")
 	sortMsg := fmt.Sprintf("Specify one of '%s' to sort on", headers)
 	volumeStatsCmd.Flags().StringP("selector", "l", "", "Selector (label query) comma-separated name=value pairs")
-	volumeStatsCmd.Flags().StringP("output", "o", "", "Output in yaml|json|wide")
+	volumeStatsCmd.Flags().StringP("output", "o", "", "Output in yaml|json|wide|name|custom-columns=<spec>|jsonpath=<template>|go-template=<template>")
+	volumeStatsCmd.Flags().Bool("no-headers", false, "Do not print the headers of tables")
 	volumeStatsCmd.Flags().String("sort-on", allHeaders[int(WRITE_TPUT)], sortMsg)
 	volumeStatsCmd.Flags().String("sort-order", "desc", "Sort in ascending or descending order. Specify one of asc|desc")
 	volumeStatsCmd.Flags().BoolP("watch", "w", false, "Monitor stats at a periodic interval")
 	volumeStatsCmd.Flags().DurationP("interval", "i", time.Second*2, "Specify refresh interval")
+	volumeStatsCmd.Flags().String("record", "", "Append the stats of every refresh to a file. Requires --watch")
+	volumeStatsCmd.Flags().String("replay", "", "Show the stats recorded with --record")
+	volumeStatsCmd.Flags().Float64("speed", 1, "Speed of the replay relative to the recording")
 	volumeStatsCmd.Flags().Bool("no-graphs", false, "Don't show graphs")
 	volumeStatsCmd.Flags().MarkHidden("no-graphs")
 })
@@ -92,6 +102,30 @@ func volumeStatsExec(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("sort-order should be one of asc or desc")
 	}
 
+	replay, _ := cmd.Flags().GetString("replay")
+	if len(replay) != 0 {
+		if len(args) != 0 {
+			return fmt.Errorf("Volume names cannot be provided with --replay")
+		}
+		speed, _ := cmd.Flags().GetFloat64("speed")
+		r, err := newStatsReplayer(replay, speed)
+		if err != nil {
+			return err
+		}
+		r.SetSortInfo(sortOn, so)
+		r.ShowSortMarker(true)
+		return displayStats(cmd, r, r.interval())
+	}
+
+	watch, err := cmd.Flags().GetBool("watch")
+	if err != nil {
+		return err
+	}
+	record, _ := cmd.Flags().GetString("record")
+	if len(record) != 0 && !watch {
+		return fmt.Errorf("--record can only be used with --watch")
+	}
+
 	// Parse out all of the common cli volume flags
 	cvi := cliops.NewCliInputs(cmd, args)
 
@@ -123,11 +157,18 @@ func volumeStatsExec(cmd *cobra.Command, args []string) error {
 	}
 
 	vsd := NewVolumeStats(cliOps, vs)
-	vsd.SetSortInfo(sortOn, so)
-	watch, err := cmd.Flags().GetBool("watch")
-	if err != nil {
-		return err
+	if len(record) != 0 {
+		rv, err := portworx.NewRecordingVolumes(vs, record)
+		if err != nil {
+			return fmt.Errorf("Failed to open stats recording: %v", err)
+		}
+		defer rv.Close()
+		vsd = &statsRecorder{
+			VolumeStats: NewVolumeStats(cliOps, rv),
+			volumes:     rv,
+		}
 	}
+	vsd.SetSortInfo(sortOn, so)
 
 	if watch {
 		vsd.ShowSortMarker(true)
@@ -135,7 +176,7 @@ func volumeStatsExec(cmd *cobra.Command, args []string) error {
 	} else {
 		vsd.ShowSortMarker(false)
 		volStatsFormatter := NewVolumeStatsGetFormatter(cliOps, vsd)
-		return util.PrintFormatted(volStatsFormatter)
+		return util.PrintFormatted(cmd.Context(), volStatsFormatter)
 	}
 }
 
@@ -147,7 +188,7 @@ func NewVolumeStatsGetFormatter(
 		cliOps: cliOps,
 		vsd:    vsd,
 	}
-	v.FormatType = cliOps.CliInputs().FormatType
+	v.BaseFormatOutput = cliOps.CliInputs().BaseFormatOutput
 	return v
 }
 
@@ -200,14 +241,11 @@ func (p *volumeStatsGetFormatter) DefaultFormat() (string, error) {
 	return p.toTabbed()
 }
 
-func (p *volumeStatsGetFormatter) toTabbed() (string, error) {
-	var b bytes.Buffer
-	writer := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
-	t := tabby.NewCustom(writer)
-
+// TableFormat returns the table of the volume stats
+func (p *volumeStatsGetFormatter) TableFormat() (*util.Table, error) {
 	err := p.vsd.Refresh()
 	if err != nil {
-		return "", nil
+		return nil, nil
 	}
 
 	h := p.vsd.GetHeaders()
@@ -215,11 +253,12 @@ func (p *volumeStatsGetFormatter) toTabbed() (string, error) {
 	for i, _ := range h {
 		hi[i] = h[i]
 	}
+	t := &util.Table{}
 	t.AddHeader(hi...)
 	for {
 		line, err := p.vsd.NextRow()
 		if err != nil {
-			return "", nil
+			return nil, nil
 		}
 		if len(line) == 0 {
 			break
@@ -230,8 +269,15 @@ func (p *volumeStatsGetFormatter) toTabbed() (string, error) {
 		}
 		t.AddLine(l...)
 	}
-	t.Print()
-	return b.String(), nil
+	return t, nil
+}
+
+func (p *volumeStatsGetFormatter) toTabbed() (string, error) {
+	t, err := p.TableFormat()
+	if err != nil || t == nil {
+		return "", err
+	}
+	return t.Tabbed(), nil
 }
 
 func doWatch(
@@ -247,7 +293,14 @@ func doWatch(
 	if interval < 2*time.Second {
 		return fmt.Errorf("--interval should not be less than 2s")
 	}
+	return displayStats(cmd, vsd, interval)
+}
 
+func displayStats(
+	cmd *cobra.Command,
+	vsd VolumeStats,
+	interval time.Duration,
+) error {
 	noGraphs, err := cmd.Flags().GetBool("no-graphs")
 	if err != nil {
 		return err
@@ -16,21 +16,26 @@ limitations under the License.
 package volume
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"sort"
 	"strings"
+	"text/tabwriter"
 	"time"
 
+	"github.com/cheynewallace/tabby"
 	humanize "github.com/dustin/go-humanize"
 	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
 	"github.com/portworx/pxc/pkg/cliops"
 	"github.com/portworx/pxc/pkg/portworx"
 	"github.com/portworx/pxc/pkg/tui"
+	"github.com/portworx/pxc/pkg/util"
 )
 
 type VolumeStats interface {
 	tui.StatsModel
+	tui.DetailModel
 	// Returns the volumes that this Stats are for
 	GetVolumes() ([]*api.Volume, error)
 	// Set if we need to show the sort marker in the column header
@@ -165,7 +170,75 @@ func (vsd *volumeStatsData) Refresh() error {
 }
 
 func (vsd *volumeStatsData) GetTitle() string {
-	return "Volume Stats (Press: q to quit; s to toggle sorting order; h|l to shift sort column); r to refresh"
+	return "Volume Stats (Press ? for help)"
+}
+
+// GetDetail returns the state, replication and consumer pods of the volume
+// in the row
+func (vsd *volumeStatsData) GetDetail(row []string) (string, error) {
+	if vsd.cliOps == nil {
+		return "Details are not available in a replay", nil
+	}
+
+	name := row[0]
+	vols, err := portworx.NewVolumes(vsd.cliOps.PxOps(), &portworx.VolumeSpec{
+		VolNames: []string{name},
+	}).GetVolumes()
+	if err != nil {
+		return "", err
+	}
+	if len(vols) == 0 {
+		return "", fmt.Errorf("Volume %s not found", name)
+	}
+	v := vols[0]
+
+	nodes, err := portworx.NewNodesForVolumes(vsd.cliOps.PxOps(), vols)
+	if err != nil {
+		return "", err
+	}
+	state, err := nodes.GetAttachedState(v)
+	if err != nil {
+		return "", err
+	}
+	replInfo, err := nodes.GetReplicationInfo(v)
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	t := tabby.NewCustom(tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0))
+	t.AddLine("Volume:", v.GetLocator().GetName())
+	t.AddLine("Id:", v.GetId())
+	t.AddLine("Status:", portworx.PrettyStatus(v))
+	t.AddLine("State:", state)
+	t.AddLine("Replication Status:", replInfo.Status)
+	for _, rsi := range replInfo.Rsi {
+		t.AddLine("  Set:", rsi.Id)
+		util.AddArray(t, "    Node:", rsi.NodeInfo)
+		if len(rsi.HaIncrease) > 0 {
+			t.AddLine("    HA-Increase on:", rsi.HaIncrease)
+		}
+	}
+
+	// Pods only use the volumes provisioned for a PVC in their namespace
+	namespace := v.GetLocator().GetVolumeLabels()["namespace"]
+	if len(namespace) != 0 {
+		pods, err := portworx.NewPods(vsd.cliOps.COps(), &portworx.PodSpec{
+			Namespace: namespace,
+		}).PodsUsingVolume(v)
+		if err != nil {
+			return "", err
+		}
+		if len(pods) != 0 {
+			t.AddLine("Pods:")
+		}
+		for _, pod := range pods {
+			t.AddLine("  - Name:", pod.GetNamespace()+"/"+pod.GetName())
+			t.AddLine("    Running on:", pod.Spec.NodeName)
+		}
+	}
+	t.Print()
+	return b.String(), nil
 }
 
 func (vsd *volumeStatsData) GetHeaders() []string {
@@ -277,12 +350,12 @@ func (vsd *volumeStatsData) getStats(v *api.Volume) (*statsData, error) {
 	sd.numReads = stats.GetReads()
 	sd.bytesWritten = stats.GetWriteBytes()
 	sd.numWrites = stats.GetWrites()
-	sd.iops = iops(stats)
+	sd.iops = portworx.Iops(stats)
 	sd.ioProgress = stats.GetIoProgress()
-	sd.readTput = readThroughput(stats)
-	sd.writeTput = writeThroughput(stats)
-	sd.readLat = readLatency(stats)
-	sd.writeLat = writeLatency(stats)
+	sd.readTput = portworx.ReadThroughput(stats)
+	sd.writeTput = portworx.WriteThroughput(stats)
+	sd.readLat = portworx.ReadLatency(stats)
+	sd.writeLat = portworx.WriteLatency(stats)
 
 	vsd.curTotal.bytesRead += sd.bytesRead
 	vsd.curTotal.numReads += sd.numReads
@@ -297,48 +370,6 @@ func (vsd *volumeStatsData) getStats(v *api.Volume) (*statsData, error) {
 	return sd, nil
 }
 
-func toSec(ms uint64) uint64 {
-	return ms / 1000
-}
-
-func iops(s *api.Stats) uint64 {
-	intv := toSec(s.GetIntervalMs())
-	if intv == 0 {
-		return 0
-	}
-	return (s.GetWrites() + s.GetReads()) / intv
-}
-
-func writeThroughput(s *api.Stats) uint64 {
-	intv := toSec(s.GetIntervalMs())
-	if intv == 0 {
-		return 0
-	}
-	return (s.GetWriteBytes()) / intv
-}
-
-func readThroughput(s *api.Stats) uint64 {
-	intv := toSec(s.GetIntervalMs())
-	if intv == 0 {
-		return 0
-	}
-	return (s.GetReadBytes()) / intv
-}
-
-func readLatency(s *api.Stats) uint64 {
-	if s.GetReads() == 0 {
-		return 0
-	}
-	return (uint64)((s.GetReadMs() * 1000) / s.GetReads())
-}
-
-func writeLatency(s *api.Stats) uint64 {
-	if s.GetWrites() == 0 {
-		return 0
-	}
-	return (uint64)((s.GetWriteMs() * 1000) / s.GetWrites())
-}
-
 func (vsd *volumeStatsData) GetGraphTitle(index int) (string, error) {
 	if index >= len(graphTitles) {
 		return "", fmt.Errorf("Unknown index %d for graph title", index)
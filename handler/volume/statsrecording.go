@@ -0,0 +1,117 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package volume
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+)
+
+// minReplayInterval is the shortest time between refreshes of a replay
+const minReplayInterval = 100 * time.Millisecond
+
+// statsRecorder writes the stats of every refresh to a recording file
+type statsRecorder struct {
+	VolumeStats
+	volumes *portworx.RecordingVolumes
+}
+
+func (r *statsRecorder) Refresh() error {
+	if err := r.VolumeStats.Refresh(); err != nil {
+		return err
+	}
+	return r.volumes.Record(time.Now())
+}
+
+// statsReplayer shows the frames of a recording. The frame shown is the one
+// recorded at the time elapsed since the start of the replay multiplied by
+// the speed, so refreshes caused by key presses do not skip frames.
+type statsReplayer struct {
+	VolumeStats
+	volumes  *portworx.ReplayVolumes
+	filename string
+	speed    float64
+	// recorded is the time of the first frame
+	recorded time.Time
+	start    time.Time
+	// pausedAt is the time the view was paused, or zero when not paused
+	pausedAt time.Time
+}
+
+func newStatsReplayer(filename string, speed float64) (*statsReplayer, error) {
+	if speed <= 0 {
+		return nil, fmt.Errorf("--speed must be greater than 0")
+	}
+	frames, err := portworx.ReadStatsRecording(filename)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read stats recording: %v", err)
+	}
+	rv := portworx.NewReplayVolumes(frames)
+	return &statsReplayer{
+		VolumeStats: NewVolumeStats(nil, rv),
+		volumes:     rv,
+		filename:    filename,
+		speed:       speed,
+		recorded:    frames[0].Time,
+	}, nil
+}
+
+func (r *statsReplayer) Refresh() error {
+	if r.start.IsZero() {
+		r.start = time.Now()
+	}
+	now := time.Now()
+	if !r.pausedAt.IsZero() {
+		now = r.pausedAt
+	}
+	elapsed := time.Duration(float64(now.Sub(r.start)) * r.speed)
+	r.volumes.Seek(r.recorded.Add(elapsed))
+	return r.VolumeStats.Refresh()
+}
+
+// SetPaused stops the replay while the view is paused
+func (r *statsReplayer) SetPaused(paused bool) {
+	if paused {
+		r.pausedAt = time.Now()
+		return
+	}
+	if !r.pausedAt.IsZero() {
+		r.start = r.start.Add(time.Since(r.pausedAt))
+		r.pausedAt = time.Time{}
+	}
+}
+
+func (r *statsReplayer) GetTitle() string {
+	end := ""
+	if r.volumes.End() {
+		end = ", end of recording"
+	}
+	return fmt.Sprintf("Replay of %s at %s%s (Press ? for help)",
+		r.filename, r.volumes.Current().Time.Format(util.TimeFormat), end)
+}
+
+// interval returns the time between refreshes to show every frame at the
+// speed of the replay
+func (r *statsReplayer) interval() time.Duration {
+	i := time.Duration(float64(r.volumes.Interval()) / r.speed)
+	if i < minReplayInterval {
+		return minReplayInterval
+	}
+	return i
+}
@@ -0,0 +1,115 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package volume
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/portworx/pxc/pkg/commander"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var waitVolumeCmd *cobra.Command
+
+var _ = commander.RegisterCommandVar(func() {
+	waitVolumeCmd = &cobra.Command{
+		Use:   "wait [NAME]",
+		Short: "Wait for a condition on a volume",
+		Long: `Wait until a volume meets a condition. The supported conditions are:
+
+  status=up           The volume is up
+  status=attached     The volume is attached
+  status=detached     The volume is detached
+  status=resync-done  No replica is being resynced or added
+  status=deleted      The volume does not exist
+  ha=N                The volume has N replicas in sync
+  size=N              The volume has at least N GiB`,
+		Example: `
+  # Wait until the volume myvolume has 3 replicas in sync
+  pxc volume wait myvolume --for=ha=3
+
+  # Wait up to 30 minutes for the resync of myvolume to finish
+  pxc volume wait myvolume --for=status=resync-done --timeout=30m`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("Must supply the name of the volume")
+			}
+			return nil
+		},
+		RunE: waitVolumeExec,
+	}
+})
+
+var _ = commander.RegisterCommandInit(func() {
+	VolumeAddCommand(waitVolumeCmd)
+	waitVolumeCmd.Flags().String("for", "", "Condition to wait for")
+	waitVolumeCmd.Flags().Duration("timeout", portworx.DefaultVolumeWaitTimeout, "Time to wait for the condition")
+	waitVolumeCmd.MarkFlagRequired("for")
+})
+
+func WaitAddCommand(cmd *cobra.Command) {
+	waitVolumeCmd.AddCommand(cmd)
+}
+
+func waitVolumeExec(cmd *cobra.Command, args []string) error {
+	s, _ := cmd.Flags().GetString("for")
+	condition, err := portworx.ParseVolumeCondition(s)
+	if err != nil {
+		return err
+	}
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	if err := waitForVolume(cmd.Context(), args[0], condition, timeout); err != nil {
+		return err
+	}
+	util.Printf("Volume %s is %s\n", args[0], condition)
+	return nil
+}
+
+// addWaitFlags adds the flags to wait for the operation to complete
+func addWaitFlags(cmd *cobra.Command, what string) {
+	cmd.Flags().Bool("wait", false, "Wait until "+what)
+	cmd.Flags().Duration("timeout", portworx.DefaultVolumeWaitTimeout, "Time to wait when --wait is provided")
+}
+
+// waitIfRequested waits for the volume to meet the condition if --wait
+// was provided
+func waitIfRequested(cmd *cobra.Command, name string, condition *portworx.VolumeCondition) error {
+	if wait, _ := cmd.Flags().GetBool("wait"); !wait {
+		return nil
+	}
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	return waitForVolume(cmd.Context(), name, condition, timeout)
+}
+
+func waitForVolume(
+	ctx context.Context,
+	name string,
+	condition *portworx.VolumeCondition,
+	timeout time.Duration,
+) error {
+	pxops, err := portworx.NewPxOps(ctx)
+	if err != nil {
+		return err
+	}
+	defer pxops.Close()
+
+	return portworx.WaitForVolume(pxops, name, condition, timeout)
+}
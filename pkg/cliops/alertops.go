@@ -28,6 +28,7 @@ type CliAlertOps struct {
 
 func GetCliAlertInputs(cmd *cobra.Command, args []string) *portworx.CliAlertInputs {
 	output, _ := cmd.Flags().GetString("output")
+	noHeaders, _ := cmd.Flags().GetBool("no-headers")
 	alertType, _ := cmd.Flags().GetString("type")
 	alertId, _ := cmd.Flags().GetString("id")
 	startTime, _ := cmd.Flags().GetString("start-time")
@@ -37,7 +38,10 @@ func GetCliAlertInputs(cmd *cobra.Command, args []string) *portworx.CliAlertInpu
 	return &portworx.CliAlertInputs{
 		BaseFormatOutput: util.BaseFormatOutput{
 			FormatType: output,
+			NoHeaders:  noHeaders,
 		},
+		// The csv and markdown outputs have the columns of the wide output
+		Wide:       output == util.FORMAT_WIDE || output == util.FORMAT_CSV || output == util.FORMAT_MARKDOWN,
 		AlertType:  alertType,
 		AlertId:    alertId,
 		StartTime:  startTime,
@@ -30,9 +30,11 @@ type CliAuthOps struct {
 // GetCliAuthInputs gets all CLI auth inputs
 func GetCliAuthInputs(cmd *cobra.Command, args []string) *portworx.CliAuthInputs {
 	output, _ := cmd.Flags().GetString("output")
+	noHeaders, _ := cmd.Flags().GetBool("no-headers")
 	return &portworx.CliAuthInputs{
 		BaseFormatOutput: util.BaseFormatOutput{
 			FormatType: output,
+			NoHeaders:  noHeaders,
 		},
 	}
 }
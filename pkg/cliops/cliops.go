@@ -16,6 +16,7 @@ limitations under the License.
 package cliops
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/portworx/pxc/pkg/kubernetes"
@@ -32,6 +33,10 @@ type CliInputs struct {
 	Owner         string
 	Labels        map[string]string
 	Args          []string
+	ListOptions   portworx.ListOptions
+	// ctx is the context of the command, which has the configuration
+	// to connect with
+	ctx context.Context
 }
 
 type CliOps interface {
@@ -64,8 +69,11 @@ func GetCliOps() CliOps {
 // NewCliVolumeInputs looks for all of the common flags and create a new cliVolumeInputs object
 func NewCliInputs(cmd *cobra.Command, args []string) *CliInputs {
 	output, _ := cmd.Flags().GetString("output")
+	noHeaders, _ := cmd.Flags().GetBool("no-headers")
+	rawUnits, _ := cmd.Flags().GetBool("raw-units")
+	// The csv and markdown outputs have the columns of the wide output
 	wide := false
-	if output == "wide" {
+	if output == util.FORMAT_WIDE || output == util.FORMAT_CSV || output == util.FORMAT_MARKDOWN {
 		wide = true
 	}
 	showLabels, _ := cmd.Flags().GetBool("show-labels")
@@ -76,10 +84,15 @@ func NewCliInputs(cmd *cobra.Command, args []string) *CliInputs {
 	mlabels, _ := util.CommaStringToStringMap(labels)
 
 	allNamespaces, _ := cmd.Flags().GetBool("all-namespaces")
+	sortBy, _ := cmd.Flags().GetString("sort-by")
+	fieldSelector, _ := cmd.Flags().GetString("field-selector")
+	limit, _ := cmd.Flags().GetInt("limit")
 	// If valid label is present, we need to pass it.
 	return &CliInputs{
 		BaseFormatOutput: util.BaseFormatOutput{
 			FormatType: output,
+			NoHeaders:  noHeaders,
+			RawUnits:   rawUnits,
 		},
 		Wide:          wide,
 		Owner:         owner,
@@ -87,9 +100,20 @@ func NewCliInputs(cmd *cobra.Command, args []string) *CliInputs {
 		AllNamespaces: allNamespaces,
 		Args:          args,
 		Labels:        mlabels,
+		ListOptions: portworx.ListOptions{
+			SortBy:        sortBy,
+			FieldSelector: fieldSelector,
+			Limit:         limit,
+		},
+		ctx: cmd.Context(),
 	}
 }
 
+// Context returns the context of the command the inputs were read from
+func (ci *CliInputs) Context() context.Context {
+	return ci.ctx
+}
+
 func NewCliOps(ci *CliInputs) CliOps {
 	inst = &cliOps{
 		cliInputs: ci,
@@ -116,13 +140,13 @@ func (p *cliOps) Connect() error {
 		return nil
 	}
 
-	pxops, err := portworx.NewPxOps()
+	pxops, err := portworx.NewPxOps(p.cliInputs.ctx)
 	if err != nil {
 		return err
 	}
 	p.pxops = pxops
 
-	cops, err := kubernetes.NewCOps()
+	cops, err := kubernetes.NewCOps(p.cliInputs.ctx)
 	if err != nil {
 		return err
 	}
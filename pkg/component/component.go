@@ -196,7 +196,7 @@ func (c *Component) rootPersistentPreRunE(cmd *cobra.Command, args []string) err
 
 func (c *Component) rootPersistentPostRunE(cmd *cobra.Command, args []string) error {
 	// Close the global Portworx API tunnel if any
-	kubernetes.StopTunnel()
+	kubernetes.StopTunnel(cmd.Context())
 
 	return nil
 }
@@ -16,6 +16,7 @@ limitations under the License.
 package config
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/portworx/pxc/pkg/util"
@@ -38,6 +39,9 @@ var (
 	gcm *ConfigManager
 )
 
+// cmContextKey is the key of the config manager in a context.Context
+type cmContextKey struct{}
+
 // CM returns the instance to the config manager
 func CM() *ConfigManager {
 	if gcm == nil {
@@ -47,6 +51,24 @@ func CM() *ConfigManager {
 	return gcm
 }
 
+// WithConfigManager returns a copy of ctx which carries the config manager.
+// Commands which run in several contexts at the same time use it to get
+// the configuration of their context instead of the global one.
+func WithConfigManager(ctx context.Context, cm *ConfigManager) context.Context {
+	return context.WithValue(ctx, cmContextKey{}, cm)
+}
+
+// CMFromContext returns the config manager carried by ctx, or the global
+// config manager if there is none
+func CMFromContext(ctx context.Context) *ConfigManager {
+	if ctx != nil {
+		if cm, ok := ctx.Value(cmContextKey{}).(*ConfigManager); ok {
+			return cm
+		}
+	}
+	return CM()
+}
+
 func newConfig() *Config {
 	return &Config{
 		Clusters:  make(map[string]*Cluster),
@@ -65,6 +87,12 @@ func NewConfigManagerForContext(context string) (*ConfigManager, error) {
 		Flags:  newConfigFlags(),
 	}
 
+	// Connection settings given on the command line apply to all contexts
+	if gcm != nil {
+		configManager.Flags.RequestTimeout = gcm.Flags.RequestTimeout
+		configManager.Flags.Retries = gcm.Flags.Retries
+	}
+
 	if util.InKubectlPluginMode() {
 		configManager.configrw = NewKubernetesConfigManagerForContext(context)
 	} else {
@@ -106,34 +134,34 @@ func (cm *ConfigManager) Load() error {
 		return err
 	}
 
+	// Use the context requested, if any
+	if len(cm.Flags.Context) != 0 {
+		if _, ok := cm.Config.Contexts[cm.Flags.Context]; !ok {
+			return fmt.Errorf("could not find the context '%s'", cm.Flags.Context)
+		}
+		cm.Config.CurrentContext = cm.Flags.Context
+	}
+
 	// Override with flags
 	cm.override()
 
 	return nil
 }
 
+// ForEachContext calls handler for each context. The ctx given to handler
+// carries the config manager of the context.
 func (cm *ConfigManager) ForEachContext(
-	handler func(context, clusterName string) error,
+	ctx context.Context,
+	handler func(ctx context.Context, contextName, clusterName string) error,
 ) {
-	// get current cluster
-	original := CM()
-	originalK := KM()
-
-	// defer resetting cluster
-	defer func() {
-		SetCM(original)
-		SetKM(originalK)
-	}()
-
-	for name, context := range cm.Config.Contexts {
+	for name, c := range cm.Config.Contexts {
 		newCm, err := NewConfigManagerForContext(name)
 		if err != nil {
-			panic("here")
+			logrus.Errorf("Failed to load context %s: %v", name, err)
+			continue
 		}
-		SetCM(newCm)
-		SetKM(NewKubernetesConfigManagerForContext(name))
 
-		err = handler(name, context.Cluster)
+		err = handler(WithConfigManager(ctx, newCm), name, c.Cluster)
 		if err != nil {
 			logrus.Errorf("Failed to comm with cluster %s: %v", name, err)
 		}
@@ -165,6 +193,15 @@ func (cm *ConfigManager) RunInNamedContext(contextName string, handler func() er
 	return handler()
 }
 
+// KM returns the Kubernetes configuration of the context in kubectl plugin
+// mode, or the global one
+func (cm *ConfigManager) KM() *KubernetesConfigManager {
+	if k, ok := cm.configrw.(*KubernetesConfigManager); ok {
+		return k
+	}
+	return KM()
+}
+
 // GetFlags returns all the pxc persistent flags
 func (cm *ConfigManager) GetFlags() *ConfigFlags {
 	return cm.Flags
@@ -186,7 +223,19 @@ func (cm *ConfigManager) GetEndpoint() string {
 	if len(cm.tunnelEndpoint) != 0 {
 		return cm.tunnelEndpoint
 	}
-	return cm.GetCurrentCluster().Endpoint
+	if endpoints := cm.GetEndpoints(); len(endpoints) != 0 {
+		return endpoints[0]
+	}
+	return ""
+}
+
+// GetEndpoints returns either all the saved endpoints in the config file or the
+// tunneled local endpoint
+func (cm *ConfigManager) GetEndpoints() []string {
+	if len(cm.tunnelEndpoint) != 0 {
+		return []string{cm.tunnelEndpoint}
+	}
+	return cm.GetCurrentCluster().GetEndpoints()
 }
 
 // GetCurrentCluster returns configuration information about the current cluster
@@ -18,6 +18,8 @@ package config
 import (
 	"encoding/base64"
 	"encoding/json"
+
+	"github.com/portworx/pxc/pkg/util"
 )
 
 const (
@@ -29,6 +31,11 @@ const (
 	DefaultClusterTunnelServiceNamespace = "kube-system"
 	DefaultClusterTunnelServiceName      = "portworx-api"
 	DefaultClusterTunnelServicePort      = "9020"
+
+	// TransportGrpc connects to the Portworx gRPC SDK server
+	TransportGrpc = "grpc"
+	// TransportRest connects to the Portworx SDK REST gateway
+	TransportRest = "rest"
 )
 
 var (
@@ -56,6 +63,36 @@ type Cluster struct {
 	Endpoint   string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
 	Secure     bool   `json:"secure,omitempty" yaml:"secure,omitempty"`
 
+	// Transport is the protocol used to talk to the endpoints: grpc or rest.
+	// If not set, grpc is used.
+	Transport string `json:"transport,omitempty" yaml:"transport,omitempty"`
+
+	// Endpoints provides additional endpoints for the cluster used for failover
+	Endpoints []string `json:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	// LoadBalancing is the policy used across endpoints: round_robin or pick_first
+	LoadBalancing string `json:"load-balancing,omitempty" yaml:"load-balancing,omitempty"`
+
+	// Client certificate and key used for mutual TLS
+	ClientCert     string `json:"client-certificate,omitempty" yaml:"client-certificate,omitempty"`
+	ClientCertData []byte `json:"client-certificate-data,omitempty" yaml:"client-certificate-data,omitempty"`
+	ClientKey      string `json:"client-key,omitempty" yaml:"client-key,omitempty"`
+	ClientKeyData  []byte `json:"client-key-data,omitempty" yaml:"client-key-data,omitempty"`
+
+	// TLSServerName overrides the server name used to verify the server certificate
+	TLSServerName string `json:"tls-server-name,omitempty" yaml:"tls-server-name,omitempty"`
+
+	// InsecureSkipTLSVerify disables verification of the server certificate
+	InsecureSkipTLSVerify bool `json:"insecure-skip-tls-verify,omitempty" yaml:"insecure-skip-tls-verify,omitempty"`
+
+	// Keepalive settings for the gRPC connection as duration strings
+	KeepaliveTime    string `json:"keepalive-time,omitempty" yaml:"keepalive-time,omitempty"`
+	KeepaliveTimeout string `json:"keepalive-timeout,omitempty" yaml:"keepalive-timeout,omitempty"`
+
+	// ProxyURL is an HTTP, HTTPS or SOCKS5 proxy used to reach the endpoints
+	ProxyURL string `json:"proxy-url,omitempty" yaml:"proxy-url,omitempty"`
+	// DisableProxyEnv ignores the standard proxy environment variables
+	DisableProxyEnv bool `json:"disable-proxy-env,omitempty" yaml:"disable-proxy-env,omitempty"`
+
 	TunnelServiceNamespace string `json:"tunnelServiceNamespace,omitempty" yaml:"tunnelServiceNamespace,omitempty"`
 	TunnelServiceName      string `json:"tunnelServiceName,omitempty" yaml:"tunnelServiceName,omitempty"`
 	TunnelServicePort      string `json:"tunnelServicePort,omitempty" yaml:"tunnelServicePort,omitempty"`
@@ -150,6 +187,34 @@ func (c *Cluster) toEncodedString() (string, error) {
 	return es, nil
 }
 
+// GetEndpoints returns the endpoint and any additional endpoints of the
+// cluster without duplicates
+func (c *Cluster) GetEndpoints() []string {
+	endpoints := make([]string, 0, len(c.Endpoints)+1)
+	for _, e := range append([]string{c.Endpoint}, c.Endpoints...) {
+		if len(e) != 0 && !util.ListContains(endpoints, e) {
+			endpoints = append(endpoints, e)
+		}
+	}
+	return endpoints
+}
+
+// UsesRestTransport returns true if the cluster must be accessed through the REST gateway
+func (c *Cluster) UsesRestTransport() bool {
+	return c.Transport == TransportRest
+}
+
+// UsesTLS returns true if any of the TLS settings for the cluster are set
+func (c *Cluster) UsesTLS() bool {
+	return c.Secure ||
+		c.InsecureSkipTLSVerify ||
+		len(c.CACert) != 0 ||
+		len(c.CACertData) != 0 ||
+		len(c.ClientCert) != 0 ||
+		len(c.ClientCertData) != 0 ||
+		len(c.TLSServerName) != 0
+}
+
 // NewAuthInfo returns an empty pxc Authinfo
 func NewAuthInfo() *AuthInfo {
 	return &AuthInfo{
@@ -0,0 +1,36 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterGetEndpoints(t *testing.T) {
+	c := &Cluster{}
+	assert.Empty(t, c.GetEndpoints())
+
+	c.Endpoint = "1.1.1.1:9020"
+	assert.Equal(t, []string{"1.1.1.1:9020"}, c.GetEndpoints())
+
+	c.Endpoints = []string{"2.2.2.2:9020", "1.1.1.1:9020", "", "3.3.3.3:9020"}
+	assert.Equal(t, []string{"1.1.1.1:9020", "2.2.2.2:9020", "3.3.3.3:9020"}, c.GetEndpoints())
+
+	c.Endpoint = ""
+	assert.Equal(t, []string{"2.2.2.2:9020", "1.1.1.1:9020", "3.3.3.3:9020"}, c.GetEndpoints())
+}
@@ -18,6 +18,9 @@ package config
 import (
 	"os"
 	"path"
+	"time"
+
+	"github.com/portworx/pxc/pkg/util"
 
 	"github.com/spf13/pflag"
 
@@ -31,6 +34,10 @@ const (
 	PxDefaultDir        = ".pxc"
 	PxDefaultConfigName = "config.yml"
 
+	DefaultRequestTimeout = time.Minute
+	DefaultRetries        = 3
+	DefaultMaxConcurrency = 8
+
 	flagPrefix          = "pxc."
 	flagConfigFile      = flagPrefix + "config"
 	flagConfigDir       = flagPrefix + "config-dir"
@@ -39,6 +46,11 @@ const (
 	flagSecretName      = flagPrefix + "secret-name"
 	flagToken           = flagPrefix + "token"
 	flagVerbosity       = flagPrefix + "v"
+	flagRequestTimeout  = flagPrefix + "request-timeout"
+	flagRetries         = flagPrefix + "retries"
+	flagContexts        = flagPrefix + "contexts"
+	flagAllContexts     = flagPrefix + "all-contexts"
+	flagMaxConcurrency  = flagPrefix + "max-concurrency"
 )
 
 type ConfigFlags struct {
@@ -49,6 +61,11 @@ type ConfigFlags struct {
 	SecretName      string
 	Token           string
 	Verbosity       int32
+	RequestTimeout  time.Duration
+	Retries         uint
+	Contexts        []string
+	AllContexts     bool
+	MaxConcurrency  int
 }
 
 func newConfigFlags() *ConfigFlags {
@@ -67,7 +84,10 @@ func newConfigFlags() *ConfigFlags {
 	}
 
 	return &ConfigFlags{
-		ConfigDir: configDir,
+		ConfigDir:      configDir,
+		RequestTimeout: DefaultRequestTimeout,
+		Retries:        DefaultRetries,
+		MaxConcurrency: DefaultMaxConcurrency,
 	}
 }
 
@@ -109,4 +129,19 @@ func (c *ConfigFlags) addFlagsCommon(flags *pflag.FlagSet) {
 	flags.StringVar(&c.ConfigFile, flagConfigFile, c.ConfigFile, "Config file (default is $HOME/"+PxDefaultDir+"/"+PxDefaultConfigName+")")
 	flags.StringVar(&c.ConfigDir, flagConfigDir, c.ConfigDir, "Config directory")
 	flags.Int32Var(&c.Verbosity, flagVerbosity, c.Verbosity, "[0-3] Log level verbosity")
+	flags.DurationVar(&c.RequestTimeout, flagRequestTimeout, c.RequestTimeout, "Timeout for each request to Portworx. A value of 0 disables the timeout")
+	flags.UintVar(&c.Retries, flagRetries, c.Retries, "Number of times to retry read-only requests when Portworx is unavailable")
+	flags.StringSliceVar(&c.Contexts, flagContexts, c.Contexts, "Run the command in each context matching the comma separated list of globs. Example: 'prod-*'")
+	flags.BoolVar(&c.AllContexts, flagAllContexts, c.AllContexts, "Run the command in all contexts")
+	flags.IntVar(&c.MaxConcurrency, flagMaxConcurrency, c.MaxConcurrency, "Maximum number of contexts to run the command in at the same time")
+}
+
+// IsFanOut returns true if the command must be run in multiple contexts
+func (c *ConfigFlags) IsFanOut() bool {
+	return c.AllContexts || len(c.Contexts) != 0
+}
+
+// MatchesContext returns true if the named context was selected by the fan-out flags
+func (c *ConfigFlags) MatchesContext(name string) bool {
+	return c.AllContexts || util.ListMatchGlob(c.Contexts, name)
 }
@@ -0,0 +1,37 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFlagsFanOut(t *testing.T) {
+	c := &ConfigFlags{}
+	assert.False(t, c.IsFanOut())
+
+	c.Contexts = []string{"prod-*", "dev"}
+	assert.True(t, c.IsFanOut())
+	assert.True(t, c.MatchesContext("prod-east"))
+	assert.True(t, c.MatchesContext("dev"))
+	assert.False(t, c.MatchesContext("staging"))
+
+	c = &ConfigFlags{AllContexts: true}
+	assert.True(t, c.IsFanOut())
+	assert.True(t, c.MatchesContext("anything"))
+}
@@ -0,0 +1,253 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/portworx"
+	"github.com/portworx/pxc/pkg/util"
+)
+
+const metricPrefix = "pxc_"
+
+// Collector periodically collects the metrics of the volumes, nodes and
+// alerts of the cluster and serves the last collected metrics over http
+type Collector struct {
+	pxops    portworx.PxOps
+	alertOps portworx.PxAlertOps
+	workers  int
+
+	lock      sync.Mutex
+	metrics   []*Metric
+	success   bool
+	duration  time.Duration
+	timestamp time.Time
+}
+
+// NewCollector returns a collector getting the stats of up to workers
+// volumes at the same time
+func NewCollector(pxops portworx.PxOps, alertOps portworx.PxAlertOps, workers int) *Collector {
+	return &Collector{
+		pxops:    pxops,
+		alertOps: alertOps,
+		workers:  workers,
+	}
+}
+
+// Run collects the metrics every interval until stop is closed
+func (c *Collector) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.Collect(); err != nil {
+			util.Eprintf("Failed to collect metrics: %v\n", err)
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Collect gets the metrics from the cluster. When it fails the metrics of
+// the previous collection are kept.
+func (c *Collector) Collect() error {
+	start := time.Now()
+	metrics, err := c.collect()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.success = err == nil
+	c.duration = time.Since(start)
+	c.timestamp = start
+	if err == nil {
+		c.metrics = metrics
+	}
+	return err
+}
+
+func (c *Collector) collect() ([]*Metric, error) {
+	vols, err := portworx.NewVolumes(c.pxops, &portworx.VolumeSpec{}).GetVolumes()
+	if err != nil {
+		return nil, util.PxErrorMessage(err, "Failed to get volumes")
+	}
+	stats, err := portworx.GetVolumesStats(c.pxops, vols, c.workers)
+	if err != nil {
+		return nil, err
+	}
+	volumeMetrics := VolumeMetrics(vols, stats)
+
+	nodes, err := portworx.NewNodes(c.pxops, &portworx.NodeSpec{}).GetNodes()
+	if err != nil {
+		return nil, util.PxErrorMessage(err, "Failed to get nodes")
+	}
+
+	alerts, err := c.alertOps.GetPxAlerts(portworx.CliAlertInputs{AlertType: "all"})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := append(volumeMetrics, NodeMetrics(nodes)...)
+	return append(metrics, AlertMetrics(alerts.AlertResp)...), nil
+}
+
+// ServeHTTP writes the last collected metrics
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.lock.Lock()
+	metrics := append([]*Metric{}, c.metrics...)
+	metrics = append(metrics, c.exporterMetrics()...)
+	c.lock.Unlock()
+
+	var b bytes.Buffer
+	if err := WriteText(&b, metrics); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(b.Bytes())
+}
+
+func (c *Collector) exporterMetrics() []*Metric {
+	success := NewGauge(metricPrefix+"exporter_collection_success", "Whether the last collection succeeded")
+	success.Add(boolValue(c.success))
+	duration := NewGauge(metricPrefix+"exporter_collection_duration_seconds", "Duration of the last collection")
+	duration.Add(c.duration.Seconds())
+	timestamp := NewGauge(metricPrefix+"exporter_collection_timestamp_seconds", "Time of the last collection")
+	if !c.timestamp.IsZero() {
+		timestamp.Add(float64(c.timestamp.Unix()))
+	}
+	return []*Metric{success, duration, timestamp}
+}
+
+// VolumeMetrics returns the IO and usage metrics of the volumes. stats are
+// the stats of each volume since the previous collection, or nil if the
+// volume was deleted.
+func VolumeMetrics(vols []*api.Volume, stats []*api.Stats) []*Metric {
+	var (
+		iops       = NewGauge(metricPrefix+"volume_iops", "Reads and writes per second")
+		readTput   = NewGauge(metricPrefix+"volume_read_bytes_per_second", "Bytes read per second")
+		writeTput  = NewGauge(metricPrefix+"volume_write_bytes_per_second", "Bytes written per second")
+		readLat    = NewGauge(metricPrefix+"volume_read_latency_seconds", "Average latency of the reads")
+		writeLat   = NewGauge(metricPrefix+"volume_write_latency_seconds", "Average latency of the writes")
+		ioProgress = NewGauge(metricPrefix+"volume_io_depth", "IOs in progress")
+		used       = NewGauge(metricPrefix+"volume_used_bytes", "Bytes used by the volume")
+		size       = NewGauge(metricPrefix+"volume_size_bytes", "Provisioned size of the volume")
+	)
+	for i, v := range vols {
+		labels := volumeLabels(v)
+		used.Add(float64(v.GetUsage()), labels...)
+		size.Add(float64(v.GetSpec().GetSize()), labels...)
+
+		s := stats[i]
+		if s == nil {
+			continue
+		}
+		iops.Add(float64(portworx.Iops(s)), labels...)
+		readTput.Add(float64(portworx.ReadThroughput(s)), labels...)
+		writeTput.Add(float64(portworx.WriteThroughput(s)), labels...)
+		readLat.Add(microseconds(portworx.ReadLatency(s)), labels...)
+		writeLat.Add(microseconds(portworx.WriteLatency(s)), labels...)
+		ioProgress.Add(float64(s.GetIoProgress()), labels...)
+	}
+	return []*Metric{iops, readTput, writeTput, readLat, writeLat, ioProgress, used, size}
+}
+
+// volumeLabels returns the labels of the volume. The PVC and namespace are
+// set by Portworx on the volumes provisioned by Kubernetes.
+func volumeLabels(v *api.Volume) []string {
+	labels := v.GetLocator().GetVolumeLabels()
+	return []string{
+		"volume", v.GetLocator().GetName(),
+		"volume_id", v.GetId(),
+		"pvc", labels["pvc"],
+		"namespace", labels["namespace"],
+	}
+}
+
+// NodeMetrics returns the status and capacity metrics of the nodes and
+// their pools
+func NodeMetrics(nodes []*api.StorageNode) []*Metric {
+	var (
+		up        = NewGauge(metricPrefix+"node_up", "Whether the node status is up")
+		cpu       = NewGauge(metricPrefix+"node_cpu_percent", "CPU usage of the node")
+		memUsed   = NewGauge(metricPrefix+"node_memory_used_bytes", "Memory used on the node")
+		memTotal  = NewGauge(metricPrefix+"node_memory_total_bytes", "Memory of the node")
+		used      = NewGauge(metricPrefix+"node_used_bytes", "Bytes used in the pools of the node")
+		capacity  = NewGauge(metricPrefix+"node_capacity_bytes", "Total bytes of the pools of the node")
+		poolUsed  = NewGauge(metricPrefix+"pool_used_bytes", "Bytes used in the pool")
+		poolTotal = NewGauge(metricPrefix+"pool_capacity_bytes", "Total bytes of the pool")
+	)
+	for _, n := range nodes {
+		labels := []string{"node", n.GetHostname(), "node_id", n.GetId()}
+		up.Add(boolValue(n.GetStatus() == api.Status_STATUS_OK), labels...)
+		cpu.Add(n.GetCpu(), labels...)
+		memUsed.Add(float64(n.GetMemUsed()), labels...)
+		memTotal.Add(float64(n.GetMemTotal()), labels...)
+		u, c := portworx.GetTotalCapacity(n)
+		used.Add(float64(u), labels...)
+		capacity.Add(float64(c), labels...)
+		for _, pool := range n.GetPools() {
+			poolLabels := append(labels, "pool", fmt.Sprintf("%d", pool.GetID()))
+			poolUsed.Add(float64(pool.GetUsed()), poolLabels...)
+			poolTotal.Add(float64(pool.GetTotalSize()), poolLabels...)
+		}
+	}
+	return []*Metric{up, cpu, memUsed, memTotal, used, capacity, poolUsed, poolTotal}
+}
+
+// AlertMetrics returns the number of alerts which are not cleared by
+// severity
+func AlertMetrics(alerts []*api.Alert) []*Metric {
+	counts := map[api.SeverityType]int{
+		api.SeverityType_SEVERITY_TYPE_ALARM:   0,
+		api.SeverityType_SEVERITY_TYPE_WARNING: 0,
+		api.SeverityType_SEVERITY_TYPE_NOTIFY:  0,
+	}
+	for _, a := range alerts {
+		if !a.GetCleared() {
+			counts[a.GetSeverity()]++
+		}
+	}
+
+	m := NewGauge(metricPrefix+"alerts", "Alerts which are not cleared")
+	for _, severity := range []api.SeverityType{
+		api.SeverityType_SEVERITY_TYPE_ALARM,
+		api.SeverityType_SEVERITY_TYPE_WARNING,
+		api.SeverityType_SEVERITY_TYPE_NOTIFY,
+	} {
+		m.Add(float64(counts[severity]), "severity", strings.ToLower(portworx.SeverityString(severity)))
+	}
+	return []*Metric{m}
+}
+
+func microseconds(us uint64) float64 {
+	return float64(us) / 1e6
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,135 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package exporter
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/stretchr/testify/assert"
+)
+
+// sample returns the value of the sample of the metric with the label value
+func sample(m *Metric, label, value string) (float64, bool) {
+	for _, s := range m.Samples {
+		for _, l := range s.Labels {
+			if l.Name == label && l.Value == value {
+				return s.Value, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func TestVolumeMetrics(t *testing.T) {
+	vols := []*api.Volume{
+		{
+			Id:      "1",
+			Locator: &api.VolumeLocator{Name: "v1", VolumeLabels: map[string]string{"pvc": "data", "namespace": "db"}},
+			Spec:    &api.VolumeSpec{Size: 100},
+			Usage:   10,
+		},
+		{
+			Id:      "2",
+			Locator: &api.VolumeLocator{Name: "deleted"},
+		},
+	}
+	metrics := VolumeMetrics(vols, []*api.Stats{
+		{
+			Reads:      10,
+			Writes:     30,
+			ReadBytes:  4000,
+			ReadMs:     20,
+			IntervalMs: 2000,
+		},
+		// The volume was deleted
+		nil,
+	})
+
+	values := make(map[string]float64)
+	for _, m := range metrics {
+		if v, ok := sample(m, "volume", "v1"); ok {
+			values[m.Name] = v
+		}
+	}
+	assert.Equal(t, 20.0, values["pxc_volume_iops"])
+	assert.Equal(t, 2000.0, values["pxc_volume_read_bytes_per_second"])
+	assert.Equal(t, 0.002, values["pxc_volume_read_latency_seconds"])
+	assert.Equal(t, 10.0, values["pxc_volume_used_bytes"])
+	assert.Equal(t, 100.0, values["pxc_volume_size_bytes"])
+	assert.Equal(t, []Label{
+		{Name: "volume", Value: "v1"},
+		{Name: "volume_id", Value: "1"},
+		{Name: "pvc", Value: "data"},
+		{Name: "namespace", Value: "db"},
+	}, metrics[0].Samples[0].Labels)
+
+	// The deleted volume only has usage metrics
+	assert.Len(t, metrics[0].Samples, 1)
+}
+
+func TestNodeMetrics(t *testing.T) {
+	metrics := NodeMetrics([]*api.StorageNode{
+		{
+			Id:       "id1",
+			Hostname: "n1",
+			Status:   api.Status_STATUS_OK,
+			Pools:    []*api.StoragePool{{ID: 0, Used: 10, TotalSize: 100}, {ID: 1, Used: 20, TotalSize: 100}},
+		},
+		{Id: "id2", Hostname: "n2", Status: api.Status_STATUS_OFFLINE},
+	})
+	byName := make(map[string]*Metric)
+	for _, m := range metrics {
+		byName[m.Name] = m
+	}
+
+	v, _ := sample(byName["pxc_node_up"], "node", "n1")
+	assert.Equal(t, 1.0, v)
+	v, _ = sample(byName["pxc_node_up"], "node", "n2")
+	assert.Equal(t, 0.0, v)
+	v, _ = sample(byName["pxc_node_used_bytes"], "node", "n1")
+	assert.Equal(t, 30.0, v)
+	v, _ = sample(byName["pxc_pool_used_bytes"], "pool", "1")
+	assert.Equal(t, 20.0, v)
+	assert.Len(t, byName["pxc_pool_capacity_bytes"].Samples, 2)
+}
+
+func TestAlertMetrics(t *testing.T) {
+	metrics := AlertMetrics([]*api.Alert{
+		{Severity: api.SeverityType_SEVERITY_TYPE_ALARM},
+		{Severity: api.SeverityType_SEVERITY_TYPE_ALARM},
+		{Severity: api.SeverityType_SEVERITY_TYPE_ALARM, Cleared: true},
+		{Severity: api.SeverityType_SEVERITY_TYPE_NOTIFY},
+	})
+	assert.Len(t, metrics, 1)
+	v, _ := sample(metrics[0], "severity", "alarm")
+	assert.Equal(t, 2.0, v)
+	v, ok := sample(metrics[0], "severity", "warn")
+	assert.True(t, ok)
+	assert.Equal(t, 0.0, v)
+	v, _ = sample(metrics[0], "severity", "notify")
+	assert.Equal(t, 1.0, v)
+}
+
+func TestCollectorServeHTTP(t *testing.T) {
+	c := NewCollector(nil, nil, 1)
+	w := httptest.NewRecorder()
+	c.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, w.Body.String(), "pxc_exporter_collection_success 0\n")
+}
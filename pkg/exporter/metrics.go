@@ -0,0 +1,113 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	MetricTypeGauge   = "gauge"
+	MetricTypeCounter = "counter"
+)
+
+// Metric is a Prometheus metric and its samples
+type Metric struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []*Sample
+}
+
+// Sample is a value of a metric with its labels
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+// Label is the name and value of a label of a sample
+type Label struct {
+	Name  string
+	Value string
+}
+
+// NewGauge returns a metric of type gauge
+func NewGauge(name, help string) *Metric {
+	return &Metric{
+		Name:    name,
+		Help:    help,
+		Type:    MetricTypeGauge,
+		Samples: make([]*Sample, 0),
+	}
+}
+
+// Add adds a sample to the metric. labels are pairs of label names and values.
+func (m *Metric) Add(value float64, labels ...string) {
+	s := &Sample{Value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		s.Labels = append(s.Labels, Label{Name: labels[i], Value: labels[i+1]})
+	}
+	m.Samples = append(m.Samples, s)
+}
+
+// WriteText writes the metrics in the Prometheus text exposition format
+func WriteText(w io.Writer, metrics []*Metric) error {
+	b := bufio.NewWriter(w)
+	for _, m := range metrics {
+		fmt.Fprintf(b, "# HELP %s %s\n", m.Name, escapeHelp(m.Help))
+		fmt.Fprintf(b, "# TYPE %s %s\n", m.Name, m.Type)
+		for _, s := range m.Samples {
+			b.WriteString(m.Name)
+			if len(s.Labels) != 0 {
+				b.WriteString("{")
+				for i, l := range s.Labels {
+					if i != 0 {
+						b.WriteString(",")
+					}
+					fmt.Fprintf(b, "%s=\"%s\"", l.Name, escapeLabelValue(l.Value))
+				}
+				b.WriteString("}")
+			}
+			fmt.Fprintf(b, " %s\n", formatValue(s.Value))
+		}
+	}
+	return b.Flush()
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
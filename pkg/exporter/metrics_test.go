@@ -0,0 +1,45 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package exporter
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteText(t *testing.T) {
+	m := NewGauge("pxc_test", "A test\nmetric")
+	m.Add(1.5, "name", `a "quoted" \ value`, "other", "line\nbreak")
+	m.Add(2)
+	inf := NewGauge("pxc_inf", "Infinite")
+	inf.Add(math.Inf(1))
+	inf.Add(math.NaN())
+
+	var b bytes.Buffer
+	assert.NoError(t, WriteText(&b, []*Metric{m, inf}))
+	assert.Equal(t, `# HELP pxc_test A test\nmetric
+# TYPE pxc_test gauge
+pxc_test{name="a \"quoted\" \\ value",other="line\nbreak"} 1.5
+pxc_test 2
+# HELP pxc_inf Infinite
+# TYPE pxc_inf gauge
+pxc_inf +Inf
+pxc_inf NaN
+`, b.String())
+}
@@ -21,14 +21,29 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/portworx/pxc/pkg/util"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
 	"google.golang.org/grpc/connectivity"
+	_ "google.golang.org/grpc/health"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+const (
+	// LoadBalancingRoundRobin spreads the calls across all healthy endpoints
+	LoadBalancingRoundRobin = "round_robin"
+	// LoadBalancingPickFirst uses the first healthy endpoint in the list
+	LoadBalancingPickFirst = "pick_first"
+
+	dnsScheme      = "dns:///"
+	endpointScheme = "pxc"
 )
 
 // Connect address by grpc
@@ -48,6 +63,97 @@ func Connect(address string, dialOptions []grpc.DialOption) (*grpc.ClientConn, e
 		return nil, err
 	}
 
+	return waitForReady(conn, address)
+}
+
+// ConnectToEndpoints connects to one of the endpoints provided using the
+// load balancing policy requested. An endpoint in the form dns:///host:port
+// will be resolved to all of its addresses. Subchannels are health checked
+// so that unhealthy endpoints are skipped.
+func ConnectToEndpoints(
+	endpoints []string,
+	loadBalancing string,
+	dialOptions []grpc.DialOption,
+) (*grpc.ClientConn, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("No endpoints provided")
+	}
+	if len(loadBalancing) == 0 {
+		loadBalancing = LoadBalancingPickFirst
+	}
+	if err := ValidateLoadBalancing(loadBalancing); err != nil {
+		return nil, err
+	}
+
+	// Nothing to balance
+	if len(endpoints) == 1 && !strings.HasPrefix(endpoints[0], dnsScheme) {
+		return Connect(endpoints[0], dialOptions)
+	}
+
+	var target string
+	if len(endpoints) == 1 {
+		// Let gRPC resolve all the addresses of the name
+		target = endpoints[0]
+	} else {
+		addresses := make([]resolver.Address, 0, len(endpoints))
+		for _, endpoint := range endpoints {
+			if strings.HasPrefix(endpoint, dnsScheme) {
+				return nil, fmt.Errorf("DNS endpoint %s cannot be used with other endpoints", endpoint)
+			}
+			host, _, err := net.SplitHostPort(endpoint)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid endpoint %s: %v", endpoint, err)
+			}
+			addresses = append(addresses, resolver.Address{
+				Addr:       endpoint,
+				ServerName: host,
+			})
+		}
+		r := manual.NewBuilderWithScheme(endpointScheme)
+		r.InitialState(resolver.State{Addresses: addresses})
+		dialOptions = append(dialOptions, grpc.WithResolvers(r))
+		target = endpointScheme + ":///" + strings.Join(endpoints, ",")
+	}
+
+	dialOptions = append(dialOptions,
+		grpc.WithDefaultServiceConfig(serviceConfig(loadBalancing)),
+		grpc.WithConnectParams(connectParams()))
+	conn, err := grpc.Dial(target, dialOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return waitForReady(conn, strings.Join(endpoints, ","))
+}
+
+// connectParams returns the connection parameters which reconnect to an
+// endpoint after at most a second. MinConnectTimeout is the default of
+// grpc, which is otherwise set to zero.
+func connectParams() grpc.ConnectParams {
+	b := backoff.DefaultConfig
+	b.MaxDelay = time.Second
+	return grpc.ConnectParams{
+		Backoff:           b,
+		MinConnectTimeout: 20 * time.Second,
+	}
+}
+
+// ValidateLoadBalancing returns an error if the load balancing policy is not supported
+func ValidateLoadBalancing(loadBalancing string) error {
+	switch loadBalancing {
+	case LoadBalancingRoundRobin, LoadBalancingPickFirst:
+		return nil
+	}
+	return fmt.Errorf("Unsupported load balancing policy %s. Must be %s or %s",
+		loadBalancing, LoadBalancingRoundRobin, LoadBalancingPickFirst)
+}
+
+func serviceConfig(loadBalancing string) string {
+	return fmt.Sprintf(`{"loadBalancingConfig":[{"%s":{}}],"healthCheckConfig":{"serviceName":""}}`,
+		loadBalancing)
+}
+
+func waitForReady(conn *grpc.ClientConn, address string) (*grpc.ClientConn, error) {
 	// We wait for 1 minute until conn.GetState() is READY.
 	// The interval for this check is 1 second.
 	if err := util.WaitFor(5*time.Second, 10*time.Millisecond, func() (bool, error) {
@@ -0,0 +1,101 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+)
+
+func startHealthServer(t *testing.T) (string, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	s := grpc.NewServer()
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(l)
+
+	return l.Addr().String(), s.Stop
+}
+
+// unusedEndpoint returns an address where nothing is listening
+func unusedEndpoint(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := l.Addr().String()
+	l.Close()
+	return address
+}
+
+func peerOfCall(t *testing.T, conn *grpc.ClientConn) string {
+	var p peer.Peer
+	_, err := healthpb.NewHealthClient(conn).Check(context.Background(),
+		&healthpb.HealthCheckRequest{}, grpc.Peer(&p))
+	assert.NoError(t, err)
+	return p.Addr.String()
+}
+
+func TestConnectToEndpointsPickFirstFailover(t *testing.T) {
+	live, stop := startHealthServer(t)
+	defer stop()
+	dead := unusedEndpoint(t)
+
+	conn, err := ConnectToEndpoints([]string{dead, live}, LoadBalancingPickFirst,
+		[]grpc.DialOption{grpc.WithInsecure()})
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	assert.Equal(t, live, peerOfCall(t, conn))
+}
+
+func TestConnectToEndpointsRoundRobin(t *testing.T) {
+	first, stopFirst := startHealthServer(t)
+	defer stopFirst()
+	second, stopSecond := startHealthServer(t)
+	defer stopSecond()
+
+	conn, err := ConnectToEndpoints([]string{first, second}, LoadBalancingRoundRobin,
+		[]grpc.DialOption{grpc.WithInsecure()})
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	// Calls must eventually be sent to both endpoints
+	seen := map[string]bool{}
+	for i := 0; i < 20 && len(seen) < 2; i++ {
+		seen[peerOfCall(t, conn)] = true
+	}
+	assert.True(t, seen[first])
+	assert.True(t, seen[second])
+}
+
+func TestConnectToEndpointsErrors(t *testing.T) {
+	_, err := ConnectToEndpoints([]string{}, "", nil)
+	assert.Error(t, err)
+
+	_, err = ConnectToEndpoints([]string{"127.0.0.1:1"}, "random", nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported load balancing policy")
+
+	_, err = ConnectToEndpoints([]string{"127.0.0.1:1", "dns:///localhost:1"}, "", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package grpcserver
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// RetryBackoffScalar is the initial wait between retries. It doubles
+	// on every attempt.
+	RetryBackoffScalar = 100 * time.Millisecond
+)
+
+var (
+	// idempotentMethodPrefixes are the prefixes of the SDK method names which
+	// do not modify the state of the cluster and can be safely retried.
+	idempotentMethodPrefixes = []string{
+		"Inspect",
+		"Enumerate",
+		"Stats",
+		"CapacityUsage",
+		"Get",
+		"List",
+		"Status",
+		"Version",
+	}
+)
+
+// ClientOptions provides the settings for the client interceptor chain
+type ClientOptions struct {
+	// Timeout is the deadline added to each call which does not have one.
+	// A value of zero disables the deadline.
+	Timeout time.Duration
+
+	// Retries is the maximum number of retries for idempotent calls
+	Retries uint
+}
+
+// IsIdempotentMethod returns true if the full gRPC method name,
+// like /openstorage.api.OpenStorageVolume/Inspect, can be safely retried.
+func IsIdempotentMethod(method string) bool {
+	name := path.Base(method)
+	for _, prefix := range idempotentMethodPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnaryClientInterceptor returns the interceptor chain used by all unary calls.
+// Calls are given a deadline and retried on Unavailable when idempotent. Each
+// attempt is logged with its own latency.
+func UnaryClientInterceptor(opts ClientOptions) grpc.UnaryClientInterceptor {
+	return grpc_middleware.ChainUnaryClient(
+		TimeoutUnaryClientInterceptor(opts.Timeout),
+		RetryUnaryClientInterceptor(opts.Retries),
+		LatencyUnaryClientInterceptor(),
+	)
+}
+
+// LatencyUnaryClientInterceptor logs the latency of each call at debug level
+func LatencyUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logrus.Debugf("gRPC %s took %v: %v", method, time.Since(start), status.Code(err))
+		return err
+	}
+}
+
+// TimeoutUnaryClientInterceptor adds a deadline to calls which do not have one
+func TimeoutUnaryClientInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// RetryUnaryClientInterceptor retries idempotent calls with an exponential
+// backoff when the server is unavailable.
+func RetryUnaryClientInterceptor(retries uint) grpc.UnaryClientInterceptor {
+	retry := grpc_retry.UnaryClientInterceptor(
+		// The maximum includes the first attempt
+		grpc_retry.WithMax(retries+1),
+		grpc_retry.WithBackoff(grpc_retry.BackoffExponential(RetryBackoffScalar)),
+		grpc_retry.WithCodes(codes.Unavailable),
+	)
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if retries == 0 || !IsIdempotentMethod(method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		return retry(ctx, method, req, reply, cc, invoker, opts...)
+	}
+}
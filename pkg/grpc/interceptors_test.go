@@ -0,0 +1,140 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package grpcserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// countingInvoker returns an invoker which fails with the provided code
+// the first failures times it is called.
+func countingInvoker(calls *int, failures int, code codes.Code) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*calls++
+		if *calls <= failures {
+			return status.Error(code, "failed")
+		}
+		return nil
+	}
+}
+
+func TestIsIdempotentMethod(t *testing.T) {
+	assert.True(t, IsIdempotentMethod("/openstorage.api.OpenStorageVolume/Inspect"))
+	assert.True(t, IsIdempotentMethod("/openstorage.api.OpenStorageVolume/InspectWithFilters"))
+	assert.True(t, IsIdempotentMethod("/openstorage.api.OpenStorageNode/EnumerateWithFilters"))
+	assert.True(t, IsIdempotentMethod("/openstorage.api.OpenStorageVolume/Stats"))
+	assert.False(t, IsIdempotentMethod("/openstorage.api.OpenStorageVolume/Create"))
+	assert.False(t, IsIdempotentMethod("/openstorage.api.OpenStorageVolume/Delete"))
+	assert.False(t, IsIdempotentMethod("/openstorage.api.OpenStorageVolume/Update"))
+}
+
+func TestTimeoutUnaryClientInterceptor(t *testing.T) {
+	interceptor := TimeoutUnaryClientInterceptor(time.Minute)
+
+	// Adds a deadline
+	err := interceptor(context.Background(), "/a/Inspect", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			deadline, ok := ctx.Deadline()
+			assert.True(t, ok)
+			assert.True(t, time.Until(deadline) <= time.Minute)
+			return nil
+		})
+	assert.NoError(t, err)
+
+	// Keeps an existing deadline
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	expected, _ := ctx.Deadline()
+	err = interceptor(ctx, "/a/Inspect", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			deadline, ok := ctx.Deadline()
+			assert.True(t, ok)
+			assert.Equal(t, expected, deadline)
+			return nil
+		})
+	assert.NoError(t, err)
+
+	// Disabled
+	err = TimeoutUnaryClientInterceptor(0)(context.Background(), "/a/Inspect", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			_, ok := ctx.Deadline()
+			assert.False(t, ok)
+			return nil
+		})
+	assert.NoError(t, err)
+}
+
+func TestRetryUnaryClientInterceptor(t *testing.T) {
+	interceptor := RetryUnaryClientInterceptor(3)
+
+	// Idempotent calls are retried on Unavailable
+	calls := 0
+	err := interceptor(context.Background(), "/a/Inspect", nil, nil, nil,
+		countingInvoker(&calls, 2, codes.Unavailable))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	// Other calls are not retried
+	calls = 0
+	err = interceptor(context.Background(), "/a/Create", nil, nil, nil,
+		countingInvoker(&calls, 2, codes.Unavailable))
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	// Other errors are not retried
+	calls = 0
+	err = interceptor(context.Background(), "/a/Inspect", nil, nil, nil,
+		countingInvoker(&calls, 2, codes.NotFound))
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, 1, calls)
+
+	// Gives up after the maximum number of retries
+	calls = 0
+	err = RetryUnaryClientInterceptor(1)(context.Background(), "/a/Inspect", nil, nil, nil,
+		countingInvoker(&calls, 5, codes.Unavailable))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 2, calls)
+}
+
+func TestUnaryClientInterceptorLogsEachAttempt(t *testing.T) {
+	hook := logrustest.NewGlobal()
+	defer hook.Reset()
+	level := logrus.GetLevel()
+	logrus.SetLevel(logrus.DebugLevel)
+	defer logrus.SetLevel(level)
+
+	interceptor := UnaryClientInterceptor(ClientOptions{Timeout: time.Minute, Retries: 3})
+	calls := 0
+	err := interceptor(context.Background(), "/a/Inspect", nil, nil, nil,
+		countingInvoker(&calls, 2, codes.Unavailable))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	entries := hook.AllEntries()
+	assert.Len(t, entries, 3)
+	assert.Contains(t, entries[0].Message, "Unavailable")
+	assert.Contains(t, entries[1].Message, "Unavailable")
+	assert.Contains(t, entries[2].Message, "OK")
+}
@@ -0,0 +1,217 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package grpcserver
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+	"google.golang.org/grpc"
+)
+
+// ProxyOptions provides the proxy settings used to connect to the server
+type ProxyOptions struct {
+	// ProxyURL is the URL of an HTTP, HTTPS or SOCKS5 proxy. If empty,
+	// the proxy is taken from the standard environment variables.
+	ProxyURL string
+
+	// DisableProxyEnv ignores HTTPS_PROXY, NO_PROXY and friends
+	DisableProxyEnv bool
+}
+
+// ContextDialer dials an address in the context provided
+type ContextDialer func(ctx context.Context, addr string) (net.Conn, error)
+
+// ValidateProxyURL returns an error if the proxy url is not supported
+func ValidateProxyURL(proxyURL string) error {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return fmt.Errorf("Invalid proxy url %s: %v", proxyURL, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("Unsupported proxy scheme %q. Must be http, https, socks5, or socks5h", u.Scheme)
+	}
+	if len(u.Host) == 0 {
+		return fmt.Errorf("Proxy url %s is missing a host", proxyURL)
+	}
+	return nil
+}
+
+// WithProxy returns a dial option which connects to the server through the
+// proxy, if any, configured in the options.
+func WithProxy(opts ProxyOptions) (grpc.DialOption, error) {
+	dialer, err := NewProxyDialer(opts)
+	if err != nil {
+		return nil, err
+	}
+	return grpc.WithContextDialer(dialer), nil
+}
+
+// NewProxyDialer returns a dialer which connects to the address through a
+// proxy. Loopback addresses, like the ones used by the Kubernetes tunnel,
+// are always dialed directly.
+func NewProxyDialer(opts ProxyOptions) (ContextDialer, error) {
+	var fixedProxy *url.URL
+	if len(opts.ProxyURL) != 0 {
+		if err := ValidateProxyURL(opts.ProxyURL); err != nil {
+			return nil, err
+		}
+		fixedProxy, _ = url.Parse(opts.ProxyURL)
+	}
+
+	var envProxy func(*url.URL) (*url.URL, error)
+	if !opts.DisableProxyEnv {
+		envProxy = httpproxy.FromEnvironment().ProxyFunc()
+	}
+
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		var proxyURL *url.URL
+		if !isLoopback(addr) {
+			if fixedProxy != nil {
+				proxyURL = fixedProxy
+			} else if envProxy != nil {
+				var err error
+				proxyURL, err = envProxy(&url.URL{Scheme: "https", Host: addr})
+				if err != nil {
+					return nil, fmt.Errorf("Failed to determine proxy for %s: %v", addr, err)
+				}
+			}
+		}
+
+		if proxyURL == nil {
+			return (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+		}
+
+		logrus.Debugf("Connecting to %s through proxy %s", addr, proxyURL.Redacted())
+		switch proxyURL.Scheme {
+		case "socks5", "socks5h":
+			return dialSocks5(ctx, proxyURL, addr)
+		default:
+			return dialHttpConnect(ctx, proxyURL, addr)
+		}
+	}, nil
+}
+
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func dialSocks5(ctx context.Context, proxyURL *url.URL, addr string) (net.Conn, error) {
+	dialer, err := proxy.FromURL(proxyURL, &net.Dialer{})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to setup SOCKS5 proxy %s: %v", proxyURL.Redacted(), err)
+	}
+	if d, ok := dialer.(proxy.ContextDialer); ok {
+		return d.DialContext(ctx, "tcp", addr)
+	}
+	return dialer.Dial("tcp", addr)
+}
+
+func dialHttpConnect(ctx context.Context, proxyURL *url.URL, addr string) (net.Conn, error) {
+	proxyAddr := proxyURL.Host
+	if len(proxyURL.Port()) == 0 {
+		if proxyURL.Scheme == "https" {
+			proxyAddr = net.JoinHostPort(proxyAddr, "443")
+		} else {
+			proxyAddr = net.JoinHostPort(proxyAddr, "80")
+		}
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to proxy %s: %v", proxyURL.Redacted(), err)
+	}
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("Failed TLS handshake with proxy %s: %v", proxyURL.Redacted(), err)
+		}
+		conn = tlsConn
+	}
+
+	// Stop waiting on the proxy if the context is done
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Host: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+	if u := proxyURL.User; u != nil {
+		password, _ := u.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(u.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Failed to send CONNECT to proxy %s: %v", proxyURL.Redacted(), err)
+	}
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("Failed to read response from proxy %s: %v", proxyURL.Redacted(), err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("Proxy %s failed to connect to %s: %s",
+			proxyURL.Redacted(), addr, resp.Status)
+	}
+
+	// Keep any data already read from the server
+	if r.Buffered() != 0 {
+		return &bufferedConn{Conn: conn, r: r}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn returns the data buffered while reading the proxy
+// response before reading from the connection
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
@@ -16,6 +16,7 @@ limitations under the License.
 package kubernetes
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/portworx/pxc/pkg/config"
@@ -28,14 +29,15 @@ import (
 )
 
 // KubeConnectDefault returns a Kubernetes client to the default
-// or named context.
-func KubeConnectDefault() (clientcmd.ClientConfig, *kubernetes.Clientset, error) {
+// or named context. The context is the one in ctx, if any.
+func KubeConnectDefault(ctx context.Context) (clientcmd.ClientConfig, *kubernetes.Clientset, error) {
 
 	if util.InKubectlPluginMode() {
 		logrus.Info("Setting up Kubernetes access in kubectl plugin mode")
-		clientConfig := config.KM().ToRawKubeConfigLoader()
+		km := config.CMFromContext(ctx).KM()
+		clientConfig := km.ToRawKubeConfigLoader()
 
-		r, err := config.KM().ToRESTConfig()
+		r, err := km.ToRESTConfig()
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable to configure kubernetes client: %v", err)
 		}
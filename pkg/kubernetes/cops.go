@@ -54,6 +54,9 @@ type COps interface {
 	// GetPvcsByLabels returns pvcs from spacified namespace with given labels
 	// labels should be of the form "abc=def,xyz=mno"
 	GetPvcsByLabels(namespace string, labels string) ([]v1.PersistentVolumeClaim, error)
+	// GetPvsByLabels returns the persistent volumes with the given labels
+	// labels should be of the form "abc=def,xyz=mno"
+	GetPvsByLabels(labels string) ([]v1.PersistentVolume, error)
 	// GetLogs writes out logs to out based on the logOptions specified
 	GetLogs(logOptions *COpsLogOptions, out io.Writer) error
 }
@@ -45,20 +45,23 @@ var (
 type kubeConnection struct {
 	clientConfig clientcmd.ClientConfig
 	clientSet    *kclikube.Clientset
+	km           *config.KubernetesConfigManager
 }
 
-func NewCOps() (COps, error) {
+func NewCOps(ctx context.Context) (COps, error) {
+	km := config.CMFromContext(ctx).KM()
 	if util.InKubectlPluginMode() {
-		cc, cs, err := KubeConnectDefault()
+		cc, cs, err := KubeConnectDefault(ctx)
 		if err != nil {
 			return nil, err
 		}
 		return &kubeConnection{
 			clientConfig: cc,
 			clientSet:    cs,
+			km:           km,
 		}, nil
 	}
-	return &kubeConnection{}, nil
+	return &kubeConnection{km: km}, nil
 }
 
 func (p *kubeConnection) Close() {
@@ -66,7 +69,7 @@ func (p *kubeConnection) Close() {
 }
 
 func (p *kubeConnection) GetNamespace() (string, error) {
-	ns, _, err := config.KM().Namespace()
+	ns, _, err := p.km.Namespace()
 	if err != nil {
 		return "", err
 	}
@@ -114,6 +117,24 @@ func (p *kubeConnection) GetPvcsByLabels(
 	return pvcList.Items, nil
 }
 
+func (p *kubeConnection) GetPvsByLabels(labels string) ([]v1.PersistentVolume, error) {
+	if p.clientSet == nil {
+		return make([]v1.PersistentVolume, 0), nil
+	}
+	pvClient := p.clientSet.CoreV1().PersistentVolumes()
+
+	lo := metav1.ListOptions{}
+	if len(labels) > 0 {
+		lo.LabelSelector = labels
+	}
+	pvList, err := pvClient.List(context.TODO(), lo)
+	if err != nil {
+		return nil, err
+	}
+
+	return pvList.Items, nil
+}
+
 type logPayload struct {
 	rw           rest.ResponseWrapper
 	podName      string
@@ -16,6 +16,7 @@ limitations under the License.
 package kubernetes
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -42,36 +43,63 @@ type KubectlPortForwarder struct {
 	signhandler *util.SigIntManager
 	lock        sync.Mutex
 	running     bool
+	// cm is the configuration of the context to forward to
+	cm *config.ConfigManager
 }
 
 var (
-	kubePortForwarder *KubectlPortForwarder
+	tunnelsLock sync.Mutex
+	// tunnels has the tunnel of each configuration in use
+	tunnels = make(map[*config.ConfigManager]*KubectlPortForwarder)
 )
 
-// StartTunnel starts the global tunnel to the Portworx endpoint through the Kubernetes service
-func StartTunnel() error {
-	if kubePortForwarder == nil {
-		logrus.Info("Kubectl plugin mode detected")
-		logrus.Infof("Port forwarder using kubeconfig %s", *config.KM().ConfigFlags().KubeConfig)
-		kubePortForwarder = newKubectlPortForwarder(*config.KM().ConfigFlags().KubeConfig)
-		if err := kubePortForwarder.Start(); err != nil {
-			StopTunnel()
-			return fmt.Errorf("Failed to setup port forward: %v", err)
-		}
-		config.CM().SetTunnelEndpoint(kubePortForwarder.Endpoint())
+// StartTunnel starts the tunnel to the Portworx endpoint through the Kubernetes
+// service of the context in ctx, if not up already
+func StartTunnel(ctx context.Context) error {
+	cm := config.CMFromContext(ctx)
+	if getTunnel(cm) != nil {
+		return nil
+	}
+
+	// Tunnels of different contexts may be started at the same time
+	logrus.Info("Kubectl plugin mode detected")
+	logrus.Infof("Port forwarder using kubeconfig %s", *cm.KM().ConfigFlags().KubeConfig)
+	p := newKubectlPortForwarder(*cm.KM().ConfigFlags().KubeConfig)
+	p.cm = cm
+	if err := p.Start(); err != nil {
+		p.Stop()
+		return fmt.Errorf("Failed to setup port forward: %v", err)
 	}
 
+	tunnelsLock.Lock()
+	defer tunnelsLock.Unlock()
+	if _, ok := tunnels[cm]; ok {
+		p.Stop()
+		return nil
+	}
+	tunnels[cm] = p
+	cm.SetTunnelEndpoint(p.Endpoint())
 	return nil
 }
 
-// StopTunnel stops the global tunnel to the Portworx endpoint through the Kubernetes service
-func StopTunnel() {
-	if kubePortForwarder != nil {
-		kubePortForwarder.Stop()
-		kubePortForwarder = nil
+// StopTunnel stops the tunnel to the Portworx endpoint through the Kubernetes
+// service of the context in ctx
+func StopTunnel(ctx context.Context) {
+	cm := config.CMFromContext(ctx)
+	tunnelsLock.Lock()
+	defer tunnelsLock.Unlock()
+	if p, ok := tunnels[cm]; ok {
+		p.Stop()
+		delete(tunnels, cm)
 	}
 }
 
+func getTunnel(cm *config.ConfigManager) *KubectlPortForwarder {
+	tunnelsLock.Lock()
+	defer tunnelsLock.Unlock()
+	return tunnels[cm]
+}
+
 // NewKubectlPortForwarder forwards a local port to the Portworx gRPC SDK endpoint
 // through the Kubernetes API server using kubectl
 // If kubeconfig is not provided, then kubectl will use the default kubeconfig
@@ -94,8 +122,12 @@ func (p *KubectlPortForwarder) Start() error {
 		return fmt.Errorf("Tunnel already running")
 	}
 
-	args := config.KM().KubectlFlagsToCliArgs()
-	currentCluster := config.CM().GetCurrentCluster()
+	cm := p.cm
+	if cm == nil {
+		cm = config.CM()
+	}
+	args := cm.KM().KubectlFlagsToCliArgs()
+	currentCluster := cm.GetCurrentCluster()
 	logrus.Debugf("port-forward: CurrentCluster: %v", *currentCluster)
 	args = args + fmt.Sprintf("-n %s port-forward svc/%s :%s",
 		currentCluster.TunnelServiceNamespace,
@@ -17,6 +17,9 @@ limitations under the License.
 package portworx
 
 import (
+	"context"
+
+	"github.com/portworx/pxc/pkg/config"
 	"github.com/portworx/pxc/pkg/util"
 
 	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
@@ -70,15 +73,21 @@ type CliAuthInputs struct {
 	Wide bool
 }
 
-type authOps struct{}
+type authOps struct {
+	ctx context.Context
+}
 
-// NewAuthOps creates a new auth ops
-func NewAuthOps() AuthOps {
-	return &authOps{}
+// NewAuthOps creates a new auth ops using the transport configured
+// for the current cluster of the configuration in ctx
+func NewAuthOps(ctx context.Context) AuthOps {
+	if config.CMFromContext(ctx).GetCurrentCluster().UsesRestTransport() {
+		return &restAuthOps{ctx: ctx}
+	}
+	return &authOps{ctx: ctx}
 }
 
 func (p *authOps) GetRole(name string) (*api.SdkRole, error) {
-	ctx, conn, err := PxConnectDefault()
+	ctx, conn, err := PxConnectDefault(p.ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +105,7 @@ func (p *authOps) GetRole(name string) (*api.SdkRole, error) {
 }
 
 func (p *authOps) UpdateRole(r *api.SdkRole) error {
-	ctx, conn, err := PxConnectDefault()
+	ctx, conn, err := PxConnectDefault(p.ctx)
 	if err != nil {
 		return err
 	}
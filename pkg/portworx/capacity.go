@@ -0,0 +1,228 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+)
+
+// CapacityThresholds are the percentages of usage forecasted
+var CapacityThresholds = []int{80, 90, 100}
+
+// ClusterCapacityName is the name of the forecast of the whole cluster
+const ClusterCapacityName = "cluster"
+
+// PoolCapacity is the used and total bytes of a storage pool
+type PoolCapacity struct {
+	Node  string `json:"node" yaml:"node"`
+	Pool  string `json:"pool" yaml:"pool"`
+	Used  uint64 `json:"used" yaml:"used"`
+	Total uint64 `json:"total" yaml:"total"`
+}
+
+// CapacitySample is the capacity of all the pools at a point in time
+type CapacitySample struct {
+	Time  time.Time       `json:"time" yaml:"time"`
+	Pools []*PoolCapacity `json:"pools" yaml:"pools"`
+}
+
+// CapacityForecast is the estimated number of days until a pool or the
+// cluster reaches each of the CapacityThresholds. A nil value means that
+// the usage is not growing.
+type CapacityForecast struct {
+	Name         string     `json:"name" yaml:"name"`
+	Used         uint64     `json:"used" yaml:"used"`
+	Total        uint64     `json:"total" yaml:"total"`
+	GrowthPerDay float64    `json:"growthPerDay" yaml:"growthPerDay"`
+	DaysUntil    []*float64 `json:"daysUntil" yaml:"daysUntil"`
+	Expand       bool       `json:"expand" yaml:"expand"`
+}
+
+// NewCapacitySample returns the capacity of the pools of the nodes
+func NewCapacitySample(t time.Time, nodes []*api.StorageNode) *CapacitySample {
+	s := &CapacitySample{
+		Time:  t,
+		Pools: make([]*PoolCapacity, 0),
+	}
+	for _, n := range nodes {
+		for _, pool := range n.GetPools() {
+			s.Pools = append(s.Pools, &PoolCapacity{
+				Node:  n.GetHostname(),
+				Pool:  fmt.Sprintf("%d", pool.GetID()),
+				Used:  pool.GetUsed(),
+				Total: pool.GetTotalSize(),
+			})
+		}
+	}
+	return s
+}
+
+// AppendCapacitySample adds the sample to the history file. The file has
+// a sample in json per line.
+func AppendCapacitySample(filename string, s *CapacitySample) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// ReadCapacityHistory returns the samples in the history file sorted by time
+func ReadCapacityHistory(filename string) ([]*CapacitySample, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	history := make([]*CapacitySample, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		s := &CapacitySample{}
+		if err := json.Unmarshal(scanner.Bytes(), s); err != nil {
+			return nil, fmt.Errorf("Failed to read sample at line %d of %s: %v", line, filename, err)
+		}
+		history = append(history, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Time.Before(history[j].Time)
+	})
+	return history, nil
+}
+
+type capacityPoint struct {
+	time  time.Time
+	used  uint64
+	total uint64
+}
+
+// ForecastCapacity fits a linear trend to the usage of each pool and of
+// the cluster. Pools which reach expandThreshold percent within
+// expandWithinDays are flagged to be expanded.
+func ForecastCapacity(
+	history []*CapacitySample,
+	expandThreshold int,
+	expandWithinDays float64,
+) []*CapacityForecast {
+	names := make([]string, 0)
+	series := make(map[string][]capacityPoint)
+	cluster := make([]capacityPoint, 0, len(history))
+	for _, s := range history {
+		total := capacityPoint{time: s.Time}
+		for _, p := range s.Pools {
+			name := p.Node + "/" + p.Pool
+			if _, ok := series[name]; !ok {
+				names = append(names, name)
+			}
+			series[name] = append(series[name], capacityPoint{time: s.Time, used: p.Used, total: p.Total})
+			total.used += p.Used
+			total.total += p.Total
+		}
+		cluster = append(cluster, total)
+	}
+	sort.Strings(names)
+
+	forecasts := make([]*CapacityForecast, 0, len(names)+1)
+	for _, name := range names {
+		f := newCapacityForecast(name, series[name])
+		days := f.daysUntil(expandThreshold)
+		f.Expand = days != nil && *days <= expandWithinDays
+		forecasts = append(forecasts, f)
+	}
+	if len(cluster) != 0 {
+		forecasts = append(forecasts, newCapacityForecast(ClusterCapacityName, cluster))
+	}
+	return forecasts
+}
+
+func newCapacityForecast(name string, points []capacityPoint) *CapacityForecast {
+	last := points[len(points)-1]
+	f := &CapacityForecast{
+		Name:         name,
+		Used:         last.used,
+		Total:        last.total,
+		GrowthPerDay: growthPerDay(points),
+	}
+	for _, t := range CapacityThresholds {
+		f.DaysUntil = append(f.DaysUntil, f.daysUntil(t))
+	}
+	return f
+}
+
+// daysUntil returns the days until the usage reaches the percentage of the
+// total, or nil if the usage is not growing
+func (f *CapacityForecast) daysUntil(percent int) *float64 {
+	target := float64(f.Total) * float64(percent) / 100
+	days := 0.0
+	if float64(f.Used) < target {
+		if f.GrowthPerDay <= 0 {
+			return nil
+		}
+		days = (target - float64(f.Used)) / f.GrowthPerDay
+	}
+	return &days
+}
+
+// growthPerDay returns the slope in bytes per day of the least squares fit
+// of the used bytes
+func growthPerDay(points []capacityPoint) float64 {
+	if len(points) < 2 {
+		return 0
+	}
+
+	start := points[0].time
+	var sumX, sumY, sumXY, sumXX float64
+	for _, p := range points {
+		x := p.time.Sub(start).Hours() / 24
+		y := float64(p.used)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	n := float64(len(points))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
@@ -0,0 +1,104 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/stretchr/testify/assert"
+)
+
+func capacitySample(day int, used ...uint64) *CapacitySample {
+	s := &CapacitySample{Time: time.Date(2020, 6, 1+day, 0, 0, 0, 0, time.UTC)}
+	for i, u := range used {
+		s.Pools = append(s.Pools, &PoolCapacity{Node: "node", Pool: string(rune('0' + i)), Used: u, Total: 1000})
+	}
+	return s
+}
+
+func TestNewCapacitySample(t *testing.T) {
+	now := time.Now()
+	s := NewCapacitySample(now, []*api.StorageNode{
+		{Hostname: "n1", Pools: []*api.StoragePool{{ID: 0, Used: 10, TotalSize: 100}, {ID: 1, Used: 20, TotalSize: 200}}},
+		{Hostname: "n2"},
+	})
+	assert.Equal(t, now, s.Time)
+	assert.Equal(t, []*PoolCapacity{
+		{Node: "n1", Pool: "0", Used: 10, Total: 100},
+		{Node: "n1", Pool: "1", Used: 20, Total: 200},
+	}, s.Pools)
+}
+
+func TestCapacityHistory(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "dir", "history.jsonl")
+	_, err := ReadCapacityHistory(filename)
+	assert.Error(t, err)
+
+	assert.NoError(t, AppendCapacitySample(filename, capacitySample(2, 30)))
+	assert.NoError(t, AppendCapacitySample(filename, capacitySample(1, 20)))
+	history, err := ReadCapacityHistory(filename)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, uint64(20), history[0].Pools[0].Used)
+	assert.Equal(t, uint64(30), history[1].Pools[0].Used)
+}
+
+func TestForecastCapacity(t *testing.T) {
+	// Pool 0 grows 100 bytes per day, pool 1 does not grow and pool 2 is full
+	history := []*CapacitySample{
+		capacitySample(0, 100, 500, 1000),
+		capacitySample(1, 200, 500, 1000),
+		capacitySample(2, 300, 500, 1000),
+	}
+	forecasts := ForecastCapacity(history, 80, 10)
+	assert.Len(t, forecasts, 4)
+
+	f := forecasts[0]
+	assert.Equal(t, "node/0", f.Name)
+	assert.Equal(t, uint64(300), f.Used)
+	assert.InDelta(t, 100, f.GrowthPerDay, 0.001)
+	assert.InDelta(t, 5, *f.DaysUntil[0], 0.001)
+	assert.InDelta(t, 6, *f.DaysUntil[1], 0.001)
+	assert.InDelta(t, 7, *f.DaysUntil[2], 0.001)
+	assert.True(t, f.Expand)
+
+	f = forecasts[1]
+	assert.Equal(t, 0.0, f.GrowthPerDay)
+	assert.Equal(t, []*float64{nil, nil, nil}, f.DaysUntil)
+	assert.False(t, f.Expand)
+
+	f = forecasts[2]
+	assert.Equal(t, 0.0, *f.DaysUntil[2])
+	assert.True(t, f.Expand)
+
+	f = forecasts[3]
+	assert.Equal(t, ClusterCapacityName, f.Name)
+	assert.Equal(t, uint64(1800), f.Used)
+	assert.Equal(t, uint64(3000), f.Total)
+	assert.InDelta(t, 100, f.GrowthPerDay, 0.001)
+	assert.InDelta(t, 6, *f.DaysUntil[0], 0.001)
+	assert.False(t, f.Expand)
+
+	// A single sample has no growth
+	forecasts = ForecastCapacity(history[:1], 80, 10)
+	assert.Equal(t, 0.0, forecasts[0].GrowthPerDay)
+	assert.False(t, forecasts[0].Expand)
+
+	assert.Len(t, ForecastCapacity(nil, 80, 10), 0)
+}
@@ -17,8 +17,12 @@ package portworx
 
 import (
 	"context"
+	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
 
 	"github.com/portworx/pxc/pkg/config"
 	pxgrpc "github.com/portworx/pxc/pkg/grpc"
@@ -26,33 +30,48 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 
 	"github.com/sirupsen/logrus"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ErrNotSupportedByTransport is returned when a command needs a gRPC connection
+// but the cluster is configured to use the REST gateway
+var ErrNotSupportedByTransport = fmt.Errorf("This command is not supported when using the %s transport. "+
+	"Use 'pxc config cluster set --transport=%s' to use gRPC", config.TransportRest, config.TransportGrpc)
+
 // PxConnectDefault returns a Portworx client to the default or
-// named context
-func PxConnectDefault() (context.Context, *grpc.ClientConn, error) {
+// named context. The configuration is the one in ctx, if any, and the
+// context returned is derived from ctx.
+func PxConnectDefault(ctx context.Context) (context.Context, *grpc.ClientConn, error) {
 
 	var (
 		caerr       error
 		dialOptions []grpc.DialOption
 	)
 
-	if len(config.CM().GetEndpoint()) == 0 {
-		// Start global tunnel if not up already
-		err := kubernetes.StartTunnel()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cm := config.CMFromContext(ctx)
+	currentCluster := cm.GetCurrentCluster()
+	if currentCluster.UsesRestTransport() {
+		return nil, nil, ErrNotSupportedByTransport
+	}
+
+	if len(cm.GetEndpoints()) == 0 {
+		// Start the tunnel if not up already
+		err := kubernetes.StartTunnel(ctx)
 		if err != nil {
 			return nil, nil, err
 		}
 	}
 
 	// If secure: true set in config.yaml file, use TLS
-	currentCluster := config.CM().GetCurrentCluster()
-	if currentCluster.Secure || len(currentCluster.CACertData) != 0 {
-		dialOptions, caerr = PxAppendCaCertcontext(config.CM().GetCurrentCluster())
+	if currentCluster.UsesTLS() {
+		dialOptions, caerr = PxAppendCaCertcontext(currentCluster)
 		if caerr != nil {
 			return nil, nil, caerr
 		}
@@ -60,28 +79,46 @@ func PxConnectDefault() (context.Context, *grpc.ClientConn, error) {
 		dialOptions = append(dialOptions, grpc.WithInsecure())
 	}
 
+	// Setup keepalive and the client interceptors
+	keepaliveOptions, err := pxKeepaliveDialOptions(currentCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	dialOptions = append(dialOptions, keepaliveOptions...)
+
+	// Setup proxy if any
+	proxyOption, err := pxgrpc.WithProxy(pxgrpc.ProxyOptions{
+		ProxyURL:        currentCluster.ProxyURL,
+		DisableProxyEnv: currentCluster.DisableProxyEnv,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	dialOptions = append(dialOptions, proxyOption)
+	dialOptions = append(dialOptions, grpc.WithUnaryInterceptor(
+		pxgrpc.UnaryClientInterceptor(pxgrpc.ClientOptions{
+			Timeout: cm.GetFlags().RequestTimeout,
+			Retries: cm.GetFlags().Retries,
+		})))
+
 	// Get config
-	endpoint := config.CM().GetEndpoint()
-	authInfo := config.CM().GetCurrentAuthInfo()
+	endpoints := cm.GetEndpoints()
+	endpoint := strings.Join(endpoints, ",")
+	authInfo := cm.GetCurrentAuthInfo()
 
 	// Connect to server
 	logrus.Infof("Connecting to Portworx at endpoint %s", endpoint)
-	conn, err := pxgrpc.Connect(endpoint, dialOptions)
+	conn, err := pxgrpc.ConnectToEndpoints(endpoints, currentCluster.LoadBalancing, dialOptions)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Check if the token is in a secret
-	token := authInfo.Token
-	if len(authInfo.KubernetesAuthInfo.SecretName) != 0 &&
-		len(authInfo.KubernetesAuthInfo.SecretNamespace) != 0 {
-		token, err = PxGetTokenFromSecret(authInfo.KubernetesAuthInfo.SecretName, authInfo.KubernetesAuthInfo.SecretNamespace)
-		if err != nil {
-			return nil, nil, err
-		}
+	token, err := pxGetToken(ctx, authInfo)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
 	}
 
-	ctx := context.Background()
 	if len(token) != 0 {
 		ctx = pxgrpc.AddMetadataToContext(ctx, "authorization", "bearer "+token)
 	}
@@ -142,27 +179,128 @@ func PxConnectNamed(cfgFile string, name string) (context.Context, *grpc.ClientC
 }
 */
 
+// pxKeepaliveDialOptions returns the keepalive dial options for the cluster if any
+func pxKeepaliveDialOptions(clusterInfo *config.Cluster) ([]grpc.DialOption, error) {
+	if len(clusterInfo.KeepaliveTime) == 0 && len(clusterInfo.KeepaliveTimeout) == 0 {
+		return nil, nil
+	}
+
+	params := keepalive.ClientParameters{
+		PermitWithoutStream: true,
+	}
+	if len(clusterInfo.KeepaliveTime) != 0 {
+		d, err := time.ParseDuration(clusterInfo.KeepaliveTime)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid keepalive time %s: %v", clusterInfo.KeepaliveTime, err)
+		}
+		params.Time = d
+	}
+	if len(clusterInfo.KeepaliveTimeout) != 0 {
+		d, err := time.ParseDuration(clusterInfo.KeepaliveTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid keepalive timeout %s: %v", clusterInfo.KeepaliveTimeout, err)
+		}
+		params.Timeout = d
+	}
+
+	return []grpc.DialOption{grpc.WithKeepaliveParams(params)}, nil
+}
+
 // PxAppendCaCertcontext appends the provided valid CA from the user to the existing systemPool or
 // load the default CA certs used for authentication with the sdk server.
 func PxAppendCaCertcontext(clusterInfo *config.Cluster) ([]grpc.DialOption, error) {
-	// Read the provided CA cert from the user
-	capool, err := x509.SystemCertPool()
+	tlsConfig, err := PxTLSConfig(clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	dialOptions := []grpc.DialOption{grpc.WithTransportCredentials(
+		credentials.NewTLS(tlsConfig))}
+	return dialOptions, nil
+}
+
+// PxTLSConfig returns the TLS configuration for the cluster. The CA certificate
+// and the client certificate and key are read from the inline data if provided,
+// otherwise from their file paths.
+func PxTLSConfig(clusterInfo *config.Cluster) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         clusterInfo.TLSServerName,
+		InsecureSkipVerify: clusterInfo.InsecureSkipTLSVerify,
+	}
 
-	// TODO: Read CA Cert file
 	// If user provided CA cert, then append it to systemCertPool.
-	if len(clusterInfo.CACertData) != 0 {
-		if !capool.AppendCertsFromPEM([]byte(clusterInfo.CACertData)) {
-			return nil, err
+	caData, err := readTLSData(clusterInfo.CACertData, clusterInfo.CACert)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read CA certificate: %v", err)
+	}
+	if len(caData) != 0 {
+		capool, err := x509.SystemCertPool()
+		if err != nil || capool == nil {
+			logrus.Infof("Unable to load system CA pool, using only the provided CA: %v", err)
+			capool = x509.NewCertPool()
 		}
+		if !capool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("Failed to parse CA certificate%s: no valid PEM certificates found",
+				fileSuffix(clusterInfo.CACertData, clusterInfo.CACert))
+		}
+		tlsConfig.RootCAs = capool
 	}
 
-	dialOptions := []grpc.DialOption{grpc.WithTransportCredentials(
-		credentials.NewClientTLSFromCert(capool, ""))}
-	return dialOptions, nil
+	// Setup client certificates for mutual TLS
+	certData, err := readTLSData(clusterInfo.ClientCertData, clusterInfo.ClientCert)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read client certificate: %v", err)
+	}
+	keyData, err := readTLSData(clusterInfo.ClientKeyData, clusterInfo.ClientKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read client key: %v", err)
+	}
+	if len(certData) != 0 || len(keyData) != 0 {
+		if len(certData) == 0 || len(keyData) == 0 {
+			return nil, fmt.Errorf("Both a client certificate and a client key must be provided")
+		}
+		cert, err := tls.X509KeyPair(certData, keyData)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load client certificate and key: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// readTLSData returns the inline data if set, otherwise the contents of the file
+func readTLSData(data []byte, file string) ([]byte, error) {
+	if len(data) != 0 {
+		return data, nil
+	}
+	if len(file) == 0 {
+		return nil, nil
+	}
+	return ioutil.ReadFile(file)
+}
+
+func fileSuffix(data []byte, file string) string {
+	if len(data) == 0 && len(file) != 0 {
+		return " " + file
+	}
+	return ""
+}
+
+// pxGetToken returns the token from the configuration or from the
+// Kubernetes secret if one is configured
+func pxGetToken(ctx context.Context, authInfo *config.AuthInfo) (string, error) {
+	token := authInfo.Token
+	if authInfo.KubernetesAuthInfo != nil &&
+		len(authInfo.KubernetesAuthInfo.SecretName) != 0 &&
+		len(authInfo.KubernetesAuthInfo.SecretNamespace) != 0 {
+		return PxGetTokenFromSecret(ctx, authInfo.KubernetesAuthInfo.SecretName, authInfo.KubernetesAuthInfo.SecretNamespace)
+	}
+	return token, nil
 }
 
-func PxGetTokenFromSecret(secretName, secretNamespace string) (string, error) {
-	_, clientSet, err := kubernetes.KubeConnectDefault()
+func PxGetTokenFromSecret(ctx context.Context, secretName, secretNamespace string) (string, error) {
+	_, clientSet, err := kubernetes.KubeConnectDefault(ctx)
 	if err != nil {
 		logrus.Errorf("Failed to get kube client: %v", err)
 		return "", err
@@ -0,0 +1,138 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/portworx/pxc/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func genTestCertAndKey(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "pxc-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func TestPxTLSConfigFromFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pxc-tls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certPem, keyPem := genTestCertAndKey(t)
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	assert.NoError(t, ioutil.WriteFile(caFile, certPem, 0600))
+	assert.NoError(t, ioutil.WriteFile(certFile, certPem, 0600))
+	assert.NoError(t, ioutil.WriteFile(keyFile, keyPem, 0600))
+
+	tlsConfig, err := PxTLSConfig(&config.Cluster{
+		CACert:                caFile,
+		ClientCert:            certFile,
+		ClientKey:             keyFile,
+		TLSServerName:         "px.example.com",
+		InsecureSkipTLSVerify: true,
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, tlsConfig.RootCAs)
+	assert.Len(t, tlsConfig.Certificates, 1)
+	assert.Equal(t, "px.example.com", tlsConfig.ServerName)
+	assert.True(t, tlsConfig.InsecureSkipVerify)
+}
+
+func TestPxTLSConfigFromData(t *testing.T) {
+	certPem, keyPem := genTestCertAndKey(t)
+
+	// Inline data must take precedence over the file paths
+	tlsConfig, err := PxTLSConfig(&config.Cluster{
+		CACert:         "/does/not/exist",
+		CACertData:     certPem,
+		ClientCertData: certPem,
+		ClientKeyData:  keyPem,
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, tlsConfig.RootCAs)
+	assert.Len(t, tlsConfig.Certificates, 1)
+	assert.False(t, tlsConfig.InsecureSkipVerify)
+
+	// No CA provided should use the system pool
+	tlsConfig, err = PxTLSConfig(&config.Cluster{Secure: true})
+	assert.NoError(t, err)
+	assert.Nil(t, tlsConfig.RootCAs)
+	assert.Empty(t, tlsConfig.Certificates)
+}
+
+func TestPxTLSConfigErrors(t *testing.T) {
+	certPem, keyPem := genTestCertAndKey(t)
+
+	_, err := PxTLSConfig(&config.Cluster{CACertData: []byte("not a pem")})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no valid PEM certificates")
+
+	_, err = PxTLSConfig(&config.Cluster{CACert: "/does/not/exist"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to read CA certificate")
+
+	_, err = PxTLSConfig(&config.Cluster{ClientCertData: certPem})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Both a client certificate and a client key")
+
+	_, err = PxTLSConfig(&config.Cluster{ClientCertData: certPem, ClientKeyData: certPem})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to load client certificate")
+
+	_, err = PxTLSConfig(&config.Cluster{ClientCertData: certPem, ClientKeyData: keyPem})
+	assert.NoError(t, err)
+}
+
+func TestClusterUsesTLS(t *testing.T) {
+	assert.False(t, (&config.Cluster{Endpoint: "localhost:9020"}).UsesTLS())
+	assert.True(t, (&config.Cluster{Secure: true}).UsesTLS())
+	assert.True(t, (&config.Cluster{CACert: "ca.pem"}).UsesTLS())
+	assert.True(t, (&config.Cluster{ClientCertData: []byte("cert")}).UsesTLS())
+	assert.True(t, (&config.Cluster{TLSServerName: "px"}).UsesTLS())
+	assert.True(t, (&config.Cluster{InsecureSkipTLSVerify: true}).UsesTLS())
+}
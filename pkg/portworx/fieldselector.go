@@ -0,0 +1,439 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/kubernetes"
+	"github.com/portworx/pxc/pkg/util"
+)
+
+// fieldOperators are the operators of a field selector. Operators which are
+// a prefix of another operator must be after it.
+var fieldOperators = []string{"!=", "==", "<=", ">=", "=", "<", ">"}
+
+// ListOptions are the sorting, filtering and limit of a list of objects
+type ListOptions struct {
+	// SortBy is the field to sort on. A leading - sorts in descending order.
+	SortBy string
+	// FieldSelector is a comma separated list of <field><operator><value>
+	// like status=down,ha<3
+	FieldSelector string
+	// Limit is the maximum number of objects returned. 0 means no limit.
+	Limit int
+}
+
+// IsEmpty returns true if the options do not change a list
+func (o *ListOptions) IsEmpty() bool {
+	return o == nil || (len(o.SortBy) == 0 && len(o.FieldSelector) == 0 && o.Limit == 0)
+}
+
+// FieldRequirement is a condition on the value of a field
+type FieldRequirement struct {
+	Field    string
+	Operator string
+	Value    string
+}
+
+// FieldFunc returns the value of a field of an object. Values are string,
+// int64, bool or time.Time. nil means that the object has no value for the
+// field.
+type FieldFunc func(obj interface{}) interface{}
+
+// FieldSet are the fields used to select and sort objects of a kind
+type FieldSet struct {
+	Kind   string
+	Fields map[string]FieldFunc
+}
+
+// ParseFieldSelector parses a comma separated list of requirements like
+// status=down,ha<3
+func ParseFieldSelector(selector string) ([]*FieldRequirement, error) {
+	reqs := make([]*FieldRequirement, 0)
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if len(term) == 0 {
+			continue
+		}
+		req, err := parseFieldRequirement(term)
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+func parseFieldRequirement(term string) (*FieldRequirement, error) {
+	for i := range term {
+		for _, op := range fieldOperators {
+			if strings.HasPrefix(term[i:], op) {
+				req := &FieldRequirement{
+					Field:    strings.ToLower(strings.TrimSpace(term[:i])),
+					Operator: op,
+					Value:    strings.TrimSpace(term[i+len(op):]),
+				}
+				if len(req.Field) == 0 {
+					return nil, fmt.Errorf("Missing field in field selector %s", term)
+				}
+				return req, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("Invalid field selector %s. Use <field><operator><value> with one of the operators %s",
+		term, strings.Join(fieldOperators, " "))
+}
+
+// FieldNames returns the sorted names of the fields
+func (fs *FieldSet) FieldNames() []string {
+	names := make([]string, 0, len(fs.Fields))
+	for name := range fs.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (fs *FieldSet) field(name string) (FieldFunc, error) {
+	f, ok := fs.Fields[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("Unknown %s field %s. Valid fields are %s",
+			fs.Kind, name, strings.Join(fs.FieldNames(), ", "))
+	}
+	return f, nil
+}
+
+// Apply returns the objects matching the field selector of the options,
+// sorted and limited
+func (fs *FieldSet) Apply(objs []interface{}, opts *ListOptions) ([]interface{}, error) {
+	if opts.IsEmpty() {
+		return objs, nil
+	}
+
+	reqs, err := ParseFieldSelector(opts.FieldSelector)
+	if err != nil {
+		return nil, err
+	}
+	funcs := make([]FieldFunc, len(reqs))
+	for i, req := range reqs {
+		if funcs[i], err = fs.field(req.Field); err != nil {
+			return nil, err
+		}
+	}
+
+	selected := make([]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		match := true
+		for i, req := range reqs {
+			ok, err := matchField(funcs[i](obj), req)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				match = false
+				break
+			}
+		}
+		if match {
+			selected = append(selected, obj)
+		}
+	}
+
+	if len(opts.SortBy) != 0 {
+		name := strings.TrimPrefix(opts.SortBy, "-")
+		descending := name != opts.SortBy
+		f, err := fs.field(name)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(selected, func(i, j int) bool {
+			a, b := f(selected[i]), f(selected[j])
+			// Objects without a value are always last
+			if a == nil || b == nil {
+				return a != nil
+			}
+			if descending {
+				return compareFields(b, a) < 0
+			}
+			return compareFields(a, b) < 0
+		})
+	}
+
+	if opts.Limit > 0 && len(selected) > opts.Limit {
+		selected = selected[:opts.Limit]
+	}
+	return selected, nil
+}
+
+// compareFields returns -1, 0 or 1 if a is less, equal or greater than b,
+// which have the same type
+func compareFields(a, b interface{}) int {
+	switch av := a.(type) {
+	case int64:
+		bv := b.(int64)
+		if av < bv {
+			return -1
+		} else if av > bv {
+			return 1
+		}
+	case bool:
+		bv := b.(bool)
+		if !av && bv {
+			return -1
+		} else if av && !bv {
+			return 1
+		}
+	case time.Time:
+		bv := b.(time.Time)
+		if av.Before(bv) {
+			return -1
+		} else if av.After(bv) {
+			return 1
+		}
+	case string:
+		return strings.Compare(strings.ToLower(av), strings.ToLower(b.(string)))
+	}
+	return 0
+}
+
+// matchField returns true if the value meets the requirement
+func matchField(value interface{}, req *FieldRequirement) (bool, error) {
+	if value == nil {
+		return req.Operator == "!=", nil
+	}
+
+	if s, ok := value.(string); ok {
+		matched := util.MatchGlob(strings.ToLower(req.Value), strings.ToLower(s))
+		switch req.Operator {
+		case "=", "==":
+			return matched, nil
+		case "!=":
+			return !matched, nil
+		}
+		return false, fmt.Errorf("Operator %s cannot be used with field %s. Use = or !=", req.Operator, req.Field)
+	}
+
+	var (
+		want interface{}
+		err  error
+	)
+	switch value.(type) {
+	case int64:
+		want, err = parseIntField(req.Value)
+	case bool:
+		want, err = strconv.ParseBool(req.Value)
+		if err == nil && req.Operator != "=" && req.Operator != "==" && req.Operator != "!=" {
+			return false, fmt.Errorf("Operator %s cannot be used with field %s. Use = or !=", req.Operator, req.Field)
+		}
+	case time.Time:
+		want, err = parseTimeField(req.Value)
+	}
+	if err != nil {
+		return false, fmt.Errorf("Invalid value %s for field %s", req.Value, req.Field)
+	}
+
+	c := compareFields(value, want)
+	switch req.Operator {
+	case "=", "==":
+		return c == 0, nil
+	case "!=":
+		return c != 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+// parseIntField parses numbers and sizes like 10GiB
+func parseIntField(s string) (int64, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, nil
+	}
+	b, err := humanize.ParseBytes(s)
+	return int64(b), err
+}
+
+// parseTimeField parses times in RFC3339 or dates like 2020-01-31
+func parseTimeField(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
+
+// NewVolumeFieldSet returns the fields of volumes. nodes is used to get the
+// hostname of the node a volume is attached on. If nil, the node id is used.
+func NewVolumeFieldSet(nodes Nodes) *FieldSet {
+	vol := func(f func(v *api.Volume) interface{}) FieldFunc {
+		return func(obj interface{}) interface{} {
+			return f(obj.(*api.Volume))
+		}
+	}
+	return &FieldSet{
+		Kind: "volume",
+		Fields: map[string]FieldFunc{
+			"name": vol(func(v *api.Volume) interface{} { return v.GetLocator().GetName() }),
+			"id":   vol(func(v *api.Volume) interface{} { return v.GetId() }),
+			"size": vol(func(v *api.Volume) interface{} { return int64(v.GetSpec().GetSize()) }),
+			"used": vol(func(v *api.Volume) interface{} { return int64(v.GetUsage()) }),
+			"ha":   vol(func(v *api.Volume) interface{} { return v.GetSpec().GetHaLevel() }),
+			"created": vol(func(v *api.Volume) interface{} {
+				if v.GetCtime() == nil {
+					return nil
+				}
+				return time.Unix(v.GetCtime().GetSeconds(), int64(v.GetCtime().GetNanos()))
+			}),
+			"status": vol(func(v *api.Volume) interface{} { return strings.ToLower(PrettyStatus(v)) }),
+			"attached": vol(func(v *api.Volume) interface{} {
+				return v.GetState() == api.VolumeState_VOLUME_STATE_ATTACHED
+			}),
+			"node": vol(func(v *api.Volume) interface{} {
+				if v.GetState() != api.VolumeState_VOLUME_STATE_ATTACHED || len(v.GetAttachedOn()) == 0 {
+					return nil
+				}
+				if nodes != nil {
+					if n, err := nodes.GetNode(v.GetAttachedOn()); err == nil {
+						return n.GetHostname()
+					}
+				}
+				return v.GetAttachedOn()
+			}),
+			"encrypted": vol(func(v *api.Volume) interface{} { return v.GetSpec().GetEncrypted() }),
+			"shared": vol(func(v *api.Volume) interface{} {
+				return v.GetSpec().GetShared() || v.GetSpec().GetSharedv4()
+			}),
+			"owner": vol(func(v *api.Volume) interface{} { return v.GetSpec().GetOwnership().GetOwner() }),
+		},
+	}
+}
+
+// NewNodeFieldSet returns the fields of storage nodes
+func NewNodeFieldSet() *FieldSet {
+	node := func(f func(n *api.StorageNode) interface{}) FieldFunc {
+		return func(obj interface{}) interface{} {
+			return f(obj.(*api.StorageNode))
+		}
+	}
+	capacity := node(func(n *api.StorageNode) interface{} {
+		_, capacity := GetTotalCapacity(n)
+		return int64(capacity)
+	})
+	return &FieldSet{
+		Kind: "node",
+		Fields: map[string]FieldFunc{
+			"name": node(func(n *api.StorageNode) interface{} { return n.GetHostname() }),
+			"id":   node(func(n *api.StorageNode) interface{} { return n.GetId() }),
+			"ip":   node(func(n *api.StorageNode) interface{} { return n.GetMgmtIp() }),
+			"status": node(func(n *api.StorageNode) interface{} {
+				return strings.ToLower(util.SdkStatusToPrettyString(n.GetStatus()))
+			}),
+			"version": node(func(n *api.StorageNode) interface{} { return GetStorageNodeVersion(n) }),
+			"used": node(func(n *api.StorageNode) interface{} {
+				used, _ := GetTotalCapacity(n)
+				return int64(used)
+			}),
+			"capacity": capacity,
+			"size":     capacity,
+			"pools":    node(func(n *api.StorageNode) interface{} { return int64(len(n.GetPools())) }),
+		},
+	}
+}
+
+// NewPxPvcFieldSet returns the fields of the volumes of PVCs, except for the
+// name and namespace which are the ones of the PVC
+func NewPxPvcFieldSet(nodes Nodes) *FieldSet {
+	fs := &FieldSet{
+		Kind:   "pvc",
+		Fields: make(map[string]FieldFunc),
+	}
+	for name, f := range NewVolumeFieldSet(nodes).Fields {
+		f := f
+		fs.Fields[name] = func(obj interface{}) interface{} {
+			v := obj.(*kubernetes.PxPvc).GetVolume()
+			if v == nil {
+				return nil
+			}
+			return f(v)
+		}
+	}
+	fs.Fields["volume"] = fs.Fields["name"]
+	fs.Fields["name"] = func(obj interface{}) interface{} { return obj.(*kubernetes.PxPvc).Name }
+	fs.Fields["namespace"] = func(obj interface{}) interface{} { return obj.(*kubernetes.PxPvc).Namespace }
+	return fs
+}
+
+// SelectVolumes returns the volumes matching the options
+func SelectVolumes(vols []*api.Volume, nodes Nodes, opts *ListOptions) ([]*api.Volume, error) {
+	objs := make([]interface{}, len(vols))
+	for i, v := range vols {
+		objs[i] = v
+	}
+	objs, err := NewVolumeFieldSet(nodes).Apply(objs, opts)
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]*api.Volume, len(objs))
+	for i, obj := range objs {
+		selected[i] = obj.(*api.Volume)
+	}
+	return selected, nil
+}
+
+// SelectNodes returns the nodes matching the options
+func SelectNodes(nodes []*api.StorageNode, opts *ListOptions) ([]*api.StorageNode, error) {
+	objs := make([]interface{}, len(nodes))
+	for i, n := range nodes {
+		objs[i] = n
+	}
+	objs, err := NewNodeFieldSet().Apply(objs, opts)
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]*api.StorageNode, len(objs))
+	for i, obj := range objs {
+		selected[i] = obj.(*api.StorageNode)
+	}
+	return selected, nil
+}
+
+// SelectPxPvcs returns the PVCs matching the options
+func SelectPxPvcs(pvcs []*kubernetes.PxPvc, nodes Nodes, opts *ListOptions) ([]*kubernetes.PxPvc, error) {
+	objs := make([]interface{}, len(pvcs))
+	for i, p := range pvcs {
+		objs[i] = p
+	}
+	objs, err := NewPxPvcFieldSet(nodes).Apply(objs, opts)
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]*kubernetes.PxPvc, len(objs))
+	for i, obj := range objs {
+		selected[i] = obj.(*kubernetes.PxPvc)
+	}
+	return selected, nil
+}
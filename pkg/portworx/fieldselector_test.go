@@ -0,0 +1,196 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/kubernetes"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestParseFieldSelector(t *testing.T) {
+	reqs, err := ParseFieldSelector("status=down, HA<3,ha<=2,used>=1GiB,node!=host1,name==vol1")
+	assert.NoError(t, err)
+	assert.Equal(t, []*FieldRequirement{
+		{Field: "status", Operator: "=", Value: "down"},
+		{Field: "ha", Operator: "<", Value: "3"},
+		{Field: "ha", Operator: "<=", Value: "2"},
+		{Field: "used", Operator: ">=", Value: "1GiB"},
+		{Field: "node", Operator: "!=", Value: "host1"},
+		{Field: "name", Operator: "==", Value: "vol1"},
+	}, reqs)
+
+	reqs, err = ParseFieldSelector("")
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 0)
+
+	_, err = ParseFieldSelector("status")
+	assert.Error(t, err)
+	_, err = ParseFieldSelector("=down")
+	assert.Error(t, err)
+}
+
+func testFieldVolumes() []*api.Volume {
+	return []*api.Volume{
+		{
+			Id:      "1",
+			Locator: &api.VolumeLocator{Name: "db"},
+			Spec:    &api.VolumeSpec{Size: 10 << 30, HaLevel: 3, Encrypted: true},
+			Usage:   5 << 30,
+			Status:  api.VolumeStatus_VOLUME_STATUS_UP,
+			State:   api.VolumeState_VOLUME_STATE_ATTACHED,
+			Ctime:   &timestamp.Timestamp{Seconds: 300},
+			// Without nodes the id of the node is used
+			AttachedOn: "node1",
+		},
+		{
+			Id:      "2",
+			Locator: &api.VolumeLocator{Name: "logs"},
+			Spec:    &api.VolumeSpec{Size: 1 << 30, HaLevel: 1},
+			Usage:   1 << 20,
+			Status:  api.VolumeStatus_VOLUME_STATUS_DOWN,
+			State:   api.VolumeState_VOLUME_STATE_DETACHED,
+			Ctime:   &timestamp.Timestamp{Seconds: 100},
+		},
+		{
+			Id:      "3",
+			Locator: &api.VolumeLocator{Name: "cache"},
+			Spec:    &api.VolumeSpec{Size: 2 << 30, HaLevel: 2},
+			Status:  api.VolumeStatus_VOLUME_STATUS_DEGRADED,
+			State:   api.VolumeState_VOLUME_STATE_ATTACHED,
+			Ctime:   &timestamp.Timestamp{Seconds: 200},
+
+			AttachedOn: "node2",
+		},
+	}
+}
+
+func volumeNames(vols []*api.Volume) []string {
+	names := make([]string, len(vols))
+	for i, v := range vols {
+		names[i] = v.GetLocator().GetName()
+	}
+	return names
+}
+
+func TestSelectVolumes(t *testing.T) {
+	vols := testFieldVolumes()
+	tests := []struct {
+		opts     ListOptions
+		expected []string
+	}{
+		{ListOptions{}, []string{"db", "logs", "cache"}},
+		{ListOptions{FieldSelector: "status=down"}, []string{"logs"}},
+		{ListOptions{FieldSelector: "status=DOWN"}, []string{"logs"}},
+		{ListOptions{FieldSelector: "ha<3"}, []string{"logs", "cache"}},
+		{ListOptions{FieldSelector: "attached=false"}, []string{"logs"}},
+		{ListOptions{FieldSelector: "encrypted=true"}, []string{"db"}},
+		{ListOptions{FieldSelector: "node=node1"}, []string{"db"}},
+		{ListOptions{FieldSelector: "node!=node1"}, []string{"logs", "cache"}},
+		{ListOptions{FieldSelector: "name=*a*"}, []string{"cache"}},
+		{ListOptions{FieldSelector: "size>=2GiB,ha>1"}, []string{"db", "cache"}},
+		{ListOptions{FieldSelector: "created>1970-01-01T00:02:30Z"}, []string{"db", "cache"}},
+		{ListOptions{SortBy: "size"}, []string{"logs", "cache", "db"}},
+		{ListOptions{SortBy: "-used"}, []string{"db", "logs", "cache"}},
+		{ListOptions{SortBy: "created"}, []string{"logs", "cache", "db"}},
+		{ListOptions{SortBy: "status"}, []string{"cache", "logs", "db"}},
+		// Volumes which are not attached are last
+		{ListOptions{SortBy: "-node"}, []string{"cache", "db", "logs"}},
+		{ListOptions{SortBy: "HA", Limit: 2}, []string{"logs", "cache"}},
+		{ListOptions{FieldSelector: "ha=5"}, []string{}},
+	}
+	for _, test := range tests {
+		selected, err := SelectVolumes(vols, nil, &test.opts)
+		assert.NoError(t, err, "%+v", test.opts)
+		assert.Equal(t, test.expected, volumeNames(selected), "%+v", test.opts)
+	}
+}
+
+func TestSelectVolumesErrors(t *testing.T) {
+	vols := testFieldVolumes()
+	for _, opts := range []ListOptions{
+		{FieldSelector: "color=blue"},
+		{SortBy: "color"},
+		{FieldSelector: "ha<many"},
+		{FieldSelector: "name<vol"},
+		{FieldSelector: "encrypted>false"},
+	} {
+		_, err := SelectVolumes(vols, nil, &opts)
+		assert.Error(t, err, "%+v", opts)
+	}
+}
+
+func TestSelectNodes(t *testing.T) {
+	nodes := []*api.StorageNode{
+		{
+			Hostname: "host1",
+			Status:   api.Status_STATUS_OK,
+			Pools:    []*api.StoragePool{{Used: 10, TotalSize: 100}},
+		},
+		{
+			Hostname: "host2",
+			Status:   api.Status_STATUS_OFFLINE,
+			Pools:    []*api.StoragePool{{Used: 50, TotalSize: 200}},
+		},
+	}
+
+	selected, err := SelectNodes(nodes, &ListOptions{FieldSelector: "status=offline"})
+	assert.NoError(t, err)
+	assert.Len(t, selected, 1)
+	assert.Equal(t, "host2", selected[0].GetHostname())
+
+	selected, err = SelectNodes(nodes, &ListOptions{SortBy: "-capacity", Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, selected, 1)
+	assert.Equal(t, "host2", selected[0].GetHostname())
+
+	selected, err = SelectNodes(nodes, &ListOptions{FieldSelector: "used<20"})
+	assert.NoError(t, err)
+	assert.Len(t, selected, 1)
+	assert.Equal(t, "host1", selected[0].GetHostname())
+}
+
+func TestSelectPxPvcs(t *testing.T) {
+	vols := testFieldVolumes()
+	pvcs := make([]*kubernetes.PxPvc, 0)
+	for i, name := range []string{"pvc-db", "pvc-logs", "pvc-cache"} {
+		p := kubernetes.NewPxPvc(&v1.PersistentVolumeClaim{})
+		p.Name = name
+		p.PxVolume = vols[i]
+		pvcs = append(pvcs, p)
+	}
+	// A PVC without a volume has no values
+	pending := kubernetes.NewPxPvc(&v1.PersistentVolumeClaim{})
+	pending.Name = "pvc-pending"
+	pvcs = append(pvcs, pending)
+
+	selected, err := SelectPxPvcs(pvcs, nil, &ListOptions{FieldSelector: "ha<3,volume=*a*"})
+	assert.NoError(t, err)
+	assert.Len(t, selected, 1)
+	assert.Equal(t, "pvc-cache", selected[0].Name)
+
+	selected, err = SelectPxPvcs(pvcs, nil, &ListOptions{SortBy: "size"})
+	assert.NoError(t, err)
+	names := make([]string, len(selected))
+	for i, p := range selected {
+		names[i] = p.Name
+	}
+	assert.Equal(t, []string{"pvc-logs", "pvc-cache", "pvc-db", "pvc-pending"}, names)
+}
@@ -0,0 +1,251 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/util"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	FsCheckModeCheckHealth = "check_health"
+	FsCheckModeFixSafe     = "fix_safe"
+	FsCheckModeFixAll      = "fix_all"
+)
+
+// FsCheckModes are the modes of a filesystem check
+var FsCheckModes = []string{
+	FsCheckModeCheckHealth,
+	FsCheckModeFixSafe,
+	FsCheckModeFixAll,
+}
+
+// FilesystemOpWaitPeriod is the time between checks of the status of a
+// filesystem trim or check
+var FilesystemOpWaitPeriod = 5 * time.Second
+
+// FilesystemOpStatus is the status of a filesystem trim or check on a volume
+type FilesystemOpStatus struct {
+	Volume  string
+	Status  string
+	Running bool
+	Failed  bool
+	Message string
+	// Mode and Health are only set for filesystem checks
+	Mode   string
+	Health string
+}
+
+func newFsTrimStatus(volume string, s api.FilesystemTrim_FilesystemTrimStatus, msg string) *FilesystemOpStatus {
+	return &FilesystemOpStatus{
+		Volume: volume,
+		Status: fsOpStatusString(s.String(), "FS_TRIM_"),
+		Running: s == api.FilesystemTrim_FS_TRIM_STARTED ||
+			s == api.FilesystemTrim_FS_TRIM_INPROGRESS,
+		Failed:  s == api.FilesystemTrim_FS_TRIM_FAILED,
+		Message: msg,
+	}
+}
+
+func newFsCheckStatus(volume string, s api.FilesystemCheck_FilesystemCheckStatus, msg string) *FilesystemOpStatus {
+	return &FilesystemOpStatus{
+		Volume: volume,
+		Status: fsOpStatusString(s.String(), "FS_CHECK_"),
+		Running: s == api.FilesystemCheck_FS_CHECK_STARTED ||
+			s == api.FilesystemCheck_FS_CHECK_INPROGRESS,
+		Failed:  s == api.FilesystemCheck_FS_CHECK_FAILED,
+		Message: msg,
+	}
+}
+
+// fsOpStatusString changes statuses like FS_TRIM_NOT_RUNNING to "not running"
+func fsOpStatusString(s, prefix string) string {
+	s = strings.Replace(strings.ToLower(strings.TrimPrefix(s, prefix)), "_", " ", -1)
+	if s == "inprogress" {
+		return "in progress"
+	}
+	return s
+}
+
+// FsHealthString returns the filesystem health as shown to the user
+func FsHealthString(h api.FilesystemHealthStatus) string {
+	return fsOpStatusString(h.String(), "FS_HEALTH_STATUS_")
+}
+
+// ValidateFsCheckMode returns an error if the filesystem check mode is unknown
+func ValidateFsCheckMode(mode string) error {
+	if !util.ListContains(FsCheckModes, mode) {
+		return fmt.Errorf("Invalid mode %s. Valid modes are %s", mode, strings.Join(FsCheckModes, ", "))
+	}
+	return nil
+}
+
+// GetFsTrimMountPath returns the path where the volume is mounted
+func GetFsTrimMountPath(v *api.Volume) (string, error) {
+	if len(v.GetAttachPath()) == 0 {
+		return "", fmt.Errorf("Volume %s is not mounted. A filesystem trim can only run on mounted volumes",
+			v.GetLocator().GetName())
+	}
+	return v.GetAttachPath()[0], nil
+}
+
+// StartFsTrim starts a filesystem trim on the volume mounted on the path
+func StartFsTrim(ctx context.Context, conn *grpc.ClientConn, volume, path string) (*FilesystemOpStatus, error) {
+	resp, err := api.NewOpenStorageFilesystemTrimClient(conn).Start(ctx, &api.SdkFilesystemTrimStartRequest{
+		VolumeId:  volume,
+		MountPath: path,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newFsTrimStatus(volume, resp.GetStatus(), resp.GetMessage()), nil
+}
+
+// GetFsTrimStatus returns the status of the filesystem trim on the volume
+func GetFsTrimStatus(ctx context.Context, conn *grpc.ClientConn, volume, path string) (*FilesystemOpStatus, error) {
+	resp, err := api.NewOpenStorageFilesystemTrimClient(conn).Status(ctx, &api.SdkFilesystemTrimStatusRequest{
+		VolumeId:  volume,
+		MountPath: path,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newFsTrimStatus(volume, resp.GetStatus(), resp.GetMessage()), nil
+}
+
+// StopFsTrim stops the filesystem trim on the volume
+func StopFsTrim(ctx context.Context, conn *grpc.ClientConn, volume, path string) error {
+	_, err := api.NewOpenStorageFilesystemTrimClient(conn).Stop(ctx, &api.SdkFilesystemTrimStopRequest{
+		VolumeId:  volume,
+		MountPath: path,
+	})
+	return err
+}
+
+// StartFsCheck starts a filesystem check on the unmounted volume
+func StartFsCheck(ctx context.Context, conn *grpc.ClientConn, volume, mode string) (*FilesystemOpStatus, error) {
+	resp, err := api.NewOpenStorageFilesystemCheckClient(conn).Start(ctx, &api.SdkFilesystemCheckStartRequest{
+		VolumeId: volume,
+		Mode:     mode,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s := newFsCheckStatus(volume, resp.GetStatus(), resp.GetMessage())
+	s.Mode = mode
+	return s, nil
+}
+
+// GetFsCheckStatus returns the status of the filesystem check on the volume
+func GetFsCheckStatus(ctx context.Context, conn *grpc.ClientConn, volume string) (*FilesystemOpStatus, error) {
+	resp, err := api.NewOpenStorageFilesystemCheckClient(conn).Status(ctx, &api.SdkFilesystemCheckStatusRequest{
+		VolumeId: volume,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s := newFsCheckStatus(volume, resp.GetStatus(), resp.GetMessage())
+	s.Mode = resp.GetMode()
+	s.Health = FsHealthString(resp.GetHealthStatus())
+	return s, nil
+}
+
+// StopFsCheck stops the filesystem check on the volume
+func StopFsCheck(ctx context.Context, conn *grpc.ClientConn, volume string) error {
+	_, err := api.NewOpenStorageFilesystemCheckClient(conn).Stop(ctx, &api.SdkFilesystemCheckStopRequest{
+		VolumeId: volume,
+	})
+	return err
+}
+
+// WaitForFilesystemOp waits until the filesystem trim or check on the volume
+// returned by getStatus is no longer running. The status is printed to Stderr
+// every time it changes.
+func WaitForFilesystemOp(
+	volume string,
+	timeout time.Duration,
+	getStatus func() (*FilesystemOpStatus, error),
+) (*FilesystemOpStatus, error) {
+	var (
+		current *FilesystemOpStatus
+		last    string
+	)
+	err := util.WaitFor(timeout, FilesystemOpWaitPeriod, func() (bool, error) {
+		var err error
+		current, err = getStatus()
+		if err != nil {
+			return false, err
+		}
+		if !current.Running {
+			return false, nil
+		}
+
+		progress := current.Status
+		if len(current.Message) != 0 {
+			progress += ": " + current.Message
+		}
+		if progress != last {
+			util.Eprintf("Volume %s %s\n", volume, progress)
+			last = progress
+		}
+		return true, nil
+	})
+	if err == util.ErrWaitTimeout {
+		return current, fmt.Errorf("Timed out after %v waiting for volume %s", timeout, volume)
+	}
+	return current, err
+}
+
+// ListRunningFilesystemOps returns the filesystem trims or checks running on
+// the volumes. getStatus returns nil for volumes which are skipped.
+func ListRunningFilesystemOps(
+	vols []*api.Volume,
+	workers int,
+	getStatus func(v *api.Volume) (*FilesystemOpStatus, error),
+) ([]*FilesystemOpStatus, error) {
+	statuses := make([]*FilesystemOpStatus, len(vols))
+	errs := util.ForEach(len(vols), workers, false, func(i int) error {
+		s, err := getStatus(vols[i])
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		statuses[i] = s
+		return err
+	})
+	for i, err := range errs {
+		if err != nil && err != util.ErrNotStarted {
+			return nil, util.PxErrorMessagef(err, "Failed to get the status of volume %s",
+				vols[i].GetLocator().GetName())
+		}
+	}
+
+	running := make([]*FilesystemOpStatus, 0)
+	for _, s := range statuses {
+		if s != nil && s.Running {
+			running = append(running, s)
+		}
+	}
+	return running, nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFilesystemOpStatus(t *testing.T) {
+	s := newFsTrimStatus("vol", api.FilesystemTrim_FS_TRIM_INPROGRESS, "50%")
+	assert.Equal(t, "in progress", s.Status)
+	assert.True(t, s.Running)
+	assert.False(t, s.Failed)
+
+	s = newFsTrimStatus("vol", api.FilesystemTrim_FS_TRIM_NOT_RUNNING, "")
+	assert.Equal(t, "not running", s.Status)
+	assert.False(t, s.Running)
+
+	s = newFsCheckStatus("vol", api.FilesystemCheck_FS_CHECK_FAILED, "error")
+	assert.Equal(t, "failed", s.Status)
+	assert.True(t, s.Failed)
+
+	assert.Equal(t, "safe to fix", FsHealthString(api.FilesystemHealthStatus_FS_HEALTH_STATUS_SAFE_TO_FIX))
+
+	assert.NoError(t, ValidateFsCheckMode(FsCheckModeFixSafe))
+	assert.Error(t, ValidateFsCheckMode("fix_some"))
+
+	_, err := GetFsTrimMountPath(&api.Volume{})
+	assert.Error(t, err)
+	path, err := GetFsTrimMountPath(&api.Volume{AttachPath: []string{"/mnt/vol"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "/mnt/vol", path)
+}
+
+func TestWaitForFilesystemOp(t *testing.T) {
+	var b bytes.Buffer
+	originalStderr, originalPeriod := util.Stderr, FilesystemOpWaitPeriod
+	util.Stderr = &b
+	FilesystemOpWaitPeriod = time.Millisecond
+	defer func() { util.Stderr, FilesystemOpWaitPeriod = originalStderr, originalPeriod }()
+
+	statuses := []api.FilesystemTrim_FilesystemTrimStatus{
+		api.FilesystemTrim_FS_TRIM_STARTED,
+		api.FilesystemTrim_FS_TRIM_INPROGRESS,
+		api.FilesystemTrim_FS_TRIM_INPROGRESS,
+		api.FilesystemTrim_FS_TRIM_COMPLETED,
+	}
+	calls := 0
+	s, err := WaitForFilesystemOp("vol", time.Second, func() (*FilesystemOpStatus, error) {
+		calls++
+		return newFsTrimStatus("vol", statuses[calls-1], ""), nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "completed", s.Status)
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, "Volume vol started\nVolume vol in progress\n", b.String())
+
+	_, err = WaitForFilesystemOp("vol", 10*time.Millisecond, func() (*FilesystemOpStatus, error) {
+		return newFsTrimStatus("vol", api.FilesystemTrim_FS_TRIM_INPROGRESS, ""), nil
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Timed out")
+
+	// The timer may fire before the status is read
+	_, err = WaitForFilesystemOp("vol", 0, func() (*FilesystemOpStatus, error) {
+		return newFsTrimStatus("vol", api.FilesystemTrim_FS_TRIM_INPROGRESS, ""), nil
+	})
+	assert.Error(t, err)
+}
+
+func TestListRunningFilesystemOps(t *testing.T) {
+	vols := []*api.Volume{
+		{Locator: &api.VolumeLocator{Name: "running"}},
+		{Locator: &api.VolumeLocator{Name: "done"}},
+		{Locator: &api.VolumeLocator{Name: "skipped"}},
+		{Locator: &api.VolumeLocator{Name: "notfound"}},
+	}
+	getStatus := func(v *api.Volume) (*FilesystemOpStatus, error) {
+		switch v.GetLocator().GetName() {
+		case "running":
+			return newFsCheckStatus("running", api.FilesystemCheck_FS_CHECK_INPROGRESS, ""), nil
+		case "done":
+			return newFsCheckStatus("done", api.FilesystemCheck_FS_CHECK_COMPLETED, ""), nil
+		case "notfound":
+			return nil, status.Errorf(codes.NotFound, "not found")
+		}
+		return nil, nil
+	}
+
+	running, err := ListRunningFilesystemOps(vols, 2, getStatus)
+	assert.NoError(t, err)
+	assert.Len(t, running, 1)
+	assert.Equal(t, "running", running[0].Volume)
+
+	_, err = ListRunningFilesystemOps(vols, 2, func(v *api.Volume) (*FilesystemOpStatus, error) {
+		return nil, fmt.Errorf("unavailable")
+	})
+	assert.Error(t, err)
+}
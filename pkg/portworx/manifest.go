@@ -0,0 +1,634 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/util"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// VolumeManifest is a list of volumes declared in a YAML or JSON file
+type VolumeManifest struct {
+	Volumes []*ManifestVolume `json:"volumes" yaml:"volumes"`
+}
+
+// ManifestVolume is the declared state of a volume. Fields which are not
+// set are not managed by the manifest and are left unchanged on update.
+type ManifestVolume struct {
+	Name string `json:"name" yaml:"name"`
+	// Size in GiB
+	Size uint64 `json:"size,omitempty" yaml:"size,omitempty"`
+	// Replicas is the HA level of the volume
+	Replicas int64             `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Filesystem, Encrypted, Nodes, AggregationLevel and GroupEnforced are
+	// only used when the volume is created
+	Filesystem       string   `json:"fs,omitempty" yaml:"fs,omitempty"`
+	Encrypted        *bool    `json:"encryption,omitempty" yaml:"encryption,omitempty"`
+	Nodes            []string `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	AggregationLevel uint32   `json:"aggregation-level,omitempty" yaml:"aggregation-level,omitempty"`
+	GroupEnforced    *bool    `json:"group-enforced,omitempty" yaml:"group-enforced,omitempty"`
+	// Group is the consistency group of the volume
+	Group      string `json:"group,omitempty" yaml:"group,omitempty"`
+	IoProfile  string `json:"io-profile,omitempty" yaml:"io-profile,omitempty"`
+	QueueDepth uint32 `json:"queue-depth,omitempty" yaml:"queue-depth,omitempty"`
+	Scale      uint32 `json:"scale,omitempty" yaml:"scale,omitempty"`
+	EarlyAck   *bool  `json:"early-ack,omitempty" yaml:"early-ack,omitempty"`
+	AsyncIo    *bool  `json:"async-io,omitempty" yaml:"async-io,omitempty"`
+	Journal    *bool  `json:"journal,omitempty" yaml:"journal,omitempty"`
+	Nodiscard  *bool  `json:"nodiscard,omitempty" yaml:"nodiscard,omitempty"`
+	// Shared is a sharedv4 volume, or a legacy shared volume if SharedModel
+	// is legacy
+	Shared           *bool                 `json:"shared,omitempty" yaml:"shared,omitempty"`
+	SharedModel      string                `json:"shared-model,omitempty" yaml:"shared-model,omitempty"`
+	Sticky           *bool                 `json:"sticky,omitempty" yaml:"sticky,omitempty"`
+	SnapshotSchedule *SnapshotScheduleOpts `json:"snapshot-schedule,omitempty" yaml:"snapshot-schedule,omitempty"`
+	// Collaborators and groups with their access type: name:r, name:w or name:a
+	Collaborators []string `json:"collaborators,omitempty" yaml:"collaborators,omitempty"`
+	Groups        []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+// Sharing models of a manifest volume
+const (
+	SharedModelSharedv4 = "sharedv4"
+	SharedModelLegacy   = "legacy"
+)
+
+// ReadVolumeManifest reads a volume manifest from a YAML or JSON file.
+// Use "-" to read from stdin.
+func ReadVolumeManifest(filename string) (*VolumeManifest, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if filename == "-" {
+		data, err = ioutil.ReadAll(util.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(filename)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read %s: %v", filename, err)
+	}
+
+	m, err := ParseVolumeManifest(data)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid manifest %s: %v", filename, err)
+	}
+	return m, nil
+}
+
+// ParseVolumeManifest parses and validates a volume manifest in YAML or JSON
+func ParseVolumeManifest(data []byte) (*VolumeManifest, error) {
+	m := &VolumeManifest{}
+	if err := yaml.UnmarshalStrict(data, m); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for i, v := range m.Volumes {
+		if v == nil || len(v.Name) == 0 {
+			return nil, fmt.Errorf("volume %d must have a name", i+1)
+		}
+		if names[v.Name] {
+			return nil, fmt.Errorf("volume %s is declared more than once", v.Name)
+		}
+		names[v.Name] = true
+
+		if _, err := v.CreateRequest(); err != nil {
+			return nil, fmt.Errorf("volume %s: %v", v.Name, err)
+		}
+	}
+	return m, nil
+}
+
+// NewManifestVolume returns the manifest of an existing volume. Runtime
+// only fields are not included and fields with default values are omitted.
+func NewManifestVolume(vol *api.Volume) (*ManifestVolume, error) {
+	spec := vol.GetSpec()
+	v := &ManifestVolume{
+		Name: vol.GetLocator().GetName(),
+		Size: (spec.GetSize() + uint64(util.Gi) - 1) / uint64(util.Gi),
+	}
+
+	if spec.GetHaLevel() > 1 {
+		v.Replicas = spec.GetHaLevel()
+	}
+	if len(vol.GetLocator().GetVolumeLabels()) != 0 {
+		v.Labels = vol.GetLocator().GetVolumeLabels()
+	}
+	if spec.GetFormat() != api.FSType_FS_TYPE_EXT4 {
+		v.Filesystem = FSTypeString(spec.GetFormat())
+	}
+	if spec.GetEncrypted() {
+		v.Encrypted = &spec.Encrypted
+	}
+	if len(spec.GetReplicaSet().GetNodes()) != 0 {
+		v.Nodes = spec.GetReplicaSet().GetNodes()
+	}
+	if spec.GetAggregationLevel() > 1 {
+		v.AggregationLevel = spec.GetAggregationLevel()
+	}
+	if spec.GetGroupEnforced() {
+		v.GroupEnforced = &spec.GroupEnforced
+	}
+	v.Group = spec.GetGroup().GetId()
+	if spec.GetIoProfile() != api.IoProfile_IO_PROFILE_SEQUENTIAL {
+		v.IoProfile = IoProfileString(spec.GetIoProfile())
+	}
+	if spec.GetQueueDepth() != 0 && spec.GetQueueDepth() != 128 {
+		v.QueueDepth = spec.GetQueueDepth()
+	}
+	if spec.GetScale() > 1 {
+		v.Scale = spec.GetScale()
+	}
+	if spec.GetIoStrategy().GetEarlyAck() {
+		v.EarlyAck = &spec.IoStrategy.EarlyAck
+	}
+	if spec.GetIoStrategy().GetAsyncIo() {
+		v.AsyncIo = &spec.IoStrategy.AsyncIo
+	}
+	if spec.GetJournal() {
+		v.Journal = &spec.Journal
+	}
+	if spec.GetNodiscard() {
+		v.Nodiscard = &spec.Nodiscard
+	}
+	if spec.GetSharedv4() {
+		v.Shared = &spec.Sharedv4
+	} else if spec.GetShared() {
+		v.Shared = &spec.Shared
+		v.SharedModel = SharedModelLegacy
+	}
+	if spec.GetSticky() {
+		v.Sticky = &spec.Sticky
+	}
+	if len(spec.GetSnapshotSchedule()) != 0 {
+		schedule, err := NewSnapshotScheduleOpts(spec.GetSnapshotSchedule())
+		if err != nil {
+			return nil, fmt.Errorf("Volume %s has an invalid snapshot schedule: %v", v.Name, err)
+		}
+		v.SnapshotSchedule = schedule
+	}
+	if acls := spec.GetOwnership().GetAcls(); acls != nil {
+		if len(acls.GetCollaborators()) != 0 {
+			v.Collaborators = strings.Split(aclString(acls.GetCollaborators()), ",")
+		}
+		if len(acls.GetGroups()) != 0 {
+			v.Groups = strings.Split(aclString(acls.GetGroups()), ",")
+		}
+	}
+	return v, nil
+}
+
+// CreateRequest returns the request to create the volume. Fields which are
+// not set use the same defaults as pxc volume create.
+func (v *ManifestVolume) CreateRequest() (*api.SdkVolumeCreateRequest, error) {
+	req := &api.SdkVolumeCreateRequest{
+		Name:   v.Name,
+		Labels: v.Labels,
+		Spec: &api.VolumeSpec{
+			Size:             v.Size * uint64(util.Gi),
+			HaLevel:          1,
+			Format:           api.FSType_FS_TYPE_EXT4,
+			IoProfile:        api.IoProfile_IO_PROFILE_SEQUENTIAL,
+			AggregationLevel: 1,
+			QueueDepth:       128,
+			Scale:            1,
+			IoStrategy:       &api.IoStrategy{},
+		},
+	}
+	spec := req.Spec
+
+	if v.Replicas != 0 {
+		spec.HaLevel = v.Replicas
+	}
+	if len(v.Filesystem) != 0 {
+		fs, err := GetFSType(v.Filesystem)
+		if err != nil {
+			return nil, err
+		}
+		spec.Format = fs
+	}
+	if v.Encrypted != nil {
+		spec.Encrypted = *v.Encrypted
+	}
+	if len(v.Nodes) != 0 {
+		spec.ReplicaSet = &api.ReplicaSet{
+			Nodes: v.Nodes,
+		}
+	}
+	if v.AggregationLevel != 0 {
+		spec.AggregationLevel = v.AggregationLevel
+	}
+	if v.GroupEnforced != nil {
+		spec.GroupEnforced = *v.GroupEnforced
+	}
+	if len(v.Group) != 0 {
+		spec.Group = &api.Group{Id: v.Group}
+	}
+	if len(v.IoProfile) != 0 {
+		ioProfile, err := GetIoProfile(v.IoProfile)
+		if err != nil {
+			return nil, err
+		}
+		spec.IoProfile = ioProfile
+	}
+	if v.QueueDepth != 0 {
+		spec.QueueDepth = v.QueueDepth
+	}
+	if v.Scale != 0 {
+		spec.Scale = v.Scale
+	}
+	if v.EarlyAck != nil {
+		spec.IoStrategy.EarlyAck = *v.EarlyAck
+	}
+	if v.AsyncIo != nil {
+		spec.IoStrategy.AsyncIo = *v.AsyncIo
+	}
+	if v.Journal != nil {
+		spec.Journal = *v.Journal
+	}
+	if v.Nodiscard != nil {
+		spec.Nodiscard = *v.Nodiscard
+	}
+	legacy, err := v.legacyShared()
+	if err != nil {
+		return nil, err
+	}
+	if v.Shared != nil {
+		if legacy {
+			spec.Shared = *v.Shared
+		} else {
+			spec.Sharedv4 = *v.Shared
+		}
+	}
+	if v.Sticky != nil {
+		spec.Sticky = *v.Sticky
+	}
+	if v.SnapshotSchedule != nil {
+		schedule, err := v.SnapshotSchedule.ScheduleString()
+		if err != nil {
+			return nil, err
+		}
+		spec.SnapshotSchedule = schedule
+	}
+
+	acls, err := v.acls()
+	if err != nil {
+		return nil, err
+	}
+	if acls != nil {
+		spec.Ownership = &api.Ownership{
+			Acls: acls,
+		}
+	}
+
+	if err := ValidateVolumeCreateRequest(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// UpdateRequests returns the requests needed to update the volume to the
+// declared state and the list of fields which changed. HA level changes
+// cannot be combined with other changes, so they are sent on their own.
+func (v *ManifestVolume) UpdateRequests(
+	current *api.Volume,
+) ([]*api.SdkVolumeUpdateRequest, []util.FieldChange, error) {
+	var changes []util.FieldChange
+	currentSpec := current.GetSpec()
+	specUpdate := &api.VolumeSpecUpdate{}
+	specChanged := false
+
+	// Size
+	desiredSize := v.Size * uint64(util.Gi)
+	if v.Size != 0 && desiredSize != currentSpec.GetSize() {
+		if desiredSize < currentSpec.GetSize() {
+			return nil, nil, fmt.Errorf("Volume %s cannot be shrunk from %d GiB to %d GiB",
+				v.Name, currentSpec.GetSize()/uint64(util.Gi), v.Size)
+		}
+		specUpdate.SizeOpt = &api.VolumeSpecUpdate_Size{Size: desiredSize}
+		specChanged = true
+		changes = append(changes, util.FieldChange{
+			Field:   "size",
+			Current: fmt.Sprintf("%d GiB", currentSpec.GetSize()/uint64(util.Gi)),
+			Desired: fmt.Sprintf("%d GiB", v.Size),
+		})
+	}
+
+	// IO profile
+	if len(v.IoProfile) != 0 {
+		ioProfile, err := GetIoProfile(v.IoProfile)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ioProfile != currentSpec.GetIoProfile() {
+			specUpdate.IoProfileOpt = &api.VolumeSpecUpdate_IoProfile{IoProfile: ioProfile}
+			specChanged = true
+			changes = append(changes, util.FieldChange{
+				Field:   "io-profile",
+				Current: IoProfileString(currentSpec.GetIoProfile()),
+				Desired: v.IoProfile,
+			})
+		}
+	}
+
+	// Queue depth and scale
+	if v.QueueDepth != 0 && v.QueueDepth != currentSpec.GetQueueDepth() {
+		specUpdate.QueueDepthOpt = &api.VolumeSpecUpdate_QueueDepth{QueueDepth: v.QueueDepth}
+		specChanged = true
+		changes = append(changes, util.FieldChange{
+			Field:   "queue-depth",
+			Current: fmt.Sprintf("%d", currentSpec.GetQueueDepth()),
+			Desired: fmt.Sprintf("%d", v.QueueDepth),
+		})
+	}
+	if v.Scale != 0 && v.Scale != currentSpec.GetScale() {
+		specUpdate.ScaleOpt = &api.VolumeSpecUpdate_Scale{Scale: v.Scale}
+		specChanged = true
+		changes = append(changes, util.FieldChange{
+			Field:   "scale",
+			Current: fmt.Sprintf("%d", currentSpec.GetScale()),
+			Desired: fmt.Sprintf("%d", v.Scale),
+		})
+	}
+
+	// Consistency group
+	if len(v.Group) != 0 && v.Group != currentSpec.GetGroup().GetId() {
+		specUpdate.GroupOpt = &api.VolumeSpecUpdate_Group{Group: &api.Group{Id: v.Group}}
+		specChanged = true
+		changes = append(changes, util.FieldChange{
+			Field:   "group",
+			Current: currentSpec.GetGroup().GetId(),
+			Desired: v.Group,
+		})
+	}
+
+	// IO strategy. The update replaces both values, so the values which
+	// are not declared are kept.
+	ioStrategy := &api.IoStrategy{
+		EarlyAck: currentSpec.GetIoStrategy().GetEarlyAck(),
+		AsyncIo:  currentSpec.GetIoStrategy().GetAsyncIo(),
+	}
+	ioStrategyChanged := false
+	if v.EarlyAck != nil && *v.EarlyAck != ioStrategy.GetEarlyAck() {
+		changes = append(changes, boolChange("early-ack", ioStrategy.GetEarlyAck(), *v.EarlyAck))
+		ioStrategy.EarlyAck = *v.EarlyAck
+		ioStrategyChanged = true
+	}
+	if v.AsyncIo != nil && *v.AsyncIo != ioStrategy.GetAsyncIo() {
+		changes = append(changes, boolChange("async-io", ioStrategy.GetAsyncIo(), *v.AsyncIo))
+		ioStrategy.AsyncIo = *v.AsyncIo
+		ioStrategyChanged = true
+	}
+	if ioStrategyChanged {
+		specUpdate.IoStrategy = ioStrategy
+		specChanged = true
+	}
+
+	if v.Journal != nil && *v.Journal != currentSpec.GetJournal() {
+		specUpdate.JournalOpt = &api.VolumeSpecUpdate_Journal{Journal: *v.Journal}
+		specChanged = true
+		changes = append(changes, boolChange("journal", currentSpec.GetJournal(), *v.Journal))
+	}
+	if v.Nodiscard != nil && *v.Nodiscard != currentSpec.GetNodiscard() {
+		specUpdate.NodiscardOpt = &api.VolumeSpecUpdate_Nodiscard{Nodiscard: *v.Nodiscard}
+		specChanged = true
+		changes = append(changes, boolChange("nodiscard", currentSpec.GetNodiscard(), *v.Nodiscard))
+	}
+
+	// Sharing
+	legacy, err := v.legacyShared()
+	if err != nil {
+		return nil, nil, err
+	}
+	if v.Shared != nil && legacy && *v.Shared != currentSpec.GetShared() {
+		specUpdate.SharedOpt = &api.VolumeSpecUpdate_Shared{Shared: *v.Shared}
+		specChanged = true
+		changes = append(changes, boolChange("shared", currentSpec.GetShared(), *v.Shared))
+	}
+	if v.Shared != nil && !legacy && *v.Shared != currentSpec.GetSharedv4() {
+		specUpdate.Sharedv4Opt = &api.VolumeSpecUpdate_Sharedv4{Sharedv4: *v.Shared}
+		specChanged = true
+		changes = append(changes, boolChange("shared", currentSpec.GetSharedv4(), *v.Shared))
+	}
+	if v.Sticky != nil && *v.Sticky != currentSpec.GetSticky() {
+		specUpdate.StickyOpt = &api.VolumeSpecUpdate_Sticky{Sticky: *v.Sticky}
+		specChanged = true
+		changes = append(changes, boolChange("sticky", currentSpec.GetSticky(), *v.Sticky))
+	}
+
+	// Snapshot schedule
+	if v.SnapshotSchedule != nil {
+		schedule, err := v.SnapshotSchedule.ScheduleString()
+		if err != nil {
+			return nil, nil, err
+		}
+		currentSummary := SnapshotScheduleSummary(currentSpec.GetSnapshotSchedule())
+		desiredSummary := SnapshotScheduleSummary(schedule)
+		if currentSummary != desiredSummary {
+			specUpdate.SnapshotScheduleOpt = &api.VolumeSpecUpdate_SnapshotSchedule{
+				SnapshotSchedule: schedule,
+			}
+			specChanged = true
+			changes = append(changes, util.FieldChange{
+				Field:   "snapshot-schedule",
+				Current: currentSummary,
+				Desired: desiredSummary,
+			})
+		}
+	}
+
+	// Access controls
+	acls, err := v.acls()
+	if err != nil {
+		return nil, nil, err
+	}
+	if acls != nil {
+		currentAcls := currentSpec.GetOwnership().GetAcls()
+		desiredAcls := &api.Ownership_AccessControl{
+			Collaborators: currentAcls.GetCollaborators(),
+			Groups:        currentAcls.GetGroups(),
+		}
+		aclChanged := false
+		if v.Collaborators != nil &&
+			aclString(currentAcls.GetCollaborators()) != aclString(acls.GetCollaborators()) {
+			desiredAcls.Collaborators = acls.GetCollaborators()
+			aclChanged = true
+			changes = append(changes, util.FieldChange{
+				Field:   "collaborators",
+				Current: aclString(currentAcls.GetCollaborators()),
+				Desired: aclString(acls.GetCollaborators()),
+			})
+		}
+		if v.Groups != nil &&
+			aclString(currentAcls.GetGroups()) != aclString(acls.GetGroups()) {
+			desiredAcls.Groups = acls.GetGroups()
+			aclChanged = true
+			changes = append(changes, util.FieldChange{
+				Field:   "groups",
+				Current: aclString(currentAcls.GetGroups()),
+				Desired: aclString(acls.GetGroups()),
+			})
+		}
+		if aclChanged {
+			specUpdate.Ownership = &api.Ownership{
+				Acls: desiredAcls,
+			}
+			specChanged = true
+		}
+	}
+
+	// Labels. Only the labels declared in the manifest are added or
+	// updated. Labels not in the manifest, such as the ones set by
+	// Kubernetes, are kept.
+	var labels map[string]string
+	if v.Labels != nil {
+		currentLabels := current.GetLocator().GetVolumeLabels()
+		desiredLabels := make(map[string]string)
+		for k, value := range currentLabels {
+			desiredLabels[k] = value
+		}
+		labels = make(map[string]string)
+		for k, value := range v.Labels {
+			if currentLabels[k] != value {
+				labels[k] = value
+				desiredLabels[k] = value
+			}
+		}
+		if len(labels) != 0 {
+			changes = append(changes, util.FieldChange{
+				Field:   "labels",
+				Current: labelsString(currentLabels),
+				Desired: labelsString(desiredLabels),
+			})
+		} else {
+			labels = nil
+		}
+	}
+
+	var reqs []*api.SdkVolumeUpdateRequest
+	if specChanged || labels != nil {
+		reqs = append(reqs, &api.SdkVolumeUpdateRequest{
+			VolumeId: current.GetId(),
+			Labels:   labels,
+			Spec:     specUpdate,
+		})
+	}
+
+	// HA level
+	if v.Replicas != 0 && v.Replicas != currentSpec.GetHaLevel() {
+		reqs = append(reqs, &api.SdkVolumeUpdateRequest{
+			VolumeId: current.GetId(),
+			Spec: &api.VolumeSpecUpdate{
+				HaLevelOpt: &api.VolumeSpecUpdate_HaLevel{HaLevel: v.Replicas},
+				// Replicaset needs to be passed due to know volume driver issue (pwx-9500)
+				ReplicaSet: &api.ReplicaSet{},
+			},
+		})
+		changes = append(changes, util.FieldChange{
+			Field:   "replicas",
+			Current: fmt.Sprintf("%d", currentSpec.GetHaLevel()),
+			Desired: fmt.Sprintf("%d", v.Replicas),
+		})
+	}
+
+	for _, req := range reqs {
+		if err := ValidateVolumeSpec(req.GetSpec()); err != nil {
+			return nil, nil, err
+		}
+	}
+	return reqs, changes, nil
+}
+
+// legacyShared returns true if the volume uses the legacy shared model
+func (v *ManifestVolume) legacyShared() (bool, error) {
+	switch v.SharedModel {
+	case "", SharedModelSharedv4:
+		return false, nil
+	case SharedModelLegacy:
+		return true, nil
+	}
+	return false, fmt.Errorf("Invalid shared model %s. Valid values are [%s, %s]",
+		v.SharedModel, SharedModelSharedv4, SharedModelLegacy)
+}
+
+// boolChange returns the change of a boolean field
+func boolChange(field string, current, desired bool) util.FieldChange {
+	return util.FieldChange{
+		Field:   field,
+		Current: fmt.Sprintf("%t", current),
+		Desired: fmt.Sprintf("%t", desired),
+	}
+}
+
+// acls returns the access controls declared, or nil if not set
+func (v *ManifestVolume) acls() (*api.Ownership_AccessControl, error) {
+	if v.Collaborators == nil && v.Groups == nil {
+		return nil, nil
+	}
+
+	acls := &api.Ownership_AccessControl{}
+	if len(v.Collaborators) != 0 {
+		collaborators, err := util.GetAclMapFromString(strings.Join(v.Collaborators, ","))
+		if err != nil {
+			return nil, err
+		}
+		acls.Collaborators = collaborators
+	}
+	if len(v.Groups) != 0 {
+		groups, err := util.GetAclMapFromString(strings.Join(v.Groups, ","))
+		if err != nil {
+			return nil, err
+		}
+		acls.Groups = groups
+	}
+	return acls, nil
+}
+
+// aclString returns the access list sorted by name as name:access
+func aclString(acls map[string]api.Ownership_AccessType) string {
+	s := make([]string, 0, len(acls))
+	for name, access := range acls {
+		s = append(s, name+":"+aclAccessString(access))
+	}
+	sort.Strings(s)
+	return strings.Join(s, ",")
+}
+
+func aclAccessString(access api.Ownership_AccessType) string {
+	switch access {
+	case api.Ownership_Write:
+		return "w"
+	case api.Ownership_Admin:
+		return "a"
+	}
+	return "r"
+}
+
+// labelsString returns the labels sorted by key as k1=v1,k2=v2
+func labelsString(labels map[string]string) string {
+	s := strings.Split(util.StringMapToCommaString(labels), ",")
+	sort.Strings(s)
+	return strings.Trim(strings.Join(s, ","), ",")
+}
@@ -0,0 +1,362 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"testing"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+const testManifest = `
+volumes:
+- name: db-data
+  size: 20
+  replicas: 2
+  labels:
+    app: db
+  io-profile: db
+  shared: true
+  snapshot-schedule:
+    daily: ["02:00,7"]
+  collaborators: ["user1:w"]
+- name: logs
+`
+
+func TestParseVolumeManifest(t *testing.T) {
+	m, err := ParseVolumeManifest([]byte(testManifest))
+	assert.NoError(t, err)
+	assert.Len(t, m.Volumes, 2)
+
+	req, err := m.Volumes[0].CreateRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, "db-data", req.GetName())
+	assert.Equal(t, uint64(20*util.Gi), req.GetSpec().GetSize())
+	assert.Equal(t, int64(2), req.GetSpec().GetHaLevel())
+	assert.Equal(t, api.IoProfile_IO_PROFILE_DB, req.GetSpec().GetIoProfile())
+	assert.True(t, req.GetSpec().GetSharedv4())
+	assert.NotEmpty(t, req.GetSpec().GetSnapshotSchedule())
+	assert.Equal(t, api.Ownership_Write, req.GetSpec().GetOwnership().GetAcls().GetCollaborators()["user1"])
+	assert.Equal(t, map[string]string{"app": "db"}, req.GetLabels())
+
+	// Defaults are the same as volume create
+	req, err = m.Volumes[1].CreateRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), req.GetSpec().GetHaLevel())
+	assert.Equal(t, api.FSType_FS_TYPE_EXT4, req.GetSpec().GetFormat())
+	assert.Equal(t, uint32(128), req.GetSpec().GetQueueDepth())
+
+	// JSON is also accepted
+	m, err = ParseVolumeManifest([]byte(`{"volumes": [{"name": "a", "size": 1}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "a", m.Volumes[0].Name)
+
+	// Invalid manifests
+	_, err = ParseVolumeManifest([]byte("volumes:\n- size: 1\n"))
+	assert.Error(t, err)
+	_, err = ParseVolumeManifest([]byte("volumes:\n- name: a\n- name: a\n"))
+	assert.Error(t, err)
+	_, err = ParseVolumeManifest([]byte("volumes:\n- name: a\n  io-profile: fast\n"))
+	assert.Error(t, err)
+	_, err = ParseVolumeManifest([]byte("volumes:\n- name: a\n  sise: 1\n"))
+	assert.Error(t, err)
+}
+
+func TestManifestVolumeUpdateRequests(t *testing.T) {
+	m, err := ParseVolumeManifest([]byte(testManifest))
+	assert.NoError(t, err)
+	desired := m.Volumes[0]
+
+	req, err := desired.CreateRequest()
+	assert.NoError(t, err)
+	current := &api.Volume{
+		Id:      "123",
+		Spec:    req.GetSpec(),
+		Locator: &api.VolumeLocator{VolumeLabels: map[string]string{"app": "db"}},
+	}
+
+	// No drift
+	reqs, changes, err := desired.UpdateRequests(current)
+	assert.NoError(t, err)
+	assert.Empty(t, reqs)
+	assert.Empty(t, changes)
+
+	// Drift in the size, labels and HA level
+	current.Spec.Size = 10 * uint64(util.Gi)
+	current.Spec.HaLevel = 1
+	current.Locator.VolumeLabels = map[string]string{"app": "web", "old": "x"}
+	reqs, changes, err = desired.UpdateRequests(current)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 3)
+	assert.Len(t, reqs, 2)
+
+	assert.Equal(t, "123", reqs[0].GetVolumeId())
+	assert.Equal(t, uint64(20*util.Gi), reqs[0].GetSpec().GetSize())
+	// Labels not in the manifest are kept
+	assert.Equal(t, map[string]string{"app": "db"}, reqs[0].GetLabels())
+	assert.Nil(t, reqs[0].GetSpec().GetHaLevelOpt())
+
+	// HA level is updated on its own
+	assert.Equal(t, int64(2), reqs[1].GetSpec().GetHaLevel())
+	assert.Nil(t, reqs[1].GetSpec().GetSizeOpt())
+
+	// Volumes cannot be shrunk
+	current.Spec.Size = 30 * uint64(util.Gi)
+	_, _, err = desired.UpdateRequests(current)
+	assert.Error(t, err)
+
+	// Fields not in the manifest are not managed
+	current.Spec.Size = 20 * uint64(util.Gi)
+	reqs, changes, err = (&ManifestVolume{Name: "db-data"}).UpdateRequests(current)
+	assert.NoError(t, err)
+	assert.Empty(t, reqs)
+	assert.Empty(t, changes)
+}
+
+func TestSnapshotScheduleSummary(t *testing.T) {
+	a, err := (&SnapshotScheduleOpts{Daily: []string{"02:00,7"}, Weekly: []string{"monday@01:00,2"}}).ScheduleString()
+	assert.NoError(t, err)
+	b, err := (&SnapshotScheduleOpts{Weekly: []string{"monday@01:00,2"}, Daily: []string{"02:00,7"}}).ScheduleString()
+	assert.NoError(t, err)
+	assert.Equal(t, SnapshotScheduleSummary(a), SnapshotScheduleSummary(b))
+	assert.Empty(t, SnapshotScheduleSummary(""))
+
+	_, err = (&SnapshotScheduleOpts{Daily: []string{"25:00"}}).ScheduleString()
+	assert.Error(t, err)
+}
+
+func TestNewManifestVolumeRoundTrip(t *testing.T) {
+	m, err := ParseVolumeManifest([]byte(testManifest))
+	assert.NoError(t, err)
+
+	for _, desired := range m.Volumes {
+		req, err := desired.CreateRequest()
+		assert.NoError(t, err)
+		vol := &api.Volume{
+			Id:     "123",
+			Status: api.VolumeStatus_VOLUME_STATUS_UP,
+			Spec:   req.GetSpec(),
+			Locator: &api.VolumeLocator{
+				Name:         desired.Name,
+				VolumeLabels: req.GetLabels(),
+			},
+		}
+
+		exported, err := NewManifestVolume(vol)
+		assert.NoError(t, err)
+		assert.Equal(t, desired.Name, exported.Name)
+
+		// The exported manifest creates the same volume
+		exportedReq, err := exported.CreateRequest()
+		assert.NoError(t, err)
+		assert.Equal(t, req.GetLabels(), exportedReq.GetLabels())
+		assert.Equal(t, req.GetSpec().GetSize(), exportedReq.GetSpec().GetSize())
+		assert.Equal(t, req.GetSpec().GetHaLevel(), exportedReq.GetSpec().GetHaLevel())
+		assert.Equal(t, req.GetSpec().GetIoProfile(), exportedReq.GetSpec().GetIoProfile())
+		assert.Equal(t, req.GetSpec().GetSharedv4(), exportedReq.GetSpec().GetSharedv4())
+		assert.Equal(t, req.GetSpec().GetOwnership(), exportedReq.GetSpec().GetOwnership())
+		assert.Equal(t,
+			SnapshotScheduleSummary(req.GetSpec().GetSnapshotSchedule()),
+			SnapshotScheduleSummary(exportedReq.GetSpec().GetSnapshotSchedule()))
+
+		// And it has no drift from the volume
+		reqs, changes, err := exported.UpdateRequests(vol)
+		assert.NoError(t, err)
+		assert.Empty(t, reqs)
+		assert.Empty(t, changes)
+	}
+
+	// Defaults are omitted
+	exported, err := NewManifestVolume(&api.Volume{
+		Locator: &api.VolumeLocator{Name: "plain"},
+		Spec: &api.VolumeSpec{
+			Size:      uint64(util.Gi),
+			HaLevel:   1,
+			Format:    api.FSType_FS_TYPE_EXT4,
+			IoProfile: api.IoProfile_IO_PROFILE_SEQUENTIAL,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &ManifestVolume{Name: "plain", Size: 1}, exported)
+}
+
+func TestNewSnapshotScheduleOpts(t *testing.T) {
+	opts := &SnapshotScheduleOpts{
+		Periodic: "60,5",
+		Daily:    []string{"02:30,7"},
+		Weekly:   []string{"monday@01:00,2"},
+		Monthly:  []string{"15@03:00"},
+		Policy:   "gold",
+	}
+	schedule, err := opts.ScheduleString()
+	assert.NoError(t, err)
+
+	parsed, err := NewSnapshotScheduleOpts(schedule)
+	assert.NoError(t, err)
+	assert.Equal(t, opts, parsed)
+}
+
+func TestApplyVolumeSpecUpdate(t *testing.T) {
+	current := &api.VolumeSpec{
+		HaLevel:    1,
+		Size:       uint64(util.Gi),
+		IoStrategy: &api.IoStrategy{AsyncIo: true},
+		Ownership: &api.Ownership{
+			Owner: "me",
+			Acls: &api.Ownership_AccessControl{
+				Groups: map[string]api.Ownership_AccessType{"g1": api.Ownership_Read},
+			},
+		},
+	}
+	updated := ApplyVolumeSpecUpdate(current, &api.VolumeSpecUpdate{
+		HaLevelOpt: &api.VolumeSpecUpdate_HaLevel{HaLevel: 2},
+		ReplicaSet: &api.ReplicaSet{Nodes: []string{"n1", "n2"}},
+		Ownership: &api.Ownership{
+			Acls: &api.Ownership_AccessControl{
+				Groups: map[string]api.Ownership_AccessType{"g1": api.Ownership_Admin},
+			},
+		},
+	})
+
+	// The current spec is not modified
+	assert.Equal(t, int64(1), current.GetHaLevel())
+	assert.Equal(t, int64(2), updated.GetHaLevel())
+	assert.Equal(t, "me", updated.GetOwnership().GetOwner())
+
+	changed := make(map[string]util.FieldChange)
+	for _, change := range VolumeSpecChanges(current, updated) {
+		if change.Changed() {
+			changed[change.Field] = change
+		}
+	}
+	assert.Len(t, changed, 3)
+	assert.Equal(t, "2", changed["replicas"].Desired)
+	assert.Equal(t, "", changed["nodes"].Current)
+	assert.Equal(t, "n1,n2", changed["nodes"].Desired)
+	assert.Equal(t, "g1:r", changed["groups"].Current)
+	assert.Equal(t, "g1:a", changed["groups"].Desired)
+}
+
+func TestManifestVolumeSpecRoundTrip(t *testing.T) {
+	schedule, err := (&SnapshotScheduleOpts{
+		Periodic: "60,5",
+		Daily:    []string{"02:30,7"},
+		Policy:   "gold",
+	}).ScheduleString()
+	assert.NoError(t, err)
+
+	spec := &api.VolumeSpec{
+		Size:             20 * uint64(util.Gi),
+		HaLevel:          2,
+		Format:           api.FSType_FS_TYPE_NONE,
+		IoProfile:        api.IoProfile_IO_PROFILE_DB,
+		AggregationLevel: 2,
+		QueueDepth:       64,
+		Scale:            3,
+		IoStrategy:       &api.IoStrategy{EarlyAck: true, AsyncIo: true},
+		Encrypted:        true,
+		ReplicaSet:       &api.ReplicaSet{Nodes: []string{"node1", "node2"}},
+		Group:            &api.Group{Id: "group1"},
+		GroupEnforced:    true,
+		Journal:          true,
+		Nodiscard:        true,
+		Sharedv4:         true,
+		Sticky:           true,
+		SnapshotSchedule: schedule,
+		Ownership: &api.Ownership{
+			Acls: &api.Ownership_AccessControl{
+				Collaborators: map[string]api.Ownership_AccessType{"user1": api.Ownership_Write},
+				Groups:        map[string]api.Ownership_AccessType{"group1": api.Ownership_Admin},
+			},
+		},
+	}
+
+	// Legacy shared volumes keep their sharing model
+	legacy := *spec
+	legacy.Sharedv4 = false
+	legacy.Shared = true
+
+	for _, spec := range []*api.VolumeSpec{spec, &legacy} {
+		vol := &api.Volume{
+			Id:   "123",
+			Spec: spec,
+			Locator: &api.VolumeLocator{
+				Name:         "db-data",
+				VolumeLabels: map[string]string{"app": "db"},
+			},
+		}
+
+		exported, err := NewManifestVolume(vol)
+		assert.NoError(t, err)
+		assert.NotNil(t, exported.Shared)
+
+		req, err := exported.CreateRequest()
+		assert.NoError(t, err)
+		assert.Equal(t, "db-data", req.GetName())
+		assert.Equal(t, map[string]string{"app": "db"}, req.GetLabels())
+		assert.Equal(t, spec, req.GetSpec())
+
+		reqs, changes, err := exported.UpdateRequests(vol)
+		assert.NoError(t, err)
+		assert.Empty(t, reqs)
+		assert.Empty(t, changes)
+	}
+}
+
+func TestManifestVolumeUpdateRequestsSpec(t *testing.T) {
+	current := &api.Volume{
+		Id: "123",
+		Spec: &api.VolumeSpec{
+			QueueDepth: 128,
+			Scale:      1,
+			IoStrategy: &api.IoStrategy{AsyncIo: true},
+			Shared:     true,
+		},
+	}
+
+	yes := true
+	desired := &ManifestVolume{
+		Name:        "db-data",
+		QueueDepth:  64,
+		Scale:       2,
+		Group:       "group1",
+		EarlyAck:    &yes,
+		Journal:     &yes,
+		Nodiscard:   &yes,
+		Shared:      &yes,
+		SharedModel: SharedModelLegacy,
+	}
+	reqs, changes, err := desired.UpdateRequests(current)
+	assert.NoError(t, err)
+	assert.Len(t, reqs, 1)
+	assert.Len(t, changes, 6)
+
+	update := reqs[0].GetSpec()
+	assert.Equal(t, uint32(64), update.GetQueueDepth())
+	assert.Equal(t, uint32(2), update.GetScale())
+	assert.Equal(t, "group1", update.GetGroup().GetId())
+	assert.Equal(t, &api.IoStrategy{EarlyAck: true, AsyncIo: true}, update.GetIoStrategy())
+	assert.True(t, update.GetJournal())
+	assert.True(t, update.GetNodiscard())
+	assert.Nil(t, update.GetSharedOpt())
+	assert.Nil(t, update.GetSharedv4Opt())
+
+	desired.SharedModel = "nfs"
+	_, _, err = desired.UpdateRequests(current)
+	assert.Error(t, err)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"context"
+	"sort"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"google.golang.org/grpc"
+)
+
+const (
+	OperationResync     = "resync"
+	OperationHaIncrease = "ha increase"
+	OperationReAdd      = "re-add"
+	OperationBackup     = "backup"
+	OperationRestore    = "restore"
+	OperationMigration  = "migration"
+)
+
+// Operation is a long running operation in progress on a volume
+type Operation struct {
+	Kind       string
+	Volume     string
+	Status     string
+	BytesDone  uint64
+	BytesTotal uint64
+}
+
+// ResyncOperations returns the volumes whose replicas are being
+// resynchronized, added by an HA increase, or re-added
+func ResyncOperations(vols []*api.Volume) []*Operation {
+	ops := make([]*Operation, 0)
+	for _, v := range vols {
+		kind := ""
+		for _, rs := range v.GetRuntimeState() {
+			irs := rs.GetRuntimeState()
+			switch {
+			case irs[PXReplRuntimeState] == RuntimeStateResync && len(v.GetAttachedOn()) != 0:
+				kind = OperationResync
+			case len(irs[PXReplNewNodeMid]) != 0:
+				kind = OperationHaIncrease
+			case len(irs[PXReplReAddNodeMid]) != 0:
+				kind = OperationReAdd
+			default:
+				continue
+			}
+			break
+		}
+		if len(kind) != 0 {
+			ops = append(ops, &Operation{
+				Kind:   kind,
+				Volume: v.GetLocator().GetName(),
+				Status: "in progress",
+			})
+		}
+	}
+	return ops
+}
+
+// BackupOperations returns the cloud backups and restores which have not
+// finished. names maps volume ids to names.
+func BackupOperations(statuses map[string]*api.SdkCloudBackupStatus, names map[string]string) []*Operation {
+	ops := make([]*Operation, 0)
+	for _, s := range statuses {
+		switch s.GetStatus() {
+		case api.SdkCloudBackupStatusType_SdkCloudBackupStatusTypeNotStarted,
+			api.SdkCloudBackupStatusType_SdkCloudBackupStatusTypeActive,
+			api.SdkCloudBackupStatusType_SdkCloudBackupStatusTypePaused,
+			api.SdkCloudBackupStatusType_SdkCloudBackupStatusTypeQueued:
+		default:
+			continue
+		}
+
+		kind := OperationBackup
+		if s.GetOptype() == api.SdkCloudBackupOpType_SdkCloudBackupOpTypeRestoreOp {
+			kind = OperationRestore
+		}
+		volume := s.GetSrcVolumeId()
+		if name, ok := names[volume]; ok {
+			volume = name
+		}
+		ops = append(ops, &Operation{
+			Kind:       kind,
+			Volume:     volume,
+			Status:     cloudBackupStatusString(s.GetStatus()),
+			BytesDone:  s.GetBytesDone(),
+			BytesTotal: s.GetBytesTotal(),
+		})
+	}
+	sortOperations(ops)
+	return ops
+}
+
+func cloudBackupStatusString(s api.SdkCloudBackupStatusType) string {
+	switch s {
+	case api.SdkCloudBackupStatusType_SdkCloudBackupStatusTypeNotStarted:
+		return "not started"
+	case api.SdkCloudBackupStatusType_SdkCloudBackupStatusTypeActive:
+		return "in progress"
+	case api.SdkCloudBackupStatusType_SdkCloudBackupStatusTypePaused:
+		return "paused"
+	case api.SdkCloudBackupStatusType_SdkCloudBackupStatusTypeQueued:
+		return "queued"
+	}
+	return "unknown"
+}
+
+// MigrationOperations returns the volume migrations which have not finished
+func MigrationOperations(resp *api.CloudMigrateStatusResponse) []*Operation {
+	ops := make([]*Operation, 0)
+	for _, list := range resp.GetInfo() {
+		for _, info := range list.GetList() {
+			status := ""
+			switch info.GetStatus() {
+			case api.CloudMigrate_Queued:
+				status = "queued"
+			case api.CloudMigrate_Initialized, api.CloudMigrate_InProgress:
+				status = "in progress"
+			default:
+				continue
+			}
+			ops = append(ops, &Operation{
+				Kind:       OperationMigration,
+				Volume:     info.GetLocalVolumeName(),
+				Status:     status,
+				BytesDone:  info.GetBytesDone(),
+				BytesTotal: info.GetBytesTotal(),
+			})
+		}
+	}
+	sortOperations(ops)
+	return ops
+}
+
+func sortOperations(ops []*Operation) {
+	sort.SliceStable(ops, func(i, j int) bool {
+		if ops[i].Kind != ops[j].Kind {
+			return ops[i].Kind < ops[j].Kind
+		}
+		return ops[i].Volume < ops[j].Volume
+	})
+}
+
+// GetBackupOperations returns the cloud backups and restores in progress
+func GetBackupOperations(ctx context.Context, conn *grpc.ClientConn, names map[string]string) ([]*Operation, error) {
+	resp, err := api.NewOpenStorageCloudBackupClient(conn).Status(ctx, &api.SdkCloudBackupStatusRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return BackupOperations(resp.GetStatuses(), names), nil
+}
+
+// GetMigrationOperations returns the volume migrations in progress
+func GetMigrationOperations(ctx context.Context, conn *grpc.ClientConn) ([]*Operation, error) {
+	resp, err := api.NewOpenStorageMigrateClient(conn).Status(ctx, &api.SdkCloudMigrateStatusRequest{
+		Request: &api.CloudMigrateStatusRequest{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return MigrationOperations(resp.GetResult()), nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"testing"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResyncOperations(t *testing.T) {
+	runtimeState := func(state map[string]string) []*api.RuntimeStateMap {
+		return []*api.RuntimeStateMap{{RuntimeState: state}}
+	}
+	vols := []*api.Volume{
+		{
+			Locator:      &api.VolumeLocator{Name: "resync"},
+			AttachedOn:   "node1",
+			RuntimeState: runtimeState(map[string]string{PXReplRuntimeState: RuntimeStateResync}),
+		},
+		{
+			// Only attached volumes are resynced
+			Locator:      &api.VolumeLocator{Name: "detached"},
+			RuntimeState: runtimeState(map[string]string{PXReplRuntimeState: RuntimeStateResync}),
+		},
+		{
+			Locator:      &api.VolumeLocator{Name: "ha"},
+			RuntimeState: runtimeState(map[string]string{PXReplNewNodeMid: "node2"}),
+		},
+		{
+			Locator:      &api.VolumeLocator{Name: "readd"},
+			RuntimeState: runtimeState(map[string]string{PXReplReAddNodeMid: "node3"}),
+		},
+		{
+			Locator: &api.VolumeLocator{Name: "clean"},
+		},
+	}
+	ops := ResyncOperations(vols)
+	assert.Len(t, ops, 3)
+	assert.Equal(t, &Operation{Kind: OperationResync, Volume: "resync", Status: "in progress"}, ops[0])
+	assert.Equal(t, OperationHaIncrease, ops[1].Kind)
+	assert.Equal(t, OperationReAdd, ops[2].Kind)
+}
+
+func TestBackupOperations(t *testing.T) {
+	ops := BackupOperations(map[string]*api.SdkCloudBackupStatus{
+		"b1": {
+			Status:      api.SdkCloudBackupStatusType_SdkCloudBackupStatusTypeActive,
+			Optype:      api.SdkCloudBackupOpType_SdkCloudBackupOpTypeBackupOp,
+			SrcVolumeId: "id1",
+			BytesDone:   10,
+			BytesTotal:  100,
+		},
+		"b2": {
+			Status:      api.SdkCloudBackupStatusType_SdkCloudBackupStatusTypeDone,
+			SrcVolumeId: "id1",
+		},
+		"r1": {
+			Status:      api.SdkCloudBackupStatusType_SdkCloudBackupStatusTypeQueued,
+			Optype:      api.SdkCloudBackupOpType_SdkCloudBackupOpTypeRestoreOp,
+			SrcVolumeId: "unknown",
+		},
+	}, map[string]string{"id1": "vol1"})
+	assert.Equal(t, []*Operation{
+		{Kind: OperationBackup, Volume: "vol1", Status: "in progress", BytesDone: 10, BytesTotal: 100},
+		{Kind: OperationRestore, Volume: "unknown", Status: "queued"},
+	}, ops)
+}
+
+func TestMigrationOperations(t *testing.T) {
+	ops := MigrationOperations(&api.CloudMigrateStatusResponse{
+		Info: map[string]*api.CloudMigrateInfoList{
+			"cluster": {List: []*api.CloudMigrateInfo{
+				{LocalVolumeName: "b", Status: api.CloudMigrate_InProgress, BytesDone: 5},
+				{LocalVolumeName: "a", Status: api.CloudMigrate_Queued},
+				{LocalVolumeName: "c", Status: api.CloudMigrate_Complete},
+			}},
+		},
+	})
+	assert.Equal(t, []*Operation{
+		{Kind: OperationMigration, Volume: "a", Status: "queued"},
+		{Kind: OperationMigration, Volume: "b", Status: "in progress", BytesDone: 5},
+	}, ops)
+
+	assert.Len(t, MigrationOperations(nil), 0)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	prototime "github.com/portworx/pxc/pkg/openstorage/proto/time"
+	"github.com/portworx/pxc/pkg/util"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	OrphanKindVolume   = "volume"
+	OrphanKindSnapshot = "snapshot"
+	OrphanKindPvc      = "pvc"
+
+	// portworxCsiDriver is the name of the Portworx CSI driver
+	portworxCsiDriver = "pxd.portworx.com"
+)
+
+// Orphan is a volume, snapshot or PVC which may no longer be needed
+type Orphan struct {
+	Kind      string   `json:"kind" yaml:"kind"`
+	Name      string   `json:"name" yaml:"name"`
+	Namespace string   `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	VolumeId  string   `json:"volumeId,omitempty" yaml:"volumeId,omitempty"`
+	Reasons   []string `json:"reasons" yaml:"reasons"`
+}
+
+// OrphanSpec has the Kubernetes objects and the settings used to find orphans
+type OrphanSpec struct {
+	// CheckPvcs is false when the PVCs and PVs are not available
+	CheckPvcs bool
+	Pvcs      []v1.PersistentVolumeClaim
+	Pvs       []v1.PersistentVolume
+	// StaleAfter is the time a volume must be detached to be reported.
+	// Zero disables the check.
+	StaleAfter time.Duration
+	Now        time.Time
+}
+
+// pvVolumeId returns the Portworx volume of the PV, if any
+func pvVolumeId(pv *v1.PersistentVolume) (string, bool) {
+	if pv.Spec.PortworxVolume != nil {
+		return pv.Spec.PortworxVolume.VolumeID, true
+	}
+	if pv.Spec.CSI != nil && pv.Spec.CSI.Driver == portworxCsiDriver {
+		return pv.Spec.CSI.VolumeHandle, true
+	}
+	return "", false
+}
+
+// FindOrphans returns the volumes not used by a PVC, the volumes detached
+// for longer than spec.StaleAfter, the snapshots whose parent volume was
+// deleted and the PVCs whose Portworx volume no longer exists
+func FindOrphans(vols []*api.Volume, spec *OrphanSpec) []*Orphan {
+	volsByKey := make(map[string]*api.Volume, 2*len(vols))
+	for _, v := range vols {
+		volsByKey[v.GetId()] = v
+		volsByKey[v.GetLocator().GetName()] = v
+	}
+
+	orphans := make([]*Orphan, 0)
+	used := make(map[string]bool)
+	if spec.CheckPvcs {
+		pvcs := make(map[string]bool, len(spec.Pvcs))
+		for _, pvc := range spec.Pvcs {
+			pvcs[pvc.GetNamespace()+"/"+pvc.GetName()] = true
+		}
+
+		pvs := make(map[string]*v1.PersistentVolume, len(spec.Pvs))
+		for i := range spec.Pvs {
+			pv := &spec.Pvs[i]
+			pvs[pv.GetName()] = pv
+			id, ok := pvVolumeId(pv)
+			if !ok {
+				continue
+			}
+			claim := pv.Spec.ClaimRef
+			if v, ok := volsByKey[id]; ok && claim != nil && pvcs[claim.Namespace+"/"+claim.Name] {
+				used[v.GetId()] = true
+			}
+		}
+
+		for _, v := range vols {
+			// Fallback to match by labels, as done by PxPvc
+			labels := v.GetLocator().GetVolumeLabels()
+			if pvcs[labels["namespace"]+"/"+labels["pvc"]] {
+				used[v.GetId()] = true
+			}
+		}
+
+		for _, pvc := range spec.Pvcs {
+			pv, ok := pvs[pvc.Spec.VolumeName]
+			if !ok {
+				continue
+			}
+			if id, ok := pvVolumeId(pv); ok {
+				if _, ok := volsByKey[id]; !ok {
+					orphans = append(orphans, &Orphan{
+						Kind:      OrphanKindPvc,
+						Name:      pvc.GetName(),
+						Namespace: pvc.GetNamespace(),
+						VolumeId:  id,
+						Reasons:   []string{fmt.Sprintf("Volume %s not found", id)},
+					})
+				}
+			}
+		}
+	}
+
+	for _, v := range vols {
+		if isSnapshot(v) {
+			if _, ok := volsByKey[v.GetSource().GetParent()]; !ok {
+				orphans = append(orphans, &Orphan{
+					Kind:     OrphanKindSnapshot,
+					Name:     v.GetLocator().GetName(),
+					VolumeId: v.GetId(),
+					Reasons:  []string{fmt.Sprintf("Parent volume %s not found", v.GetSource().GetParent())},
+				})
+			}
+			continue
+		}
+
+		reasons := make([]string, 0)
+		if spec.CheckPvcs && !used[v.GetId()] {
+			reasons = append(reasons, "Not used by a PVC")
+		}
+		if reason, stale := staleReason(v, spec); stale {
+			reasons = append(reasons, reason)
+		}
+		if len(reasons) != 0 {
+			orphans = append(orphans, &Orphan{
+				Kind:     OrphanKindVolume,
+				Name:     v.GetLocator().GetName(),
+				VolumeId: v.GetId(),
+				Reasons:  reasons,
+			})
+		}
+	}
+
+	sort.SliceStable(orphans, func(i, j int) bool {
+		a, b := orphans[i], orphans[j]
+		if a.Kind != b.Kind {
+			return a.Kind > b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return orphans
+}
+
+// staleReason returns the reason when the volume has been detached for
+// longer than spec.StaleAfter
+func staleReason(v *api.Volume, spec *OrphanSpec) (string, bool) {
+	if spec.StaleAfter == 0 || len(v.GetAttachedOn()) != 0 {
+		return "", false
+	}
+
+	if v.GetDetachTime() != nil {
+		since := prototime.TimestampToTime(v.GetDetachTime())
+		if spec.Now.Sub(since) > spec.StaleAfter {
+			return "Detached since " + since.Format(util.TimeFormat), true
+		}
+		return "", false
+	}
+
+	created := prototime.TimestampToTime(v.GetCtime())
+	if v.GetAttachTime() == nil && v.GetCtime() != nil && spec.Now.Sub(created) > spec.StaleAfter {
+		return "Never attached since created on " + created.Format(util.TimeFormat), true
+	}
+	return "", false
+}
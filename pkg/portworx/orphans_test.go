@@ -0,0 +1,112 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	prototime "github.com/portworx/pxc/pkg/openstorage/proto/time"
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func orphanPvc(namespace, name, pvName string) v1.PersistentVolumeClaim {
+	return v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       v1.PersistentVolumeClaimSpec{VolumeName: pvName},
+	}
+}
+
+func orphanPv(name, volumeId, claimNamespace, claimName string) v1.PersistentVolume {
+	return v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1.PersistentVolumeSpec{
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				CSI: &v1.CSIPersistentVolumeSource{Driver: portworxCsiDriver, VolumeHandle: volumeId},
+			},
+			ClaimRef: &v1.ObjectReference{Namespace: claimNamespace, Name: claimName},
+		},
+	}
+}
+
+func TestFindOrphans(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	longAgo := prototime.TimeToTimestamp(now.Add(-100 * 24 * time.Hour))
+	recently := prototime.TimeToTimestamp(now.Add(-time.Hour))
+
+	vols := []*api.Volume{
+		// Used by a PVC through a PV
+		{Id: "1", Locator: &api.VolumeLocator{Name: "used"}, AttachedOn: "node1"},
+		// Used by a PVC through the labels
+		{Id: "2", Locator: &api.VolumeLocator{Name: "labeled", VolumeLabels: map[string]string{
+			"namespace": "dev", "pvc": "labeled"}}, DetachTime: recently},
+		// Not used and detached a long time ago
+		{Id: "3", Locator: &api.VolumeLocator{Name: "stale"}, DetachTime: longAgo},
+		// Not used, but never attached and recently created
+		{Id: "4", Locator: &api.VolumeLocator{Name: "new"}, Ctime: recently},
+		// Snapshot of a volume which exists
+		{Id: "5", Locator: &api.VolumeLocator{Name: "snap"}, Readonly: true, Source: &api.Source{Parent: "1"}},
+		// Snapshot of a deleted volume
+		{Id: "6", Locator: &api.VolumeLocator{Name: "lost-snap"}, Readonly: true, Source: &api.Source{Parent: "99"}},
+		// Used by a PVC and never attached since created long ago
+		{Id: "7", Locator: &api.VolumeLocator{Name: "unattached"}, Ctime: longAgo},
+	}
+	spec := &OrphanSpec{
+		CheckPvcs: true,
+		Pvcs: []v1.PersistentVolumeClaim{
+			orphanPvc("prod", "used", "pv-used"),
+			orphanPvc("dev", "labeled", ""),
+			orphanPvc("prod", "gone", "pv-gone"),
+			orphanPvc("prod", "unattached", "pv-unattached"),
+		},
+		Pvs: []v1.PersistentVolume{
+			orphanPv("pv-used", "1", "prod", "used"),
+			orphanPv("pv-gone", "98", "prod", "gone"),
+			orphanPv("pv-unattached", "unattached", "prod", "unattached"),
+			// PV of a PVC which was deleted
+			orphanPv("pv-stale", "3", "prod", "deleted"),
+		},
+		StaleAfter: 90 * 24 * time.Hour,
+		Now:        now,
+	}
+
+	orphans := FindOrphans(vols, spec)
+	assert.Len(t, orphans, 5)
+	assert.Equal(t, &Orphan{Kind: OrphanKindVolume, Name: "new", VolumeId: "4",
+		Reasons: []string{"Not used by a PVC"}}, orphans[0])
+	assert.Equal(t, &Orphan{Kind: OrphanKindVolume, Name: "stale", VolumeId: "3",
+		Reasons: []string{"Not used by a PVC", "Detached since Feb 22 00:00:00 UTC 2020"}}, orphans[1])
+	assert.Equal(t, &Orphan{Kind: OrphanKindVolume, Name: "unattached", VolumeId: "7",
+		Reasons: []string{"Never attached since created on Feb 22 00:00:00 UTC 2020"}}, orphans[2])
+	assert.Equal(t, &Orphan{Kind: OrphanKindSnapshot, Name: "lost-snap", VolumeId: "6",
+		Reasons: []string{"Parent volume 99 not found"}}, orphans[3])
+	assert.Equal(t, &Orphan{Kind: OrphanKindPvc, Name: "gone", Namespace: "prod", VolumeId: "98",
+		Reasons: []string{"Volume 98 not found"}}, orphans[4])
+
+	// Without Kubernetes only the stale volumes and snapshots are reported
+	orphans = FindOrphans(vols, &OrphanSpec{StaleAfter: spec.StaleAfter, Now: now})
+	assert.Len(t, orphans, 3)
+	assert.Equal(t, []string{"Detached since Feb 22 00:00:00 UTC 2020"}, orphans[0].Reasons)
+
+	// Stale volumes can be ignored
+	orphans = FindOrphans(vols, &OrphanSpec{Now: now})
+	assert.Len(t, orphans, 1)
+	assert.Equal(t, OrphanKindSnapshot, orphans[0].Kind)
+}
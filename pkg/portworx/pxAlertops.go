@@ -17,15 +17,18 @@ limitations under the License.
 package portworx
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"sort"
 	"time"
 
+	"github.com/portworx/pxc/pkg/config"
 	"github.com/portworx/pxc/pkg/util"
 
 	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
 	prototime "github.com/portworx/pxc/pkg/openstorage/proto/time"
+	"google.golang.org/grpc"
 )
 
 type PxAlertOps interface {
@@ -44,7 +47,9 @@ type CliAlertInputs struct {
 	ResourceId string
 }
 
-type pxAlertOps struct{}
+type pxAlertOps struct {
+	ctx context.Context
+}
 
 type AlertResp struct {
 	AlertResp     []*api.Alert
@@ -66,18 +71,84 @@ func (g alertsList) Len() int           { return len(g) }
 func (g alertsList) Less(i, j int) bool { return g[i].Timestamp.Seconds < g[j].Timestamp.Seconds }
 func (g alertsList) Swap(i, j int)      { g[i], g[j] = g[j], g[i] }
 
-func NewPxAlertOps() PxAlertOps {
-	return &pxAlertOps{}
+// alertsClient sends the alert requests to Portworx
+type alertsClient interface {
+	EnumerateWithFilters(req *api.SdkAlertsEnumerateWithFiltersRequest) ([]*api.Alert, error)
+	Delete(req *api.SdkAlertsDeleteRequest) error
+	Close()
+}
+
+// grpcAlertsClient sends the alert requests to the Portworx gRPC SDK server
+type grpcAlertsClient struct {
+	ctx  context.Context
+	conn *grpc.ClientConn
+}
+
+// NewPxAlertOps returns the alert operations on the current cluster of the
+// configuration in ctx
+func NewPxAlertOps(ctx context.Context) PxAlertOps {
+	return &pxAlertOps{ctx: ctx}
+}
+
+// newAlertsClient returns a client using the transport configured for the current cluster
+func newAlertsClient(ctx context.Context) (alertsClient, error) {
+	if config.CMFromContext(ctx).GetCurrentCluster().UsesRestTransport() {
+		client, err := newRestClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &restAlertsClient{client: client}, nil
+	}
+
+	ctx, conn, err := PxConnectDefault(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcAlertsClient{ctx: ctx, conn: conn}, nil
+}
+
+func (c *grpcAlertsClient) EnumerateWithFilters(
+	req *api.SdkAlertsEnumerateWithFiltersRequest,
+) ([]*api.Alert, error) {
+	client := api.NewOpenStorageAlertsClient(c.conn)
+	resp, err := client.EnumerateWithFilters(c.ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []*api.Alert
+	for {
+		res, err := resp.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if res.GetAlerts() != nil {
+			alerts = append(alerts, res.Alerts...)
+		}
+	}
+	return alerts, nil
+}
+
+func (c *grpcAlertsClient) Delete(req *api.SdkAlertsDeleteRequest) error {
+	client := api.NewOpenStorageAlertsClient(c.conn)
+	_, err := client.Delete(c.ctx, req)
+	return err
+}
+
+func (c *grpcAlertsClient) Close() {
+	c.conn.Close()
 }
 
 func (p *pxAlertOps) GetPxAlerts(cliAlertInputs CliAlertInputs) (AlertResp, error) {
 	alertResp := AlertResp{}
 
-	ctx, conn, err := PxConnectDefault()
+	client, err := newAlertsClient(p.ctx)
 	if err != nil {
 		return alertResp, err
 	}
-	defer conn.Close()
+	defer client.Close()
 	getAlertsGetReq := getAlertsOpts{
 		req: &api.SdkAlertsEnumerateWithFiltersRequest{},
 	}
@@ -193,21 +264,11 @@ func (p *pxAlertOps) GetPxAlerts(cliAlertInputs CliAlertInputs) (AlertResp, erro
 		}
 
 		// Send request
-		client := api.NewOpenStorageAlertsClient(conn)
-		resp, err := client.EnumerateWithFilters(ctx, getAlertsGetReq.req)
+		alerts, err := client.EnumerateWithFilters(getAlertsGetReq.req)
 		if err != nil {
 			return alertResp, fmt.Errorf("Failed to fetch alerts.")
 		}
-
-		for {
-			res, err := resp.Recv()
-			if err == io.EOF {
-				break
-			}
-			if res.GetAlerts() != nil {
-				myAlerts = append(myAlerts, res.Alerts...)
-			}
-		}
+		myAlerts = append(myAlerts, alerts...)
 	}
 	sort.Sort(alertsList(myAlerts))
 	alertResp.AlertResp = myAlerts
@@ -217,13 +278,11 @@ func (p *pxAlertOps) GetPxAlerts(cliAlertInputs CliAlertInputs) (AlertResp, erro
 func (p *pxAlertOps) DeletePxAlerts(alert string) error {
 	alertResp := AlertResp{}
 
-	ctx, conn, err := PxConnectDefault()
-	_ = ctx
+	client, err := newAlertsClient(p.ctx)
 	if err != nil {
 		return err
 	}
-
-	defer conn.Close()
+	defer client.Close()
 	delAlertsGetReq := delAlertsOpts{
 		req: &api.SdkAlertsDeleteRequest{},
 	}
@@ -250,8 +309,7 @@ func (p *pxAlertOps) DeletePxAlerts(alert string) error {
 			},
 		}
 		// Send request
-		client := api.NewOpenStorageAlertsClient(conn)
-		_, err = client.Delete(ctx, delAlertsGetReq.req)
+		err = client.Delete(delAlertsGetReq.req)
 		if err != nil {
 			return fmt.Errorf("Failed to delete alerts")
 		}
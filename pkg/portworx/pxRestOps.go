@@ -0,0 +1,176 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/util"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+)
+
+// pxRestOps implements PxOps using the Portworx SDK REST gateway
+type pxRestOps struct {
+	ctx    context.Context
+	client *restClient
+}
+
+func newPxRestOps(ctx context.Context) (PxOps, error) {
+	client, err := newRestClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &pxRestOps{
+		ctx:    ctx,
+		client: client,
+	}, nil
+}
+
+func (p *pxRestOps) Close() {
+	p.client.Close()
+}
+
+func (p *pxRestOps) GetCtx() context.Context {
+	return p.ctx
+}
+
+func (p *pxRestOps) GetConn() *grpc.ClientConn {
+	return nil
+}
+
+func (p *pxRestOps) GetVolumesBySpec(
+	vs *VolumeSpec,
+) ([]*api.SdkVolumeInspectResponse, error) {
+	req := &api.SdkVolumeInspectWithFiltersRequest{
+		Labels: vs.Labels,
+	}
+
+	if vs.Owner != "" {
+		req.Ownership = &api.Ownership{
+			Owner: vs.Owner,
+		}
+	}
+
+	volsInfo := &api.SdkVolumeInspectWithFiltersResponse{}
+	err := p.client.send(http.MethodPost, "/v1/volumes/inspectwithfilters", req, volsInfo)
+	if err != nil {
+		return nil, util.PxErrorMessage(err, "Failed to get volumes")
+	}
+	return volsInfo.GetVolumes(), nil
+}
+
+func (p *pxRestOps) GetVolumeById(id string) (*api.SdkVolumeInspectResponse, error) {
+	resp := &api.SdkVolumeInspectResponse{}
+	if err := p.client.get(restPath("/v1/volumes/inspect", id), resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (p *pxRestOps) GetStats(v *api.Volume, notCumulative bool) (*api.Stats, error) {
+	volStats := &api.SdkVolumeStatsResponse{}
+	path := fmt.Sprintf("%s?not_cumulative=%t", restPath("/v1/volumes/stats", v.GetId()), notCumulative)
+	if err := p.client.get(path, volStats); err != nil {
+		return &api.Stats{}, util.PxError(err)
+	}
+	return volStats.GetStats(), nil
+}
+
+func (p *pxRestOps) EnumerateNodes() ([]string, error) {
+	nodesInfo := &api.SdkNodeEnumerateResponse{}
+	if err := p.client.get("/v1/nodes", nodesInfo); err != nil {
+		return make([]string, 0), util.PxError(err)
+	}
+	return nodesInfo.GetNodeIds(), nil
+}
+
+func (p *pxRestOps) GetNode(nodeId string) (*api.StorageNode, error) {
+	nodeInfo := &api.SdkNodeInspectResponse{}
+	if err := p.client.get(restPath("/v1/nodes/inspect", nodeId), nodeInfo); err != nil {
+		return nil, util.PxError(err)
+	}
+	return nodeInfo.GetNode(), nil
+}
+
+// restAuthOps implements AuthOps using the Portworx SDK REST gateway
+type restAuthOps struct {
+	ctx context.Context
+}
+
+func (p *restAuthOps) GetRole(name string) (*api.SdkRole, error) {
+	client, err := newRestClient(p.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	resp := &api.SdkRoleInspectResponse{}
+	if err := client.get(restPath("/v1/roles/inspect", name), resp); err != nil {
+		return nil, util.PxErrorMessage(err, "Failed to get role")
+	}
+
+	return resp.GetRole(), nil
+}
+
+func (p *restAuthOps) UpdateRole(r *api.SdkRole) error {
+	client, err := newRestClient(p.ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	err = client.send(http.MethodPut, "/v1/roles", &api.SdkRoleUpdateRequest{
+		Role: r,
+	}, &api.SdkRoleUpdateResponse{})
+	if err != nil {
+		return util.PxErrorMessage(err, "Failed to update role")
+	}
+
+	return nil
+}
+
+// restAlertsClient sends alert requests to the Portworx SDK REST gateway
+type restAlertsClient struct {
+	client *restClient
+}
+
+func (c *restAlertsClient) EnumerateWithFilters(
+	req *api.SdkAlertsEnumerateWithFiltersRequest,
+) ([]*api.Alert, error) {
+	var alerts []*api.Alert
+	err := c.client.stream(http.MethodPost, "/v1/alerts/filters", req,
+		func() proto.Message { return &api.SdkAlertsEnumerateWithFiltersResponse{} },
+		func(m proto.Message) {
+			alerts = append(alerts, m.(*api.SdkAlertsEnumerateWithFiltersResponse).GetAlerts()...)
+		})
+	return alerts, err
+}
+
+func (c *restAlertsClient) Delete(req *api.SdkAlertsDeleteRequest) error {
+	return c.client.send(http.MethodPost, "/v1/alerts", req, &api.SdkAlertsDeleteResponse{})
+}
+
+func (c *restAlertsClient) Close() {
+	c.client.Close()
+}
@@ -0,0 +1,256 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/config"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupRestConfig points the current context to the REST gateway at endpoints
+func setupRestConfig(t *testing.T, endpoints ...string) func() {
+	original := config.CM()
+	config.SetCM(newRestConfig(endpoints...))
+	return func() { config.SetCM(original) }
+}
+
+// newRestConfig returns a configuration with a context using the REST
+// gateway at endpoints
+func newRestConfig(endpoints ...string) *config.ConfigManager {
+	return &config.ConfigManager{
+		Config: &config.Config{
+			Clusters: map[string]*config.Cluster{
+				"c": {
+					Name:      "c",
+					Endpoints: endpoints,
+					Transport: config.TransportRest,
+				},
+			},
+			AuthInfos: map[string]*config.AuthInfo{
+				"u": {Name: "u", Token: "abc"},
+			},
+			Contexts: map[string]*config.Context{
+				"c": {Name: "c", Cluster: "c", AuthInfo: "u"},
+			},
+			CurrentContext: "c",
+		},
+		Flags: &config.ConfigFlags{
+			RequestTimeout: 5 * time.Second,
+			Retries:        1,
+		},
+	}
+}
+
+func writeProto(t *testing.T, w http.ResponseWriter, m proto.Message) {
+	var b bytes.Buffer
+	assert.NoError(t, restMarshaler.Marshal(&b, m))
+	w.Write(b.Bytes())
+}
+
+func newFakeGateway(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/volumes/inspectwithfilters", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "bearer abc", r.Header.Get("Authorization"))
+		req := &api.SdkVolumeInspectWithFiltersRequest{}
+		assert.NoError(t, restUnmarshaler.Unmarshal(r.Body, req))
+		assert.Equal(t, "me", req.GetOwnership().GetOwner())
+		writeProto(t, w, &api.SdkVolumeInspectWithFiltersResponse{
+			Volumes: []*api.SdkVolumeInspectResponse{
+				{Name: "vol1", Volume: &api.Volume{Id: "1"}},
+			},
+		})
+	})
+	mux.HandleFunc("/v1/volumes/inspect/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/v1/volumes/inspect/")
+		if id != "1" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": "not found", "code": 5, "message": "Volume " + id + " not found",
+			})
+			return
+		}
+		writeProto(t, w, &api.SdkVolumeInspectResponse{Name: "vol1", Volume: &api.Volume{Id: "1"}})
+	})
+	mux.HandleFunc("/v1/volumes/stats/1", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("not_cumulative"))
+		writeProto(t, w, &api.SdkVolumeStatsResponse{Stats: &api.Stats{Reads: 10, Writes: 20}})
+	})
+	mux.HandleFunc("/v1/nodes", func(w http.ResponseWriter, r *http.Request) {
+		writeProto(t, w, &api.SdkNodeEnumerateResponse{NodeIds: []string{"n1", "n2"}})
+	})
+	mux.HandleFunc("/v1/nodes/inspect/n1", func(w http.ResponseWriter, r *http.Request) {
+		writeProto(t, w, &api.SdkNodeInspectResponse{Node: &api.StorageNode{Id: "n1", Hostname: "host1"}})
+	})
+	mux.HandleFunc("/v1/roles/inspect/system.guest", func(w http.ResponseWriter, r *http.Request) {
+		writeProto(t, w, &api.SdkRoleInspectResponse{Role: &RoleGuestEnabled})
+	})
+	mux.HandleFunc("/v1/roles", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		req := &api.SdkRoleUpdateRequest{}
+		assert.NoError(t, restUnmarshaler.Unmarshal(r.Body, req))
+		assert.Equal(t, "system.guest", req.GetRole().GetName())
+		w.Write([]byte("{}"))
+	})
+	mux.HandleFunc("/v1/alerts/filters", func(w http.ResponseWriter, r *http.Request) {
+		// Server streaming responses are sent as a sequence of results
+		for _, id := range []string{"a1", "a2"} {
+			var b bytes.Buffer
+			restMarshaler.Marshal(&b, &api.SdkAlertsEnumerateWithFiltersResponse{
+				Alerts: []*api.Alert{{Id: 1, ResourceId: id}},
+			})
+			w.Write([]byte(`{"result":` + b.String() + "}\n"))
+		}
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestPxRestOps(t *testing.T) {
+	server := newFakeGateway(t)
+	defer server.Close()
+	defer setupRestConfig(t, strings.TrimPrefix(server.URL, "http://"))()
+
+	pxops, err := NewPxOps(context.Background())
+	assert.NoError(t, err)
+	defer pxops.Close()
+	assert.Nil(t, pxops.GetConn())
+
+	vols, err := pxops.GetVolumesBySpec(&VolumeSpec{Owner: "me"})
+	assert.NoError(t, err)
+	assert.Len(t, vols, 1)
+	assert.Equal(t, "vol1", vols[0].GetName())
+
+	vol, err := pxops.GetVolumeById("1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", vol.GetVolume().GetId())
+
+	_, err = pxops.GetVolumeById("2")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Volume 2 not found")
+
+	stats, err := pxops.GetStats(vol.GetVolume(), true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), stats.GetReads())
+
+	nodes, err := pxops.EnumerateNodes()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1", "n2"}, nodes)
+
+	node, err := pxops.GetNode("n1")
+	assert.NoError(t, err)
+	assert.Equal(t, "host1", node.GetHostname())
+}
+
+func TestPxRestOpsFailover(t *testing.T) {
+	server := newFakeGateway(t)
+	defer server.Close()
+
+	// The first endpoint is not listening
+	dead := httptest.NewServer(http.NotFoundHandler())
+	deadEndpoint := strings.TrimPrefix(dead.URL, "http://")
+	dead.Close()
+	defer setupRestConfig(t, deadEndpoint, strings.TrimPrefix(server.URL, "http://"))()
+
+	pxops, err := NewPxOps(context.Background())
+	assert.NoError(t, err)
+	defer pxops.Close()
+
+	nodes, err := pxops.EnumerateNodes()
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 2)
+}
+
+func TestPxRestOpsConcurrentFailover(t *testing.T) {
+	server := newFakeGateway(t)
+	defer server.Close()
+
+	dead := httptest.NewServer(http.NotFoundHandler())
+	deadEndpoint := strings.TrimPrefix(dead.URL, "http://")
+	dead.Close()
+	defer setupRestConfig(t, deadEndpoint, strings.TrimPrefix(server.URL, "http://"))()
+
+	pxops, err := NewPxOps(context.Background())
+	assert.NoError(t, err)
+	defer pxops.Close()
+
+	// The connection is shared by goroutines like the workers of the
+	// exporter or the panels of the dashboard
+	errs := util.ForEach(32, 8, true, func(i int) error {
+		_, err := pxops.EnumerateNodes()
+		return err
+	})
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+}
+
+func TestRestAuthAndAlertOps(t *testing.T) {
+	server := newFakeGateway(t)
+	defer server.Close()
+	defer setupRestConfig(t, strings.TrimPrefix(server.URL, "http://"))()
+
+	authOps := NewAuthOps(context.Background())
+	role, err := authOps.GetRole("system.guest")
+	assert.NoError(t, err)
+	assert.Equal(t, "system.guest", role.GetName())
+	assert.NoError(t, authOps.UpdateRole(&RoleGuestDisabled))
+
+	client, err := newAlertsClient(context.Background())
+	assert.NoError(t, err)
+	defer client.Close()
+	alerts, err := client.EnumerateWithFilters(&api.SdkAlertsEnumerateWithFiltersRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, alerts, 2)
+	assert.Equal(t, "a2", alerts[1].GetResourceId())
+}
+
+func TestPxConnectDefaultWithRestTransport(t *testing.T) {
+	defer setupRestConfig(t, "127.0.0.1:9021")()
+
+	_, _, err := PxConnectDefault(context.Background())
+	assert.Equal(t, ErrNotSupportedByTransport, err)
+}
+
+func TestNewPxOpsUsesConfigOfContext(t *testing.T) {
+	server := newFakeGateway(t)
+	defer server.Close()
+
+	// The global configuration points to an endpoint which is not used
+	defer setupRestConfig(t, "127.0.0.1:1")()
+	ctx := config.WithConfigManager(context.Background(),
+		newRestConfig(strings.TrimPrefix(server.URL, "http://")))
+
+	pxops, err := NewPxOps(ctx)
+	assert.NoError(t, err)
+	defer pxops.Close()
+	assert.Equal(t, ctx, pxops.GetCtx())
+
+	node, err := pxops.GetNode("n1")
+	assert.NoError(t, err)
+	assert.Equal(t, "host1", node.GetHostname())
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 
 	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/config"
 	"github.com/portworx/pxc/pkg/util"
 
 	"google.golang.org/grpc"
@@ -39,7 +40,8 @@ type PxOps interface {
 	GetNode(id string) (*api.StorageNode, error)
 	// GetCtx returns the context
 	GetCtx() context.Context
-	// GetConn returns the grpc client connection
+	// GetConn returns the grpc client connection. It is nil when
+	// the REST transport is used.
 	GetConn() *grpc.ClientConn
 }
 
@@ -50,8 +52,14 @@ type pxOps struct {
 	conn *grpc.ClientConn
 }
 
-func NewPxOps() (PxOps, error) {
-	ctx, conn, err := PxConnectDefault()
+// NewPxOps returns a connection to Portworx using the transport configured
+// for the current cluster of the configuration in ctx
+func NewPxOps(ctx context.Context) (PxOps, error) {
+	if config.CMFromContext(ctx).GetCurrentCluster().UsesRestTransport() {
+		return newPxRestOps(ctx)
+	}
+
+	ctx, conn, err := PxConnectDefault(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -61,6 +69,15 @@ func NewPxOps() (PxOps, error) {
 	}, nil
 }
 
+// NewPxOpsWithConn returns a PxOps which uses the gRPC connection provided.
+// Closing it closes the connection.
+func NewPxOpsWithConn(ctx context.Context, conn *grpc.ClientConn) PxOps {
+	return &pxOps{
+		ctx:  ctx,
+		conn: conn,
+	}
+}
+
 func (p *pxOps) Close() {
 	p.conn.Close()
 }
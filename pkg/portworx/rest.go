@@ -0,0 +1,314 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/portworx/pxc/pkg/config"
+	pxgrpc "github.com/portworx/pxc/pkg/grpc"
+	"github.com/portworx/pxc/pkg/kubernetes"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// restClient sends SDK requests to the Portworx REST gateway
+type restClient struct {
+	client    *http.Client
+	endpoints []string
+	scheme    string
+	token     string
+	retries   uint
+
+	// current is the index of the endpoint last known to work. The client
+	// is shared by goroutines, so it is guarded by lock.
+	lock    sync.Mutex
+	current int
+}
+
+// restError is the error returned by the REST gateway
+type restError struct {
+	Error   string `json:"error"`
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+}
+
+var (
+	restMarshaler   = &jsonpb.Marshaler{OrigName: true}
+	restUnmarshaler = &jsonpb.Unmarshaler{AllowUnknownFields: true}
+)
+
+// newRestClient returns a client to the REST gateway of the current context
+// of the configuration in ctx
+func newRestClient(ctx context.Context) (*restClient, error) {
+	cm := config.CMFromContext(ctx)
+	if len(cm.GetEndpoints()) == 0 {
+		// Start the tunnel if not up already
+		if err := kubernetes.StartTunnel(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	currentCluster := cm.GetCurrentCluster()
+	transport := &http.Transport{}
+
+	scheme := "http"
+	if currentCluster.UsesTLS() {
+		tlsConfig, err := PxTLSConfig(currentCluster)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+		scheme = "https"
+	}
+
+	dialer, err := pxgrpc.NewProxyDialer(pxgrpc.ProxyOptions{
+		ProxyURL:        currentCluster.ProxyURL,
+		DisableProxyEnv: currentCluster.DisableProxyEnv,
+	})
+	if err != nil {
+		return nil, err
+	}
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dialer(ctx, addr)
+	}
+
+	token, err := pxGetToken(ctx, cm.GetCurrentAuthInfo())
+	if err != nil {
+		return nil, err
+	}
+
+	// Names are resolved by the HTTP client
+	endpoints := cm.GetEndpoints()
+	for i, endpoint := range endpoints {
+		endpoints[i] = strings.TrimPrefix(endpoint, "dns:///")
+	}
+	logrus.Infof("Using REST gateway at endpoint %s", strings.Join(endpoints, ","))
+	return &restClient{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   cm.GetFlags().RequestTimeout,
+		},
+		endpoints: endpoints,
+		scheme:    scheme,
+		token:     token,
+		retries:   cm.GetFlags().Retries,
+	}, nil
+}
+
+// Close releases the idle connections
+func (r *restClient) Close() {
+	r.client.CloseIdleConnections()
+}
+
+// get sends a GET request and decodes the response into resp
+func (r *restClient) get(path string, resp proto.Message) error {
+	body, err := r.do(http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return r.decode(body, resp)
+}
+
+// send sends the request with the method provided and decodes the response into resp
+func (r *restClient) send(method, path string, req, resp proto.Message) error {
+	body, err := r.do(method, path, req)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	if resp == nil {
+		return nil
+	}
+	return r.decode(body, resp)
+}
+
+// stream sends the request to a server streaming API and calls f for each
+// result received
+func (r *restClient) stream(
+	method, path string,
+	req proto.Message,
+	newResp func() proto.Message,
+	f func(proto.Message),
+) error {
+	body, err := r.do(method, path, req)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	decoder := json.NewDecoder(body)
+	for {
+		var msg struct {
+			Result json.RawMessage `json:"result"`
+			Error  *restError      `json:"error"`
+		}
+		if err := decoder.Decode(&msg); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return status.Errorf(codes.Internal, "Failed to decode response: %v", err)
+		}
+		if msg.Error != nil {
+			return msg.Error.toStatus()
+		}
+
+		resp := newResp()
+		if err := restUnmarshaler.Unmarshal(bytes.NewReader(msg.Result), resp); err != nil {
+			return status.Errorf(codes.Internal, "Failed to decode response: %v", err)
+		}
+		f(resp)
+	}
+}
+
+func (r *restClient) decode(body io.Reader, resp proto.Message) error {
+	if err := restUnmarshaler.Unmarshal(body, resp); err != nil && err != io.EOF {
+		return status.Errorf(codes.Internal, "Failed to decode response: %v", err)
+	}
+	return nil
+}
+
+// do sends the request, failing over to the next endpoint when one cannot be
+// reached. Idempotent requests are retried with an exponential backoff when
+// the gateway is unavailable. The caller must close the returned body.
+func (r *restClient) do(method, path string, req proto.Message) (io.ReadCloser, error) {
+	var payload []byte
+	if req != nil {
+		var b bytes.Buffer
+		if err := restMarshaler.Marshal(&b, req); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Failed to encode request: %v", err)
+		}
+		payload = b.Bytes()
+	}
+
+	retries := uint(0)
+	if method == http.MethodGet {
+		retries = r.retries
+	}
+
+	var lastErr error
+	for attempt := uint(0); attempt <= retries; attempt++ {
+		if attempt != 0 {
+			time.Sleep(pxgrpc.RetryBackoffScalar * time.Duration(1<<(attempt-1)))
+		}
+
+		// Try each endpoint starting with the one which last worked
+		current := r.getCurrent()
+		for i := 0; i < len(r.endpoints); i++ {
+			index := (current + i) % len(r.endpoints)
+			resp, err := r.doOnce(r.endpoints[index], method, path, payload)
+			if err != nil {
+				logrus.Infof("Failed to reach %s: %v", r.endpoints[index], err)
+				lastErr = status.Errorf(codes.Unavailable, "Failed to reach %s: %v", r.endpoints[index], err)
+				continue
+			}
+			r.setCurrent(index)
+
+			if resp.StatusCode == http.StatusOK {
+				return resp.Body, nil
+			}
+
+			lastErr = responseToError(resp)
+			resp.Body.Close()
+			if status.Code(lastErr) != codes.Unavailable {
+				return nil, lastErr
+			}
+		}
+	}
+
+	return nil, lastErr
+}
+
+func (r *restClient) getCurrent() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.current
+}
+
+func (r *restClient) setCurrent(index int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.current = index
+}
+
+func (r *restClient) doOnce(endpoint, method, path string, payload []byte) (*http.Response, error) {
+	target := r.scheme + "://" + endpoint + path
+	httpReq, err := http.NewRequest(method, target, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	if len(r.token) != 0 {
+		httpReq.Header.Set("Authorization", "bearer "+r.token)
+	}
+
+	start := time.Now()
+	resp, err := r.client.Do(httpReq)
+	logrus.Debugf("REST %s %s took %v", method, path, time.Since(start))
+	return resp, err
+}
+
+// responseToError returns a gRPC status from the error returned by the gateway
+func responseToError(resp *http.Response) error {
+	data, _ := ioutil.ReadAll(resp.Body)
+	var restErr restError
+	if err := json.Unmarshal(data, &restErr); err != nil || (restErr.Code == 0 && len(restErr.Message) == 0) {
+		code := codes.Unknown
+		switch resp.StatusCode {
+		case http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusGatewayTimeout:
+			code = codes.Unavailable
+		case http.StatusNotFound:
+			code = codes.NotFound
+		case http.StatusUnauthorized:
+			code = codes.Unauthenticated
+		case http.StatusForbidden:
+			code = codes.PermissionDenied
+		}
+		return status.Errorf(code, "%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return restErr.toStatus()
+}
+
+func (e *restError) toStatus() error {
+	msg := e.Message
+	if len(msg) == 0 {
+		msg = e.Error
+	}
+	return status.Error(codes.Code(e.Code), msg)
+}
+
+// restPath escapes and appends the parameters to the path
+func restPath(path string, params ...string) string {
+	for _, param := range params {
+		path = path + "/" + url.PathEscape(param)
+	}
+	return path
+}
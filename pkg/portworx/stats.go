@@ -0,0 +1,112 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StatsWorkers is the default number of volume stats requested at the same
+// time
+const StatsWorkers = 8
+
+func toSec(ms uint64) uint64 {
+	return ms / 1000
+}
+
+// Iops returns the reads and writes per second in the interval of the stats
+func Iops(s *api.Stats) uint64 {
+	intv := toSec(s.GetIntervalMs())
+	if intv == 0 {
+		return 0
+	}
+	return (s.GetWrites() + s.GetReads()) / intv
+}
+
+// WriteThroughput returns the bytes written per second in the interval of
+// the stats
+func WriteThroughput(s *api.Stats) uint64 {
+	intv := toSec(s.GetIntervalMs())
+	if intv == 0 {
+		return 0
+	}
+	return (s.GetWriteBytes()) / intv
+}
+
+// ReadThroughput returns the bytes read per second in the interval of the
+// stats
+func ReadThroughput(s *api.Stats) uint64 {
+	intv := toSec(s.GetIntervalMs())
+	if intv == 0 {
+		return 0
+	}
+	return (s.GetReadBytes()) / intv
+}
+
+// ReadLatency returns the average latency of the reads in microseconds
+func ReadLatency(s *api.Stats) uint64 {
+	if s.GetReads() == 0 {
+		return 0
+	}
+	return (uint64)((s.GetReadMs() * 1000) / s.GetReads())
+}
+
+// WriteLatency returns the average latency of the writes in microseconds
+func WriteLatency(s *api.Stats) uint64 {
+	if s.GetWrites() == 0 {
+		return 0
+	}
+	return (uint64)((s.GetWriteMs() * 1000) / s.GetWrites())
+}
+
+// AttachedVolumes returns the volumes which are attached, which are the only
+// ones with IO
+func AttachedVolumes(vols []*api.Volume) []*api.Volume {
+	attached := make([]*api.Volume, 0, len(vols))
+	for _, v := range vols {
+		if len(v.GetAttachedOn()) != 0 {
+			attached = append(attached, v)
+		}
+	}
+	return attached
+}
+
+// GetVolumesStats returns the stats of each volume since the previous call
+// using up to workers requests at the same time. The stats of volumes which
+// were deleted are nil.
+func GetVolumesStats(pxops PxOps, vols []*api.Volume, workers int) ([]*api.Stats, error) {
+	stats := make([]*api.Stats, len(vols))
+	errs := util.ForEach(len(vols), workers, false, func(i int) error {
+		s, err := pxops.GetStats(vols[i], true)
+		if status.Code(err) == codes.NotFound {
+			// The volume was deleted
+			return nil
+		}
+		stats[i] = s
+		return err
+	})
+	for i, err := range errs {
+		if err != nil && err != util.ErrNotStarted {
+			return nil, util.PxErrorMessagef(err, "Failed to get the stats of volume %s",
+				vols[i].GetLocator().GetName())
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statsPxOps returns the stats of the volumes by id. Volumes without stats
+// are not found.
+type statsPxOps struct {
+	fakePxOps
+	lock  sync.Mutex
+	stats map[string]*api.Stats
+	err   error
+}
+
+func (f *statsPxOps) GetStats(v *api.Volume, notCumulative bool) (*api.Stats, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	if s, ok := f.stats[v.GetId()]; ok {
+		return s, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "Volume %s not found", v.GetId())
+}
+
+func TestAttachedVolumes(t *testing.T) {
+	vols := []*api.Volume{
+		{Id: "1", AttachedOn: "node1"},
+		{Id: "2"},
+		{Id: "3", AttachedOn: "node2"},
+	}
+	attached := AttachedVolumes(vols)
+	assert.Equal(t, []*api.Volume{vols[0], vols[2]}, attached)
+}
+
+func TestGetVolumesStats(t *testing.T) {
+	vols := []*api.Volume{
+		{Id: "1", Locator: &api.VolumeLocator{Name: "vol1"}},
+		{Id: "2", Locator: &api.VolumeLocator{Name: "deleted"}},
+		{Id: "3", Locator: &api.VolumeLocator{Name: "vol3"}},
+	}
+	pxops := &statsPxOps{
+		stats: map[string]*api.Stats{
+			"1": {Reads: 1},
+			"3": {Reads: 3},
+		},
+	}
+	stats, err := GetVolumesStats(pxops, vols, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*api.Stats{{Reads: 1}, nil, {Reads: 3}}, stats)
+
+	pxops.err = fmt.Errorf("failed")
+	_, err = GetVolumesStats(pxops, vols, 1)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "vol1")
+
+	stats, err = GetVolumesStats(pxops, nil, StatsWorkers)
+	assert.NoError(t, err)
+	assert.Len(t, stats, 0)
+}
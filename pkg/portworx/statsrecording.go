@@ -0,0 +1,200 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StatsFrame is the stats of the volumes, by volume name, on a refresh
+type StatsFrame struct {
+	Time  time.Time             `json:"time"`
+	Stats map[string]*api.Stats `json:"stats"`
+}
+
+// RecordingVolumes returns the volumes and stats of the wrapped Volumes and
+// keeps the stats returned since the last call to Record, which writes them
+// as a frame to the recording file. The file has a frame in json per line.
+type RecordingVolumes struct {
+	Volumes
+	file  *os.File
+	stats map[string]*api.Stats
+}
+
+// NewRecordingVolumes returns a RecordingVolumes writing to the file
+func NewRecordingVolumes(vols Volumes, filename string) (*RecordingVolumes, error) {
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &RecordingVolumes{
+		Volumes: vols,
+		file:    f,
+		stats:   make(map[string]*api.Stats),
+	}, nil
+}
+
+// GetStats returns the stats of the volume and keeps them for the next frame
+func (p *RecordingVolumes) GetStats(v *api.Volume, notCumulative bool) (*api.Stats, error) {
+	s, err := p.Volumes.GetStats(v, notCumulative)
+	if err != nil {
+		return nil, err
+	}
+	p.stats[v.GetLocator().GetName()] = s
+	return s, nil
+}
+
+// Record writes the stats returned since the previous frame as a frame
+func (p *RecordingVolumes) Record(t time.Time) error {
+	data, err := json.Marshal(&StatsFrame{Time: t, Stats: p.stats})
+	if err != nil {
+		return err
+	}
+	p.stats = make(map[string]*api.Stats)
+	_, err = p.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the recording file
+func (p *RecordingVolumes) Close() error {
+	return p.file.Close()
+}
+
+// ReadStatsRecording returns the frames of the recording file sorted by time
+func ReadStatsRecording(filename string) ([]*StatsFrame, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	frames := make([]*StatsFrame, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		frame := &StatsFrame{}
+		if err := json.Unmarshal(scanner.Bytes(), frame); err != nil {
+			return nil, fmt.Errorf("Failed to read frame at line %d of %s: %v", line, filename, err)
+		}
+		frames = append(frames, frame)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("No stats found in %s", filename)
+	}
+
+	sort.SliceStable(frames, func(i, j int) bool {
+		return frames[i].Time.Before(frames[j].Time)
+	})
+	return frames, nil
+}
+
+// ReplayVolumes returns the volumes and stats of the current frame of a
+// recording. The volumes only have a name.
+type ReplayVolumes struct {
+	frames []*StatsFrame
+	cur    int
+	vols   []*api.Volume
+}
+
+// NewReplayVolumes returns a ReplayVolumes on the first frame
+func NewReplayVolumes(frames []*StatsFrame) *ReplayVolumes {
+	p := &ReplayVolumes{frames: frames}
+	p.Reset()
+	return p
+}
+
+// Reset moves the replay back to the first frame
+func (p *ReplayVolumes) Reset() {
+	p.cur = 0
+	p.vols = nil
+}
+
+// Seek moves to the last frame recorded at or before t
+func (p *ReplayVolumes) Seek(t time.Time) {
+	cur := sort.Search(len(p.frames), func(i int) bool {
+		return p.frames[i].Time.After(t)
+	}) - 1
+	if cur < 0 {
+		cur = 0
+	}
+	if cur != p.cur {
+		p.cur = cur
+		p.vols = nil
+	}
+}
+
+// End returns true if the current frame is the last one
+func (p *ReplayVolumes) End() bool {
+	return p.cur == len(p.frames)-1
+}
+
+// Current returns the current frame
+func (p *ReplayVolumes) Current() *StatsFrame {
+	return p.frames[p.cur]
+}
+
+// Interval returns the time between the first two frames, or zero if the
+// recording has a single frame
+func (p *ReplayVolumes) Interval() time.Duration {
+	if len(p.frames) < 2 {
+		return 0
+	}
+	return p.frames[1].Time.Sub(p.frames[0].Time)
+}
+
+// GetVolumes returns the volumes in the current frame sorted by name
+func (p *ReplayVolumes) GetVolumes() ([]*api.Volume, error) {
+	if p.vols == nil {
+		names := make([]string, 0, len(p.Current().Stats))
+		for name := range p.Current().Stats {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		p.vols = make([]*api.Volume, 0, len(names))
+		for _, name := range names {
+			p.vols = append(p.vols, &api.Volume{
+				Id:      name,
+				Locator: &api.VolumeLocator{Name: name},
+			})
+		}
+	}
+	return p.vols, nil
+}
+
+// GetStats returns the stats of the volume in the current frame
+func (p *ReplayVolumes) GetStats(v *api.Volume, notCumulative bool) (*api.Stats, error) {
+	s, ok := p.Current().Stats[v.GetLocator().GetName()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "No stats recorded for volume %s", v.GetLocator().GetName())
+	}
+	return s, nil
+}
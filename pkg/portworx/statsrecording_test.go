@@ -0,0 +1,98 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsRecording(t *testing.T) {
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	frames := []*StatsFrame{
+		{Time: start, Stats: map[string]*api.Stats{
+			"b": {Reads: 1},
+			"a": {Writes: 2},
+		}},
+		{Time: start.Add(2 * time.Second), Stats: map[string]*api.Stats{
+			"a": {Writes: 3},
+		}},
+	}
+	rv := NewReplayVolumes(frames)
+	assert.Equal(t, 2*time.Second, rv.Interval())
+
+	filename := filepath.Join(t.TempDir(), "stats.jsonl")
+	recording, err := NewRecordingVolumes(rv, filename)
+	assert.NoError(t, err)
+
+	// Record every frame of the replay
+	for _, f := range frames {
+		rv.Seek(f.Time)
+		vols, err := recording.GetVolumes()
+		assert.NoError(t, err)
+		for _, v := range vols {
+			_, err := recording.GetStats(v, true)
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, recording.Record(f.Time))
+	}
+	assert.NoError(t, recording.Close())
+
+	recorded, err := ReadStatsRecording(filename)
+	assert.NoError(t, err)
+	assert.Len(t, recorded, 2)
+	for i := range frames {
+		assert.True(t, frames[i].Time.Equal(recorded[i].Time))
+		assert.Equal(t, len(frames[i].Stats), len(recorded[i].Stats))
+	}
+	assert.Equal(t, uint64(3), recorded[1].Stats["a"].GetWrites())
+}
+
+func TestReplayVolumes(t *testing.T) {
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	rv := NewReplayVolumes([]*StatsFrame{
+		{Time: start, Stats: map[string]*api.Stats{"b": {}, "a": {Reads: 1}}},
+		{Time: start.Add(time.Second), Stats: map[string]*api.Stats{"a": {Reads: 2}}},
+		{Time: start.Add(3 * time.Second), Stats: map[string]*api.Stats{"a": {Reads: 3}}},
+	})
+
+	vols, err := rv.GetVolumes()
+	assert.NoError(t, err)
+	assert.Len(t, vols, 2)
+	assert.Equal(t, "a", vols[0].GetLocator().GetName())
+	assert.Equal(t, "b", vols[1].GetLocator().GetName())
+	s, err := rv.GetStats(vols[0], true)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), s.GetReads())
+
+	// Seek shows the last frame recorded before the time
+	rv.Seek(start.Add(2 * time.Second))
+	assert.Equal(t, start.Add(time.Second), rv.Current().Time)
+	assert.False(t, rv.End())
+	vols, _ = rv.GetVolumes()
+	assert.Len(t, vols, 1)
+	_, err = rv.GetStats(&api.Volume{Locator: &api.VolumeLocator{Name: "b"}}, true)
+	assert.Error(t, err)
+
+	rv.Seek(start.Add(time.Hour))
+	assert.True(t, rv.End())
+	rv.Seek(start.Add(-time.Hour))
+	assert.Equal(t, start, rv.Current().Time)
+}
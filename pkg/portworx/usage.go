@@ -0,0 +1,156 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/kubernetes"
+)
+
+const (
+	UsageByNamespace = "namespace"
+	UsageByOwner     = "owner"
+	// UsageByLabel is followed by the label key, as in label=app
+	UsageByLabel = "label"
+
+	// UsageNone is the group of the volumes without a namespace, owner or label
+	UsageNone = "<none>"
+)
+
+// VolumeUsage is the storage used by a group of volumes. Snapshots are
+// accounted in the group of their parent volume.
+type VolumeUsage struct {
+	Name             string `json:"name" yaml:"name"`
+	Volumes          int    `json:"volumes" yaml:"volumes"`
+	Snapshots        int    `json:"snapshots" yaml:"snapshots"`
+	ProvisionedBytes uint64 `json:"provisionedBytes" yaml:"provisionedBytes"`
+	UsedBytes        uint64 `json:"usedBytes" yaml:"usedBytes"`
+	SnapshotBytes    uint64 `json:"snapshotBytes" yaml:"snapshotBytes"`
+}
+
+// UsageReport is the storage used by volumes grouped by namespace, owner
+// or label
+type UsageReport struct {
+	By     string         `json:"by" yaml:"by"`
+	Groups []*VolumeUsage `json:"groups" yaml:"groups"`
+	Total  *VolumeUsage   `json:"total" yaml:"total"`
+}
+
+// ParseUsageBy validates the grouping of the usage report and returns the
+// label key when grouping by label
+func ParseUsageBy(by string) (string, error) {
+	switch {
+	case by == UsageByNamespace || by == UsageByOwner:
+		return "", nil
+	case strings.HasPrefix(by, UsageByLabel+"="):
+		if key := strings.TrimPrefix(by, UsageByLabel+"="); len(key) != 0 {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("Invalid value %s. Usage can be grouped by namespace, owner or label=KEY", by)
+}
+
+// UsageGroupByNamespace returns the namespace of the PVC of the volume
+func UsageGroupByNamespace(pxpvcs []*kubernetes.PxPvc) func(v *api.Volume) string {
+	namespaces := make(map[string]string)
+	for _, pxpvc := range pxpvcs {
+		namespaces[pxpvc.GetVolume().GetId()] = pxpvc.Namespace
+	}
+	return func(v *api.Volume) string {
+		return namespaces[v.GetId()]
+	}
+}
+
+// UsageGroupByOwner returns the owner of the volume
+func UsageGroupByOwner(v *api.Volume) string {
+	return v.GetSpec().GetOwnership().GetOwner()
+}
+
+// UsageGroupByLabel returns the value of the label of the volume
+func UsageGroupByLabel(key string) func(v *api.Volume) string {
+	return func(v *api.Volume) string {
+		return v.GetLocator().GetVolumeLabels()[key]
+	}
+}
+
+func isSnapshot(v *api.Volume) bool {
+	return v.GetReadonly() && len(v.GetSource().GetParent()) != 0
+}
+
+// NewUsageReport adds up the usage of the volumes in the group returned
+// by groupOf. Groups are sorted by the bytes used, highest first.
+func NewUsageReport(by string, vols []*api.Volume, groupOf func(v *api.Volume) string) *UsageReport {
+	byId := make(map[string]*api.Volume, len(vols))
+	for _, v := range vols {
+		byId[v.GetId()] = v
+	}
+
+	report := &UsageReport{
+		By:    by,
+		Total: &VolumeUsage{Name: "Total"},
+	}
+	groups := make(map[string]*VolumeUsage)
+	for _, v := range vols {
+		owner := v
+		if isSnapshot(v) {
+			if parent, ok := byId[v.GetSource().GetParent()]; ok {
+				owner = parent
+			}
+		}
+		name := groupOf(owner)
+		if len(name) == 0 {
+			name = UsageNone
+		}
+		group, ok := groups[name]
+		if !ok {
+			group = &VolumeUsage{Name: name}
+			groups[name] = group
+			report.Groups = append(report.Groups, group)
+		}
+
+		for _, usage := range []*VolumeUsage{group, report.Total} {
+			if isSnapshot(v) {
+				usage.Snapshots++
+				usage.SnapshotBytes += v.GetUsage()
+			} else {
+				usage.Volumes++
+				usage.ProvisionedBytes += v.GetSpec().GetSize()
+				usage.UsedBytes += v.GetUsage()
+			}
+		}
+	}
+
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if a.UsedBytes+a.SnapshotBytes != b.UsedBytes+b.SnapshotBytes {
+			return a.UsedBytes+a.SnapshotBytes > b.UsedBytes+b.SnapshotBytes
+		}
+		return a.Name < b.Name
+	})
+	return report
+}
+
+// Top keeps only the n groups using the most bytes. The total still
+// accounts for all the volumes.
+func (r *UsageReport) Top(n int) {
+	if n > 0 && n < len(r.Groups) {
+		r.Groups = r.Groups[:n]
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"testing"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/kubernetes"
+	"github.com/stretchr/testify/assert"
+)
+
+func usageVolume(id, owner string, labels map[string]string, size, used uint64) *api.Volume {
+	return &api.Volume{
+		Id:      id,
+		Locator: &api.VolumeLocator{Name: id, VolumeLabels: labels},
+		Spec: &api.VolumeSpec{
+			Size:      size,
+			Ownership: &api.Ownership{Owner: owner},
+		},
+		Usage: used,
+	}
+}
+
+func usageSnapshot(id, parent string, used uint64) *api.Volume {
+	v := usageVolume(id, "", nil, 0, used)
+	v.Readonly = true
+	v.Source = &api.Source{Parent: parent}
+	return v
+}
+
+func TestParseUsageBy(t *testing.T) {
+	key, err := ParseUsageBy("namespace")
+	assert.NoError(t, err)
+	assert.Empty(t, key)
+
+	key, err = ParseUsageBy("label=team")
+	assert.NoError(t, err)
+	assert.Equal(t, "team", key)
+
+	for _, invalid := range []string{"label", "label=", "node"} {
+		_, err = ParseUsageBy(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestUsageReport(t *testing.T) {
+	vols := []*api.Volume{
+		usageVolume("v1", "alice", map[string]string{"team": "db"}, 100, 10),
+		usageVolume("v2", "bob", map[string]string{"team": "web"}, 200, 50),
+		usageVolume("v3", "alice", nil, 300, 5),
+		usageSnapshot("s1", "v1", 7),
+		usageSnapshot("s2", "missing", 3),
+	}
+
+	report := NewUsageReport(UsageByOwner, vols, UsageGroupByOwner)
+	assert.Len(t, report.Groups, 3)
+	assert.Equal(t, &VolumeUsage{Name: "bob", Volumes: 1, ProvisionedBytes: 200, UsedBytes: 50}, report.Groups[0])
+	assert.Equal(t, &VolumeUsage{Name: "alice", Volumes: 2, Snapshots: 1, ProvisionedBytes: 400, UsedBytes: 15, SnapshotBytes: 7}, report.Groups[1])
+	// The parent of s2 does not exist
+	assert.Equal(t, &VolumeUsage{Name: UsageNone, Snapshots: 1, SnapshotBytes: 3}, report.Groups[2])
+	assert.Equal(t, &VolumeUsage{Name: "Total", Volumes: 3, Snapshots: 2, ProvisionedBytes: 600, UsedBytes: 65, SnapshotBytes: 10}, report.Total)
+
+	report.Top(1)
+	assert.Len(t, report.Groups, 1)
+	assert.Equal(t, 3, report.Total.Volumes)
+
+	report = NewUsageReport("label=team", vols, UsageGroupByLabel("team"))
+	assert.Equal(t, []string{"web", "db", UsageNone}, usageNames(report))
+
+	pxpvcs := []*kubernetes.PxPvc{
+		{Namespace: "prod", PxVolume: vols[1]},
+		{Namespace: "dev", PxVolume: vols[2]},
+	}
+	report = NewUsageReport(UsageByNamespace, vols, UsageGroupByNamespace(pxpvcs))
+	assert.Equal(t, []string{"prod", UsageNone, "dev"}, usageNames(report))
+}
+
+func usageNames(report *UsageReport) []string {
+	names := make([]string, 0, len(report.Groups))
+	for _, g := range report.Groups {
+		names = append(names, g.Name)
+	}
+	return names
+}
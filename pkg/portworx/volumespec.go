@@ -0,0 +1,330 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	sched "github.com/portworx/pxc/pkg/openstorage/sched"
+	"github.com/portworx/pxc/pkg/util"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+)
+
+// IoProfiles are the IO profiles which can be set on a volume
+var IoProfiles = []string{"sequential", "cms", "db", "db_remote", "sync_shared"}
+
+// SnapshotScheduleOpts is a snapshot schedule in the format used
+// by the command line
+type SnapshotScheduleOpts struct {
+	// Periodic is the interval in minutes and the number of snapshots to keep: mins,k
+	Periodic string `json:"periodic,omitempty" yaml:"periodic,omitempty"`
+	// Daily snapshots at hh:mm,k
+	Daily []string `json:"daily,omitempty" yaml:"daily,omitempty"`
+	// Weekly snapshots at weekday@hh:mm,k
+	Weekly []string `json:"weekly,omitempty" yaml:"weekly,omitempty"`
+	// Monthly snapshots at day@hh:mm,k
+	Monthly []string `json:"monthly,omitempty" yaml:"monthly,omitempty"`
+	// Policy is a comma separated list of schedule policy names
+	Policy string `json:"policy,omitempty" yaml:"policy,omitempty"`
+}
+
+// ScheduleString returns the snapshot schedule as stored in the volume spec
+func (s *SnapshotScheduleOpts) ScheduleString() (string, error) {
+	updates := []sched.RetainIntervalSpec{}
+	if len(s.Periodic) > 0 {
+		spec, err := sched.ParsePeriodic(s.Periodic)
+		if err != nil {
+			return "", err
+		}
+		updates = append(updates, spec)
+		if spec.Period == 0 {
+			return sched.ScheduleString(updates, nil)
+		}
+	}
+
+	for _, freq := range []string{sched.DailyType, sched.WeeklyType, sched.MonthlyType} {
+		var items []string
+		switch freq {
+		case sched.DailyType:
+			items = s.Daily
+		case sched.WeeklyType:
+			items = s.Weekly
+		case sched.MonthlyType:
+			items = s.Monthly
+		}
+
+		for _, item := range items {
+			spec, err := sched.ParseCLI[freq](item)
+			if err != nil {
+				return "", err
+			}
+			updates = append(updates, spec)
+		}
+	}
+
+	p, err := sched.NewPolicyTags(s.Policy)
+	if err != nil {
+		return "", err
+	}
+	return sched.ScheduleString(updates, p)
+}
+
+// NewSnapshotScheduleOpts returns the command line format of the snapshot
+// schedule stored in a volume spec
+func NewSnapshotScheduleOpts(schedule string) (*SnapshotScheduleOpts, error) {
+	intervals, policies, err := sched.ParseScheduleAndPolicies(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SnapshotScheduleOpts{}
+	for _, interval := range intervals {
+		spec := interval.RetainIntervalSpec()
+		retain := ""
+		if spec.Retain != 0 {
+			retain = fmt.Sprintf(",%d", spec.Retain)
+		}
+		switch spec.Freq {
+		case sched.PeriodicType:
+			s.Periodic = fmt.Sprintf("%d%s", uint64(time.Duration(spec.Period)/time.Minute), retain)
+		case sched.DailyType:
+			s.Daily = append(s.Daily, fmt.Sprintf("%02d:%02d%s", spec.Hour, spec.Minute, retain))
+		case sched.WeeklyType:
+			s.Weekly = append(s.Weekly, fmt.Sprintf("%s@%02d:%02d%s",
+				strings.ToLower(time.Weekday(spec.Weekday).String()), spec.Hour, spec.Minute, retain))
+		case sched.MonthlyType:
+			s.Monthly = append(s.Monthly, fmt.Sprintf("%d@%02d:%02d%s", spec.Day, spec.Hour, spec.Minute, retain))
+		}
+	}
+	if policies != nil {
+		s.Policy = strings.Join(policies.Names, ",")
+	}
+	return s, nil
+}
+
+// SnapshotScheduleSummary returns a description of the snapshot schedule
+// which does not depend on the order of the intervals in the schedule.
+// Invalid schedules are returned unchanged.
+func SnapshotScheduleSummary(schedule string) string {
+	if len(schedule) == 0 {
+		return ""
+	}
+	intervals, policies, err := sched.ParseScheduleAndPolicies(schedule)
+	if err != nil {
+		return schedule
+	}
+
+	items := make([]string, 0, len(intervals)+1)
+	for _, interval := range intervals {
+		items = append(items, interval.String())
+	}
+	sort.Strings(items)
+	if policies != nil {
+		items = append([]string{policies.String()}, items...)
+	}
+	return strings.Join(items, ", ")
+}
+
+// GetIoProfile returns the IO profile for the name provided
+func GetIoProfile(s string) (api.IoProfile, error) {
+	switch s {
+	case "db":
+		return api.IoProfile_IO_PROFILE_DB, nil
+	case "cms":
+		return api.IoProfile_IO_PROFILE_CMS, nil
+	case "db_remote":
+		return api.IoProfile_IO_PROFILE_DB_REMOTE, nil
+	case "sync_shared":
+		return api.IoProfile_IO_PROFILE_SYNC_SHARED, nil
+	case "sequential":
+		return api.IoProfile_IO_PROFILE_SEQUENTIAL, nil
+	}
+	return api.IoProfile_IO_PROFILE_SEQUENTIAL,
+		fmt.Errorf("Invalid IO profile %s. Valid values are %s", s, strings.Join(IoProfiles, ", "))
+}
+
+// IoProfileString returns the name used in the command line for the IO profile
+func IoProfileString(p api.IoProfile) string {
+	return strings.ToLower(strings.TrimPrefix(p.String(), "IO_PROFILE_"))
+}
+
+// GetFSType returns the filesystem type for the name provided
+func GetFSType(s string) (api.FSType, error) {
+	switch s {
+	case "ext4":
+		return api.FSType_FS_TYPE_EXT4, nil
+	case "none":
+		return api.FSType_FS_TYPE_NONE, nil
+	}
+	return api.FSType_FS_TYPE_NONE, fmt.Errorf("Invalid filesystem %s. Valid values are [none, ext4]", s)
+}
+
+// FSTypeString returns the name used in the command line for the filesystem type
+func FSTypeString(fs api.FSType) string {
+	return strings.ToLower(strings.TrimPrefix(fs.String(), "FS_TYPE_"))
+}
+
+// ValidateVolumeCreateRequest checks the values of the spec of a new volume
+func ValidateVolumeCreateRequest(req *api.SdkVolumeCreateRequest) error {
+	if len(req.GetName()) == 0 {
+		return fmt.Errorf("Must supply a name for volume")
+	}
+	if req.GetSpec().GetQueueDepth() < 1 || req.GetSpec().GetQueueDepth() > 256 {
+		return fmt.Errorf("Queuedepth has to be in the range of 1 to 256.")
+	}
+	return nil
+}
+
+// CreateVolume validates the request and creates the volume. It returns the
+// id of the new volume.
+func CreateVolume(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	req *api.SdkVolumeCreateRequest,
+) (string, error) {
+	if err := ValidateVolumeCreateRequest(req); err != nil {
+		return "", err
+	}
+
+	// Update default EXT4, if it fs is 'none' and shared volume
+	if req.Spec.Format == api.FSType_FS_TYPE_NONE && req.Spec.Shared {
+		req.Spec.Format = api.FSType_FS_TYPE_EXT4
+	}
+
+	volumes := api.NewOpenStorageVolumeClient(conn)
+	resp, err := volumes.Create(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return resp.GetVolumeId(), nil
+}
+
+// UpdateVolume validates the update request and sends it to the server
+func UpdateVolume(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	req *api.SdkVolumeUpdateRequest,
+) error {
+	if err := ValidateVolumeSpec(req.GetSpec()); err != nil {
+		return err
+	}
+
+	volumes := api.NewOpenStorageVolumeClient(conn)
+	_, err := volumes.Update(ctx, req)
+	return err
+}
+
+// ApplyVolumeSpecUpdate returns a copy of the spec with the update applied
+func ApplyVolumeSpecUpdate(spec *api.VolumeSpec, update *api.VolumeSpecUpdate) *api.VolumeSpec {
+	s := &api.VolumeSpec{}
+	if spec != nil {
+		s = proto.Clone(spec).(*api.VolumeSpec)
+	}
+	if update == nil {
+		return s
+	}
+
+	if update.GetSizeOpt() != nil && update.GetSize() != 0 {
+		s.Size = update.GetSize()
+	}
+	if update.GetHaLevelOpt() != nil {
+		s.HaLevel = update.GetHaLevel()
+		if len(update.GetReplicaSet().GetNodes()) != 0 {
+			s.ReplicaSet = update.GetReplicaSet()
+		}
+	}
+	if update.GetIoProfileOpt() != nil {
+		s.IoProfile = update.GetIoProfile()
+	}
+	if update.GetSharedOpt() != nil {
+		s.Shared = update.GetShared()
+	}
+	if update.GetSharedv4Opt() != nil {
+		s.Sharedv4 = update.GetSharedv4()
+	}
+	if update.GetStickyOpt() != nil {
+		s.Sticky = update.GetSticky()
+	}
+	if update.GetJournalOpt() != nil {
+		s.Journal = update.GetJournal()
+	}
+	if update.GetNodiscardOpt() != nil {
+		s.Nodiscard = update.GetNodiscard()
+	}
+	if update.GetScaleOpt() != nil {
+		s.Scale = update.GetScale()
+	}
+	if update.GetQueueDepthOpt() != nil {
+		s.QueueDepth = update.GetQueueDepth()
+	}
+	if update.GetSnapshotScheduleOpt() != nil {
+		s.SnapshotSchedule = update.GetSnapshotSchedule()
+	}
+	if update.GetIoStrategy() != nil {
+		s.IoStrategy = update.GetIoStrategy()
+	}
+	if update.GetOwnership() != nil {
+		if s.Ownership == nil {
+			s.Ownership = &api.Ownership{}
+		}
+		s.Ownership.Acls = update.GetOwnership().GetAcls()
+	}
+	return s
+}
+
+// VolumeSpecChanges returns the values of the fields of the spec which can be
+// updated before and after the change
+func VolumeSpecChanges(before, after *api.VolumeSpec) []util.FieldChange {
+	fields := func(s *api.VolumeSpec) [][2]string {
+		return [][2]string{
+			{"replicas", fmt.Sprintf("%d", s.GetHaLevel())},
+			{"size", fmt.Sprintf("%d GiB", s.GetSize()/uint64(util.Gi))},
+			{"nodes", strings.Join(s.GetReplicaSet().GetNodes(), ",")},
+			{"shared", TrueOrFalse(s.GetShared())},
+			{"sharedv4", TrueOrFalse(s.GetSharedv4())},
+			{"sticky", TrueOrFalse(s.GetSticky())},
+			{"journal", TrueOrFalse(s.GetJournal())},
+			{"io-profile", IoProfileString(s.GetIoProfile())},
+			{"early-ack", TrueOrFalse(s.GetIoStrategy().GetEarlyAck())},
+			{"async-io", TrueOrFalse(s.GetIoStrategy().GetAsyncIo())},
+			{"nodiscard", TrueOrFalse(s.GetNodiscard())},
+			{"scale", fmt.Sprintf("%d", s.GetScale())},
+			{"queue-depth", fmt.Sprintf("%d", s.GetQueueDepth())},
+			{"snapshot-schedule", SnapshotScheduleSummary(s.GetSnapshotSchedule())},
+			{"collaborators", aclString(s.GetOwnership().GetAcls().GetCollaborators())},
+			{"groups", aclString(s.GetOwnership().GetAcls().GetGroups())},
+		}
+	}
+
+	b := fields(before)
+	a := fields(after)
+	changes := make([]util.FieldChange, len(b))
+	for i := range b {
+		changes[i] = util.FieldChange{
+			Field:   b[i][0],
+			Current: b[i][1],
+			Desired: a[i][1],
+		}
+	}
+	return changes
+}
@@ -0,0 +1,241 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultVolumeWaitTimeout is the default time to wait for a volume condition
+	DefaultVolumeWaitTimeout = 10 * time.Minute
+
+	VolumeConditionStatus = "status"
+	VolumeConditionHa     = "ha"
+	VolumeConditionSize   = "size"
+
+	VolumeStatusUp         = "up"
+	VolumeStatusAttached   = "attached"
+	VolumeStatusDetached   = "detached"
+	VolumeStatusResyncDone = "resync-done"
+	VolumeStatusDeleted    = "deleted"
+)
+
+// VolumeWaitPeriod is the time between checks of the volume condition
+var VolumeWaitPeriod = 2 * time.Second
+
+var volumeStatuses = []string{
+	VolumeStatusUp,
+	VolumeStatusAttached,
+	VolumeStatusDetached,
+	VolumeStatusResyncDone,
+	VolumeStatusDeleted,
+}
+
+// VolumeCondition is a condition of a volume which can be waited for
+type VolumeCondition struct {
+	Type  string
+	Value string
+	// number is the value of ha and size conditions. Size is in bytes.
+	number uint64
+}
+
+// ParseVolumeCondition parses conditions like status=up, ha=3 or size=10 (GiB)
+func ParseVolumeCondition(s string) (*VolumeCondition, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || len(parts[1]) == 0 {
+		return nil, fmt.Errorf("Invalid condition %s. Conditions must be in the format "+
+			"status=[%s], ha=N or size=N", s, strings.Join(volumeStatuses, "|"))
+	}
+
+	c := &VolumeCondition{
+		Type:  parts[0],
+		Value: strings.ToLower(parts[1]),
+	}
+	switch c.Type {
+	case VolumeConditionStatus:
+		if !util.ListContains(volumeStatuses, c.Value) {
+			return nil, fmt.Errorf("Invalid status %s. Valid values are %s",
+				c.Value, strings.Join(volumeStatuses, ", "))
+		}
+	case VolumeConditionHa, VolumeConditionSize:
+		n, err := strconv.ParseUint(c.Value, 10, 64)
+		if err != nil || n == 0 {
+			return nil, fmt.Errorf("Invalid value %s for %s. It must be a positive number", c.Value, c.Type)
+		}
+		c.number = n
+		if c.Type == VolumeConditionSize {
+			c.number = n * uint64(util.Gi)
+		}
+	default:
+		return nil, fmt.Errorf("Unknown condition %s. Valid conditions are status, ha and size", c.Type)
+	}
+	return c, nil
+}
+
+// NewVolumeStatusCondition returns the condition for the volume status provided
+func NewVolumeStatusCondition(s string) *VolumeCondition {
+	return &VolumeCondition{Type: VolumeConditionStatus, Value: s}
+}
+
+// NewVolumeHaCondition returns a condition met when the volume has
+// the HA level provided
+func NewVolumeHaCondition(ha int64) *VolumeCondition {
+	return &VolumeCondition{Type: VolumeConditionHa, Value: fmt.Sprintf("%d", ha), number: uint64(ha)}
+}
+
+// NewVolumeSizeCondition returns a condition met when the volume has
+// at least the size in bytes provided
+func NewVolumeSizeCondition(size uint64) *VolumeCondition {
+	return &VolumeCondition{
+		Type:   VolumeConditionSize,
+		Value:  fmt.Sprintf("%d", size/uint64(util.Gi)),
+		number: size,
+	}
+}
+
+func (c *VolumeCondition) String() string {
+	return c.Type + "=" + c.Value
+}
+
+// Met returns true if the volume meets the condition. A nil volume is a
+// volume which does not exist.
+func (c *VolumeCondition) Met(v *api.Volume, replInfo *ReplicationInfo) bool {
+	if v == nil {
+		return c.Type == VolumeConditionStatus && c.Value == VolumeStatusDeleted
+	}
+
+	switch c.Type {
+	case VolumeConditionStatus:
+		switch c.Value {
+		case VolumeStatusUp:
+			return v.GetStatus() == api.VolumeStatus_VOLUME_STATUS_UP
+		case VolumeStatusAttached:
+			return v.GetState() == api.VolumeState_VOLUME_STATE_ATTACHED
+		case VolumeStatusDetached:
+			return v.GetState() == api.VolumeState_VOLUME_STATE_DETACHED
+		case VolumeStatusResyncDone:
+			return !replicationInProgress(replInfo) &&
+				(replInfo == nil || replInfo.Status == "UP" || replInfo.Status == "Detached")
+		}
+	case VolumeConditionHa:
+		if v.GetSpec().GetHaLevel() != int64(c.number) || replicationInProgress(replInfo) {
+			return false
+		}
+		for _, rset := range v.GetReplicaSets() {
+			if uint64(len(rset.GetNodes())) != c.number {
+				return false
+			}
+		}
+		return true
+	case VolumeConditionSize:
+		return v.GetSpec().GetSize() >= c.number
+	}
+	return false
+}
+
+func replicationInProgress(replInfo *ReplicationInfo) bool {
+	if replInfo == nil {
+		return false
+	}
+	for _, rsi := range replInfo.Rsi {
+		if len(rsi.HaIncrease) != 0 || len(rsi.ReAddOn) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// volumeProgress describes the current state of the volume while waiting
+func volumeProgress(v *api.Volume, replInfo *ReplicationInfo) string {
+	if v == nil {
+		return "not found"
+	}
+
+	progress := []string{
+		"status " + strings.ToLower(strings.TrimPrefix(v.GetStatus().String(), "VOLUME_STATUS_")),
+		"state " + strings.ToLower(strings.TrimPrefix(v.GetState().String(), "VOLUME_STATE_")),
+		fmt.Sprintf("ha %d", v.GetSpec().GetHaLevel()),
+	}
+	if replInfo != nil {
+		progress = append(progress, "replication "+strings.ToLower(replInfo.Status))
+		for _, rsi := range replInfo.Rsi {
+			if len(rsi.HaIncrease) != 0 {
+				progress = append(progress, "HA increase on "+rsi.HaIncrease)
+			}
+			for _, readd := range rsi.ReAddOn {
+				progress = append(progress, "re-add on "+readd)
+			}
+		}
+	}
+	return strings.Join(progress, ", ")
+}
+
+// WaitForVolume waits until the volume meets the condition. The state of
+// the volume is printed to Stderr every time it changes.
+func WaitForVolume(
+	pxops PxOps,
+	name string,
+	condition *VolumeCondition,
+	timeout time.Duration,
+) error {
+	nodes := NewNodes(pxops, &NodeSpec{})
+	lastProgress := ""
+
+	err := util.WaitFor(timeout, VolumeWaitPeriod, func() (bool, error) {
+		var (
+			vol      *api.Volume
+			replInfo *ReplicationInfo
+		)
+		resp, err := pxops.GetVolumeById(name)
+		if status.Code(err) == codes.NotFound {
+			vol = nil
+		} else if err != nil {
+			return false, util.PxErrorMessagef(err, "Failed to get volume %s", name)
+		} else {
+			vol = resp.GetVolume()
+			if len(vol.GetReplicaSets()) != 0 {
+				replInfo, err = nodes.GetReplicationInfo(vol)
+				if err != nil {
+					return false, err
+				}
+			}
+		}
+
+		if condition.Met(vol, replInfo) {
+			return false, nil
+		}
+
+		if progress := volumeProgress(vol, replInfo); progress != lastProgress {
+			util.Eprintf("Waiting for volume %s to be %s: %s\n", name, condition, progress)
+			lastProgress = progress
+		}
+		return true, nil
+	})
+	if err == util.ErrWaitTimeout {
+		return fmt.Errorf("Timed out after %v waiting for volume %s to be %s", timeout, name, condition)
+	}
+	return err
+}
@@ -0,0 +1,155 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package portworx
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/portworx/pxc/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakePxOps returns the volumes in order on each call to GetVolumeById.
+// A nil volume is returned as not found.
+type fakePxOps struct {
+	vols  []*api.Volume
+	calls int
+	nodes map[string]*api.StorageNode
+}
+
+func (f *fakePxOps) Close() {}
+
+func (f *fakePxOps) GetVolumesBySpec(vs *VolumeSpec) ([]*api.SdkVolumeInspectResponse, error) {
+	return nil, nil
+}
+
+func (f *fakePxOps) GetVolumeById(id string) (*api.SdkVolumeInspectResponse, error) {
+	i := f.calls
+	if i >= len(f.vols) {
+		i = len(f.vols) - 1
+	}
+	f.calls++
+	if f.vols[i] == nil {
+		return nil, status.Errorf(codes.NotFound, "Volume %s not found", id)
+	}
+	return &api.SdkVolumeInspectResponse{Volume: f.vols[i], Name: id}, nil
+}
+
+func (f *fakePxOps) GetStats(v *api.Volume, notCumulative bool) (*api.Stats, error) {
+	return &api.Stats{}, nil
+}
+
+func (f *fakePxOps) EnumerateNodes() ([]string, error) {
+	ids := make([]string, 0, len(f.nodes))
+	for id := range f.nodes {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (f *fakePxOps) GetNode(id string) (*api.StorageNode, error) {
+	if n, ok := f.nodes[id]; ok {
+		return n, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "Node %s not found", id)
+}
+
+func (f *fakePxOps) GetCtx() context.Context {
+	return context.Background()
+}
+
+func (f *fakePxOps) GetConn() *grpc.ClientConn {
+	return nil
+}
+
+func TestParseVolumeCondition(t *testing.T) {
+	c, err := ParseVolumeCondition("status=UP")
+	assert.NoError(t, err)
+	assert.Equal(t, "status=up", c.String())
+
+	c, err = ParseVolumeCondition("ha=3")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), c.number)
+
+	c, err = ParseVolumeCondition("size=2")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2*util.Gi), c.number)
+
+	for _, invalid := range []string{"up", "status=", "status=running", "ha=0", "ha=x", "color=blue"} {
+		_, err = ParseVolumeCondition(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestVolumeConditionMet(t *testing.T) {
+	v := &api.Volume{
+		Status:      api.VolumeStatus_VOLUME_STATUS_UP,
+		State:       api.VolumeState_VOLUME_STATE_DETACHED,
+		Spec:        &api.VolumeSpec{HaLevel: 2, Size: 2 * uint64(util.Gi)},
+		ReplicaSets: []*api.ReplicaSet{{Nodes: []string{"n1", "n2"}}},
+	}
+	inSync := &ReplicationInfo{Status: "UP", Rsi: []*ReplicationSetInfo{{}}}
+	haIncrease := &ReplicationInfo{Status: "UP", Rsi: []*ReplicationSetInfo{{HaIncrease: "n3"}}}
+
+	assert.True(t, NewVolumeStatusCondition(VolumeStatusUp).Met(v, inSync))
+	assert.True(t, NewVolumeStatusCondition(VolumeStatusDetached).Met(v, inSync))
+	assert.False(t, NewVolumeStatusCondition(VolumeStatusAttached).Met(v, inSync))
+	assert.False(t, NewVolumeStatusCondition(VolumeStatusDeleted).Met(v, inSync))
+	assert.True(t, NewVolumeStatusCondition(VolumeStatusDeleted).Met(nil, nil))
+	assert.False(t, NewVolumeStatusCondition(VolumeStatusUp).Met(nil, nil))
+	assert.True(t, NewVolumeStatusCondition(VolumeStatusResyncDone).Met(v, inSync))
+	assert.False(t, NewVolumeStatusCondition(VolumeStatusResyncDone).Met(v, haIncrease))
+
+	assert.True(t, NewVolumeHaCondition(2).Met(v, inSync))
+	assert.False(t, NewVolumeHaCondition(2).Met(v, haIncrease))
+	assert.False(t, NewVolumeHaCondition(3).Met(v, inSync))
+
+	assert.True(t, NewVolumeSizeCondition(2*uint64(util.Gi)).Met(v, nil))
+	assert.False(t, NewVolumeSizeCondition(3*uint64(util.Gi)).Met(v, nil))
+}
+
+func TestWaitForVolume(t *testing.T) {
+	var b bytes.Buffer
+	originalStderr, originalPeriod := util.Stderr, VolumeWaitPeriod
+	util.Stderr = &b
+	VolumeWaitPeriod = time.Millisecond
+	defer func() { util.Stderr, VolumeWaitPeriod = originalStderr, originalPeriod }()
+
+	down := &api.Volume{Status: api.VolumeStatus_VOLUME_STATUS_DOWN, Spec: &api.VolumeSpec{HaLevel: 1}}
+	up := &api.Volume{Status: api.VolumeStatus_VOLUME_STATUS_UP, Spec: &api.VolumeSpec{HaLevel: 1}}
+
+	pxops := &fakePxOps{vols: []*api.Volume{nil, down, down, up}}
+	err := WaitForVolume(pxops, "myvol", NewVolumeStatusCondition(VolumeStatusUp), time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, pxops.calls)
+
+	// Progress is only shown when it changes
+	assert.Equal(t, 2, bytes.Count(b.Bytes(), []byte("Waiting for volume myvol to be status=up")))
+	assert.Contains(t, b.String(), "not found")
+	assert.Contains(t, b.String(), "status down")
+
+	pxops = &fakePxOps{vols: []*api.Volume{down}}
+	err = WaitForVolume(pxops, "myvol", NewVolumeStatusCondition(VolumeStatusUp), 10*time.Millisecond)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Timed out")
+}
@@ -0,0 +1,342 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+)
+
+// ClusterSummary is the status and capacity of the cluster
+type ClusterSummary struct {
+	Name    string
+	Status  string
+	Nodes   int
+	NodesUp int
+	Volumes int
+	Used    uint64
+	Total   uint64
+}
+
+// DashboardTable is the headers and rows of a table of the dashboard
+type DashboardTable struct {
+	Headers []string
+	Rows    [][]string
+}
+
+// DashboardModel provides the data of the panels of the dashboard. Each
+// method is called from the goroutine of its panel, so a slow call only
+// delays the refresh of its own panel.
+type DashboardModel interface {
+	// GetClusterSummary returns the status and capacity of the cluster
+	GetClusterSummary() (*ClusterSummary, error)
+	// GetNodes returns the table of the nodes
+	GetNodes() (*DashboardTable, error)
+	// GetAlerts returns the most recent alerts first
+	GetAlerts() ([]string, error)
+	// GetTopVolumes returns the tables of the busiest and slowest volumes
+	GetTopVolumes() (byIops *DashboardTable, byLatency *DashboardTable, err error)
+	// GetOperations returns the table of the operations in progress
+	GetOperations() (*DashboardTable, error)
+	// HumanizeBytes formats bytes into a string that is easy to read
+	HumanizeBytes(b uint64) string
+}
+
+// DashboardIntervals are the times between refreshes of each panel
+type DashboardIntervals struct {
+	Cluster    time.Duration
+	Nodes      time.Duration
+	Alerts     time.Duration
+	Volumes    time.Duration
+	Operations time.Duration
+}
+
+type dashboard struct {
+	model     DashboardModel
+	intervals DashboardIntervals
+
+	grid       *ui.Grid
+	topLeft    *widgets.Paragraph
+	cluster    *widgets.Paragraph
+	capacity   *widgets.Gauge
+	nodesUp    *widgets.Gauge
+	nodes      *widgets.Table
+	alerts     *widgets.List
+	topIops    *widgets.Table
+	topLatency *widgets.Table
+	operations *widgets.Table
+	termWidth  int
+	termHeight int
+
+	// updates receives the functions updating the widgets of a panel. They
+	// are called from the UI goroutine.
+	updates chan func()
+	refresh []chan struct{}
+	stop    chan struct{}
+}
+
+// Dashboard is a full screen view of the cluster
+type Dashboard interface {
+	// Display shows the dashboard until the user quits
+	Display() error
+}
+
+// NewDashboard returns a dashboard view of the model
+func NewDashboard(model DashboardModel, intervals DashboardIntervals) Dashboard {
+	return &dashboard{
+		model:     model,
+		intervals: intervals,
+		updates:   make(chan func()),
+		stop:      make(chan struct{}),
+	}
+}
+
+func (d *dashboard) Display() error {
+	if err := ui.Init(); err != nil {
+		return err
+	}
+	defer ui.Close()
+	defer close(d.stop)
+
+	d.layoutComponents()
+	d.startPanels()
+	d.render()
+
+	uiEvents := ui.PollEvents()
+	clock := time.NewTicker(time.Second)
+	defer clock.Stop()
+	for {
+		select {
+		case e := <-uiEvents:
+			switch e.ID {
+			case "q", "<C-c>":
+				return nil
+			case "r":
+				for _, r := range d.refresh {
+					select {
+					case r <- struct{}{}:
+					default:
+					}
+				}
+			case "<Resize>":
+				payload := e.Payload.(ui.Resize)
+				d.resize(payload.Width, payload.Height)
+			}
+		case update := <-d.updates:
+			update()
+			d.render()
+		case <-clock.C:
+			d.render()
+		}
+	}
+}
+
+// startPanels starts a goroutine per panel getting its data at the
+// interval of the panel
+func (d *dashboard) startPanels() {
+	d.poll(d.intervals.Cluster, d.fetchCluster)
+	d.poll(d.intervals.Nodes, d.fetchNodes)
+	d.poll(d.intervals.Alerts, d.fetchAlerts)
+	d.poll(d.intervals.Volumes, d.fetchTopVolumes)
+	d.poll(d.intervals.Operations, d.fetchOperations)
+}
+
+// poll calls fetch every interval, or when a refresh is requested, and
+// sends the update it returns to the UI goroutine
+func (d *dashboard) poll(interval time.Duration, fetch func() func()) {
+	refresh := make(chan struct{}, 1)
+	d.refresh = append(d.refresh, refresh)
+	go func() {
+		for {
+			update := fetch()
+			select {
+			case d.updates <- update:
+			case <-d.stop:
+				return
+			}
+
+			timer := time.NewTimer(interval)
+			select {
+			case <-timer.C:
+			case <-refresh:
+				timer.Stop()
+			case <-d.stop:
+				timer.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (d *dashboard) fetchCluster() func() {
+	s, err := d.model.GetClusterSummary()
+	return func() {
+		if err != nil {
+			d.cluster.Text = fmt.Sprintf("Failed to get the cluster status: %v", err)
+			return
+		}
+		d.cluster.Text = fmt.Sprintf("Name:    %s\nStatus:  %s\nNodes:   %d (%d up)\nVolumes: %d",
+			s.Name, s.Status, s.Nodes, s.NodesUp, s.Volumes)
+		d.capacity.Percent = percent(s.Used, s.Total)
+		d.capacity.Label = fmt.Sprintf("%d%% (%s of %s)", d.capacity.Percent,
+			d.model.HumanizeBytes(s.Used), d.model.HumanizeBytes(s.Total))
+		d.nodesUp.Percent = percent(uint64(s.NodesUp), uint64(s.Nodes))
+		d.nodesUp.Label = fmt.Sprintf("%d of %d", s.NodesUp, s.Nodes)
+	}
+}
+
+func (d *dashboard) fetchNodes() func() {
+	t, err := d.model.GetNodes()
+	return func() {
+		setTable(d.nodes, t, err)
+	}
+}
+
+func (d *dashboard) fetchAlerts() func() {
+	alerts, err := d.model.GetAlerts()
+	return func() {
+		if err != nil {
+			d.alerts.Rows = []string{fmt.Sprintf("Failed to get alerts: %v", err)}
+			return
+		}
+		if len(alerts) == 0 {
+			alerts = []string{"No alerts"}
+		}
+		d.alerts.Rows = alerts
+	}
+}
+
+func (d *dashboard) fetchTopVolumes() func() {
+	byIops, byLatency, err := d.model.GetTopVolumes()
+	return func() {
+		setTable(d.topIops, byIops, err)
+		setTable(d.topLatency, byLatency, err)
+	}
+}
+
+func (d *dashboard) fetchOperations() func() {
+	t, err := d.model.GetOperations()
+	return func() {
+		if err == nil && len(t.Rows) == 0 {
+			t = &DashboardTable{Headers: []string{"No operations in progress"}}
+		}
+		setTable(d.operations, t, err)
+	}
+}
+
+// setTable shows the table, or the error when it could not be fetched
+func setTable(w *widgets.Table, t *DashboardTable, err error) {
+	if err != nil {
+		w.Rows = [][]string{{fmt.Sprintf("Error: %v", err)}}
+		return
+	}
+	w.Rows = append([][]string{t.Headers}, t.Rows...)
+}
+
+func percent(used, total uint64) int {
+	if total == 0 {
+		return 0
+	}
+	p := int(used * 100 / total)
+	if p > 100 {
+		return 100
+	}
+	return p
+}
+
+func newDashboardTable(title string) *widgets.Table {
+	t := widgets.NewTable()
+	t.Title = title
+	t.TextAlignment = ui.AlignLeft
+	t.RowSeparator = false
+	t.RowStyles[0] = ui.NewStyle(ui.ColorClear, ui.ColorClear, ui.ModifierBold)
+	t.Rows = [][]string{{"Loading..."}}
+	return t
+}
+
+func (d *dashboard) layoutComponents() {
+	d.termWidth, d.termHeight = ui.TerminalDimensions()
+
+	d.topLeft = widgets.NewParagraph()
+	d.topLeft.Border = false
+	d.topLeft.PaddingTop = -1
+	d.topLeft.PaddingRight = -1
+	d.topLeft.PaddingBottom = -1
+	d.topLeft.SetRect(0, 0, d.termWidth, TOP_LINE_HEIGHT)
+
+	d.cluster = widgets.NewParagraph()
+	d.cluster.Title = "Cluster"
+	d.cluster.Text = "Loading..."
+
+	d.capacity = widgets.NewGauge()
+	d.capacity.Title = "Capacity"
+	d.capacity.BarColor = ui.ColorBlue
+
+	d.nodesUp = widgets.NewGauge()
+	d.nodesUp.Title = "Nodes Up"
+	d.nodesUp.BarColor = ui.ColorGreen
+
+	d.nodes = newDashboardTable("Nodes")
+	d.alerts = widgets.NewList()
+	d.alerts.Title = "Alerts"
+	d.alerts.Rows = []string{"Loading..."}
+	d.topIops = newDashboardTable("Top Volumes by IOPS")
+	d.topLatency = newDashboardTable("Top Volumes by Latency")
+	d.operations = newDashboardTable("Operations in Progress")
+
+	d.grid = ui.NewGrid()
+	d.grid.SetRect(0, TOP_LINE_HEIGHT-1, d.termWidth, d.termHeight)
+	d.grid.Set(
+		ui.NewRow(0.2,
+			ui.NewCol(0.4, d.cluster),
+			ui.NewCol(0.3, d.capacity),
+			ui.NewCol(0.3, d.nodesUp),
+		),
+		ui.NewRow(0.4,
+			ui.NewCol(0.5, d.nodes),
+			ui.NewCol(0.5, d.alerts),
+		),
+		ui.NewRow(0.4,
+			ui.NewCol(1.0/3, d.topIops),
+			ui.NewCol(1.0/3, d.topLatency),
+			ui.NewCol(1.0/3, d.operations),
+		),
+	)
+}
+
+func (d *dashboard) render() {
+	s1 := "Portworx Dashboard (Press: q to quit; r to refresh)"
+	s2 := getCurrentDateTime()
+	s3 := "  "
+	if l := len(s1) + len(s2) + 2; l < d.termWidth {
+		s3 = strings.Repeat(" ", d.termWidth-l)
+	}
+	d.topLeft.Text = s1 + s3 + s2
+	ui.Render(d.topLeft, d.grid)
+}
+
+func (d *dashboard) resize(w int, h int) {
+	d.termWidth = w
+	d.termHeight = h
+	d.topLeft.SetRect(0, 0, w, TOP_LINE_HEIGHT)
+	d.grid.SetRect(0, TOP_LINE_HEIGHT-1, w, h)
+	ui.Clear()
+	d.render()
+}
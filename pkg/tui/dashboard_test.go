@@ -0,0 +1,57 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboardPoll(t *testing.T) {
+	d := NewDashboard(nil, DashboardIntervals{}).(*dashboard)
+	defer close(d.stop)
+
+	slow := make(chan struct{})
+	defer close(slow)
+	fast := 0
+	d.poll(time.Hour, func() func() {
+		<-slow
+		return func() {}
+	})
+	d.poll(time.Millisecond, func() func() {
+		return func() { fast++ }
+	})
+
+	// The fast panel is refreshed while the slow one is blocked
+	for i := 0; i < 3; i++ {
+		select {
+		case update := <-d.updates:
+			update()
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for an update")
+		}
+	}
+	assert.Equal(t, 3, fast)
+	assert.Len(t, d.refresh, 2)
+}
+
+func TestPercent(t *testing.T) {
+	assert.Equal(t, 0, percent(10, 0))
+	assert.Equal(t, 25, percent(25, 100))
+	assert.Equal(t, 100, percent(200, 100))
+}
@@ -50,6 +50,20 @@ type StatsModel interface {
 	Humanize(index int, val float64) (string, error)
 }
 
+// DetailModel is implemented by the models which can show the details of
+// a row when it is selected with Enter
+type DetailModel interface {
+	// GetDetail returns the details of the row to show in the detail pane
+	GetDetail(row []string) (string, error)
+}
+
+// PausableModel is implemented by the models which need to know when the
+// view is paused, like the models replaying a recording
+type PausableModel interface {
+	// SetPaused is called when the view is paused or resumed
+	SetPaused(paused bool)
+}
+
 type View interface {
 	Display(ti StatsModel, refreshInterval time.Duration) error
 }
@@ -63,11 +77,26 @@ type statsView struct {
 	grid       *ui.Grid
 	topLeft    *widgets.Paragraph
 	table      *widgets.Table
+	detail     *widgets.Paragraph
+	help       *widgets.Paragraph
 	gi         []*graphInfo
 	termWidth  int
 	termHeight int
 	curTitle   string
 	curTimeStr string
+
+	headers []string
+	// rows are all the rows of the model and visible the ones matching the filter
+	rows     [][]string
+	visible  [][]string
+	selected int
+	offset   int
+	filter   string
+	// prompt is the filter being typed. It is nil when not typing a filter.
+	prompt    *string
+	paused    bool
+	showHelp  bool
+	detailRow []string
 }
 
 const (
@@ -76,6 +105,19 @@ const (
 	MAX_GRAPH_POINTS  = 400
 )
 
+var helpText = `q, Ctrl-C           Quit
+?                   Show or hide this help
+/                   Filter the rows by name, * is a wildcard
+Esc                 Close the help or details, or clear the filter
+p, Space            Pause or resume the refresh
+r                   Refresh now
+j, k, Down, Up      Select the next or previous row
+PgDn, PgUp          Scroll down or up a page
+g, G, Home, End     Select the first or last row
+Enter               Show the details of the selected row
+s                   Toggle the sort order
+h, l                Sort on the previous or next column`
+
 func NewStatsView(numPlots int) View {
 	tv := &statsView{
 		gi: make([]*graphInfo, numPlots),
@@ -106,30 +148,17 @@ func (tv *statsView) Display(ti StatsModel, interval time.Duration) error {
 	for {
 		select {
 		case e := <-uiEvents:
-			switch e.ID {
-			case "s":
-				if err := tv.toggleSortOrder(ti); err != nil {
-					return err
-				}
-			case "h":
-				if err := tv.moveSortColumnPrev(ti); err != nil {
-					return err
-				}
-			case "l":
-				if err := tv.moveSortColumnNext(ti); err != nil {
-					return err
-				}
-			case "r":
-				if err := tv.render(ti); err != nil {
-					return err
-				}
-			case "q", "<C-c>":
+			quit, err := tv.handleEvent(ti, e)
+			if err != nil {
+				return err
+			}
+			if quit {
 				return nil
-			case "<Resize>":
-				payload := e.Payload.(ui.Resize)
-				tv.resize(payload.Width, payload.Height)
 			}
 		case <-ticker:
+			if tv.paused {
+				continue
+			}
 			if err := tv.render(ti); err != nil {
 				return err
 			}
@@ -137,6 +166,154 @@ func (tv *statsView) Display(ti StatsModel, interval time.Duration) error {
 	}
 }
 
+// handleEvent handles a key press or resize. It returns true to quit.
+func (tv *statsView) handleEvent(ti StatsModel, e ui.Event) (bool, error) {
+	if e.ID == "<Resize>" {
+		payload := e.Payload.(ui.Resize)
+		tv.resize(payload.Width, payload.Height)
+		return false, nil
+	}
+	if tv.prompt != nil {
+		tv.handlePromptKey(e.ID)
+		tv.draw()
+		return false, nil
+	}
+
+	switch e.ID {
+	case "s":
+		return false, tv.toggleSortOrder(ti)
+	case "h":
+		return false, tv.moveSortColumnPrev(ti)
+	case "l":
+		return false, tv.moveSortColumnNext(ti)
+	case "r":
+		return false, tv.render(ti)
+	case "q", "<C-c>":
+		return true, nil
+	case "?":
+		tv.showHelp = !tv.showHelp
+	case "<Escape>":
+		switch {
+		case tv.showHelp:
+			tv.showHelp = false
+		case tv.detailRow != nil:
+			tv.detailRow = nil
+		default:
+			tv.filter = ""
+		}
+	case "/":
+		p := tv.filter
+		tv.prompt = &p
+	case "p", "<Space>":
+		tv.paused = !tv.paused
+		if pm, ok := ti.(PausableModel); ok {
+			pm.SetPaused(tv.paused)
+		}
+	case "j", "<Down>":
+		tv.selected++
+	case "k", "<Up>":
+		tv.selected--
+	case "<PageDown>":
+		tv.selected += tv.pageSize()
+	case "<PageUp>":
+		tv.selected -= tv.pageSize()
+	case "g", "<Home>":
+		tv.selected = 0
+	case "G", "<End>":
+		tv.selected = len(tv.visible) - 1
+	case "<Enter>":
+		if len(tv.visible) != 0 {
+			tv.detailRow = tv.visible[tv.selected]
+			tv.updateDetail(ti)
+		}
+	default:
+		return false, nil
+	}
+	tv.draw()
+	return false, nil
+}
+
+// handlePromptKey edits the filter being typed. The rows are filtered
+// while typing.
+func (tv *statsView) handlePromptKey(id string) {
+	switch id {
+	case "<Enter>":
+		tv.prompt = nil
+		return
+	case "<Escape>":
+		tv.prompt = nil
+		tv.filter = ""
+		return
+	case "<Backspace>", "<C-<Backspace>>":
+		r := []rune(*tv.prompt)
+		if len(r) != 0 {
+			*tv.prompt = string(r[:len(r)-1])
+		}
+	case "<Space>":
+		*tv.prompt += " "
+	default:
+		if len([]rune(id)) != 1 {
+			return
+		}
+		*tv.prompt += id
+	}
+	tv.filter = *tv.prompt
+	tv.selected = 0
+}
+
+// matchFilter returns true if the name matches the filter. Filters with a
+// * are matched as globs, and the others as substrings.
+func matchFilter(filter, name string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	if strings.Contains(filter, "*") {
+		return util.MatchGlob(filter, name)
+	}
+	return strings.Contains(name, filter)
+}
+
+// filterRows returns the rows whose first column matches the filter
+func filterRows(rows [][]string, filter string) [][]string {
+	if len(filter) == 0 {
+		return rows
+	}
+	matched := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		if len(row) != 0 && matchFilter(filter, row[0]) {
+			matched = append(matched, row)
+		}
+	}
+	return matched
+}
+
+// scroll returns the selected row and the first row shown so that the
+// selected row is within the page
+func scroll(selected, offset, numRows, page int) (int, int) {
+	if selected >= numRows {
+		selected = numRows - 1
+	}
+	if selected < 0 {
+		selected = 0
+	}
+	if page < 1 {
+		page = 1
+	}
+	if selected < offset {
+		offset = selected
+	}
+	if selected >= offset+page {
+		offset = selected - page + 1
+	}
+	if offset > numRows-page {
+		offset = numRows - page
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return selected, offset
+}
+
 func getCurrentDateTime() string {
 	now := time.Now()
 	return now.Format(util.TimeFormat)
@@ -173,11 +350,17 @@ func (tv *statsView) getTable() *widgets.Table {
 	t.TextAlignment = ui.AlignLeft
 	t.Border = false
 	t.RowSeparator = false
-
-	t.RowStyles[0] = ui.NewStyle(ui.ColorClear, ui.ColorClear, ui.ModifierBold)
+	t.FillRow = true
 	return t
 }
 
+func (tv *statsView) getHelp() *widgets.Paragraph {
+	p := widgets.NewParagraph()
+	p.Title = "Keys (Press ? or Esc to close)"
+	p.Text = helpText
+	return p
+}
+
 func (tv *statsView) getGraphRows() []interface{} {
 	numGraphs := len(tv.gi)
 	cols := make([]interface{}, numGraphs)
@@ -201,6 +384,8 @@ func (tv *statsView) layoutComponents() {
 	tv.termWidth, tv.termHeight = ui.TerminalDimensions()
 	tv.topLeft = tv.getTopLeft(tv.termWidth)
 	tv.table = tv.getTable()
+	tv.detail = widgets.NewParagraph()
+	tv.help = tv.getHelp()
 	tv.grid = ui.NewGrid()
 	tv.grid.SetRect(0, TOP_LINE_HEIGHT-1,
 		tv.termWidth, tv.termHeight-TOP_LINE_HEIGHT)
@@ -220,34 +405,150 @@ func (tv *statsView) layoutComponents() {
 	)
 }
 
+// render refreshes the data of the model and draws the view. A nil model
+// only draws the view.
 func (tv *statsView) render(ti StatsModel) error {
-	s1 := tv.curTitle
-	s2 := tv.curTimeStr
-
 	if ti != nil {
 		err := ti.Refresh()
 		if err != nil {
 			return nil
 		}
+		selected := tv.selectedName()
 		if err := tv.fillTable(ti); err != nil {
 			return err
 		}
+		tv.selectName(selected)
 		if err := tv.fillGraphData(ti); err != nil {
 			return err
 		}
-		s1 = ti.GetTitle()
-		s2 = getCurrentDateTime()
-		tv.curTitle = s1
-		tv.curTimeStr = s2
+		if tv.detailRow != nil {
+			tv.updateDetail(ti)
+		}
+		tv.curTitle = ti.GetTitle()
+		tv.curTimeStr = getCurrentDateTime()
+	}
+	tv.draw()
+	return nil
+}
+
+// draw shows the rows matching the filter around the selected row, and
+// the detail pane and help when they are open
+func (tv *statsView) draw() {
+	tv.visible = filterRows(tv.rows, tv.filter)
+	tv.selected, tv.offset = scroll(tv.selected, tv.offset, len(tv.visible), tv.pageSize())
+	if tv.topLeft == nil {
+		// The view is not displayed
+		return
+	}
+
+	s1 := tv.curTitle
+	if tv.prompt != nil {
+		s1 = "Filter: /" + *tv.prompt
+	} else {
+		if len(tv.filter) != 0 {
+			s1 += fmt.Sprintf(" [filter: %s]", tv.filter)
+		}
+		if tv.paused {
+			s1 += " [paused]"
+		}
 	}
+	s2 := tv.curTimeStr
 	l := len(s1) + len(s2) + 2
 	s3 := "  "
 	if l < tv.termWidth {
 		s3 = strings.Repeat(" ", tv.termWidth-l)
 	}
 	tv.topLeft.Text = fmt.Sprintf("%s%s%s", s1, s3, s2)
-	ui.Render(tv.topLeft, tv.grid)
-	return nil
+
+	items := []ui.Drawable{tv.topLeft}
+	if len(tv.headers) != 0 {
+		end := tv.offset + tv.pageSize()
+		if end > len(tv.visible) {
+			end = len(tv.visible)
+		}
+
+		rows := make([][]string, 0, end-tv.offset+1)
+		rows = append(rows, tv.headers)
+		rows = append(rows, tv.visible[tv.offset:end]...)
+		tv.table.Rows = rows
+		tv.table.RowStyles = map[int]ui.Style{
+			0: ui.NewStyle(ui.ColorClear, ui.ColorClear, ui.ModifierBold),
+		}
+		if len(tv.visible) != 0 {
+			tv.table.RowStyles[tv.selected-tv.offset+1] =
+				ui.NewStyle(ui.ColorClear, ui.ColorClear, ui.ModifierReverse)
+		}
+		items = append(items, tv.grid)
+	}
+
+	if tv.detailRow != nil {
+		r := tv.table.GetRect()
+		tv.detail.SetRect(r.Min.X, r.Min.Y, r.Max.X, r.Max.Y)
+		items = append(items, tv.detail)
+	}
+	if tv.showHelp {
+		w, h := 72, strings.Count(helpText, "\n")+3
+		x, y := (tv.termWidth-w)/2, (tv.termHeight-h)/2
+		if x < 0 {
+			x = 0
+		}
+		if y < 0 {
+			y = 0
+		}
+		tv.help.SetRect(x, y, x+w, y+h)
+		items = append(items, tv.help)
+	}
+	ui.Render(items...)
+}
+
+// selectedName returns the first column of the selected row
+func (tv *statsView) selectedName() string {
+	if tv.selected < len(tv.visible) && len(tv.visible[tv.selected]) != 0 {
+		return tv.visible[tv.selected][0]
+	}
+	return ""
+}
+
+// selectName keeps the row with the name selected when the rows are
+// refreshed or sorted
+func (tv *statsView) selectName(name string) {
+	for i, row := range filterRows(tv.rows, tv.filter) {
+		if len(row) != 0 && row[0] == name {
+			tv.selected = i
+			return
+		}
+	}
+}
+
+// pageSize returns the number of rows shown in the table
+func (tv *statsView) pageSize() int {
+	h := 0
+	if tv.table != nil {
+		h = tv.table.Inner.Dy() - 1
+	}
+	if h < 1 {
+		// The table has not been drawn yet
+		h = tv.termHeight - TOP_LINE_HEIGHT - 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+// updateDetail gets the details of the row shown in the detail pane
+func (tv *statsView) updateDetail(ti StatsModel) {
+	tv.detail.Title = fmt.Sprintf("%s (Press Esc to close)", tv.detailRow[0])
+	dm, ok := ti.(DetailModel)
+	if !ok {
+		tv.detail.Text = "No details available"
+		return
+	}
+	text, err := dm.GetDetail(tv.detailRow)
+	if err != nil {
+		text = fmt.Sprintf("Failed to get details: %v", err)
+	}
+	tv.detail.Text = text
 }
 
 func (tv *statsView) resize(w int, h int) {
@@ -260,9 +561,8 @@ func (tv *statsView) resize(w int, h int) {
 }
 
 func (tv *statsView) fillTable(ti StatsModel) error {
-	headers := ti.GetHeaders()
+	tv.headers = ti.GetHeaders()
 	rows := make([][]string, 0)
-	rows = append(rows, headers)
 	for {
 		n, err := ti.NextRow()
 		if err != nil {
@@ -273,7 +573,7 @@ func (tv *statsView) fillTable(ti StatsModel) error {
 		}
 		rows = append(rows, n)
 	}
-	tv.table.Rows = rows
+	tv.rows = rows
 	return nil
 }
 
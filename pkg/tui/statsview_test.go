@@ -0,0 +1,142 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tui
+
+import (
+	"testing"
+
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchFilter(t *testing.T) {
+	assert.True(t, matchFilter("", "vol1"))
+	assert.True(t, matchFilter("ol", "vol1"))
+	assert.False(t, matchFilter("db", "vol1"))
+	assert.True(t, matchFilter("vol*", "vol1"))
+	assert.False(t, matchFilter("*vol", "vol1"))
+	assert.True(t, matchFilter("*1", "vol1"))
+
+	rows := [][]string{{"db-1", "a"}, {"web-1", "b"}, {"db-2", "c"}}
+	assert.Equal(t, rows, filterRows(rows, ""))
+	assert.Equal(t, [][]string{{"db-1", "a"}, {"db-2", "c"}}, filterRows(rows, "db*"))
+	assert.Len(t, filterRows(rows, "-1"), 2)
+}
+
+func TestScroll(t *testing.T) {
+	for _, test := range []struct {
+		selected, offset, numRows, page int
+		expSelected, expOffset          int
+	}{
+		{0, 0, 10, 5, 0, 0},
+		{4, 0, 10, 5, 4, 0},
+		{5, 0, 10, 5, 5, 1},
+		{2, 4, 10, 5, 2, 2},
+		{20, 0, 10, 5, 9, 5},
+		{-3, 0, 10, 5, 0, 0},
+		{3, 8, 10, 5, 3, 3},
+		{0, 0, 0, 5, 0, 0},
+		{2, 0, 3, 5, 2, 0},
+	} {
+		selected, offset := scroll(test.selected, test.offset, test.numRows, test.page)
+		assert.Equal(t, test.expSelected, selected, "%+v", test)
+		assert.Equal(t, test.expOffset, offset, "%+v", test)
+	}
+}
+
+type fakeModel struct {
+	rows    [][]string
+	next    int
+	paused  bool
+	details []string
+}
+
+func (f *fakeModel) Refresh() error                          { f.next = 0; return nil }
+func (f *fakeModel) GetTitle() string                        { return "Fake" }
+func (f *fakeModel) GetHeaders() []string                    { return []string{"Name"} }
+func (f *fakeModel) SetSortInfo(colName string, asc bool)    {}
+func (f *fakeModel) GetSortInfo() (string, bool)             { return "Name", true }
+func (f *fakeModel) MoveSortColumnNext()                     {}
+func (f *fakeModel) MoveSortColumnPrev()                     {}
+func (f *fakeModel) GetGraphTitle(index int) (string, error) { return "", nil }
+func (f *fakeModel) GetGraphData(index int) (float64, error) { return 0, nil }
+func (f *fakeModel) Humanize(index int, val float64) (string, error) {
+	return "", nil
+}
+func (f *fakeModel) SetPaused(paused bool) { f.paused = paused }
+func (f *fakeModel) GetDetail(row []string) (string, error) {
+	f.details = append(f.details, row[0])
+	return "details of " + row[0], nil
+}
+
+func (f *fakeModel) NextRow() ([]string, error) {
+	if f.next >= len(f.rows) {
+		return nil, nil
+	}
+	f.next++
+	return f.rows[f.next-1], nil
+}
+
+func key(tv *statsView, ti StatsModel, id string) bool {
+	quit, _ := tv.handleEvent(ti, ui.Event{ID: id})
+	return quit
+}
+
+func TestStatsViewKeys(t *testing.T) {
+	ti := &fakeModel{rows: [][]string{{"db-1"}, {"web-1"}, {"db-2"}}}
+	tv := &statsView{termHeight: 20, detail: widgets.NewParagraph()}
+	tv.render(ti)
+
+	// Filter while typing
+	key(tv, ti, "/")
+	key(tv, ti, "d")
+	key(tv, ti, "b")
+	assert.Equal(t, "db", tv.filter)
+	key(tv, ti, "<Backspace>")
+	key(tv, ti, "b")
+	key(tv, ti, "<Enter>")
+	assert.Nil(t, tv.prompt)
+	assert.Equal(t, "db", tv.filter)
+	assert.Len(t, tv.visible, 2)
+
+	// Drill down into the selected row
+	key(tv, ti, "j")
+	key(tv, ti, "j")
+	assert.Equal(t, 1, tv.selected)
+	key(tv, ti, "<Enter>")
+	assert.Equal(t, []string{"db-2"}, tv.detailRow)
+	assert.Equal(t, "details of db-2", tv.detail.Text)
+
+	// Escape closes the details, then clears the filter
+	key(tv, ti, "<Escape>")
+	assert.Nil(t, tv.detailRow)
+	key(tv, ti, "<Escape>")
+	assert.Empty(t, tv.filter)
+
+	key(tv, ti, "p")
+	assert.True(t, tv.paused)
+	assert.True(t, ti.paused)
+	key(tv, ti, "<Space>")
+	assert.False(t, ti.paused)
+
+	key(tv, ti, "?")
+	assert.True(t, tv.showHelp)
+	key(tv, ti, "<Escape>")
+	assert.False(t, tv.showHelp)
+
+	assert.True(t, key(tv, ti, "q"))
+}
@@ -16,6 +16,7 @@ limitations under the License.
 package util
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -60,3 +61,19 @@ func TestDefaultFormatOutput(t *testing.T) {
 	assert.Equal(t, newout.Cmd, out.Cmd, "yaml output not correct for Cmd")
 	assert.Equal(t, newout.Desc, out.Desc, "yaml output not correct for Desc")
 }
+
+func TestPrintFormattedWithHandler(t *testing.T) {
+	out := &DefaultFormatOutput{
+		Cmd:  "Create Test",
+		Desc: "Create Test successful",
+	}
+
+	var got []FormatOutput
+	ctx := WithFormatOutputHandler(context.Background(), func(in FormatOutput) error {
+		got = append(got, in)
+		return nil
+	})
+	err := PrintFormatted(ctx, out)
+	assert.NoError(t, err)
+	assert.Equal(t, []FormatOutput{out}, got)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const (
+	ColorRed    = "\033[31m"
+	ColorGreen  = "\033[32m"
+	ColorYellow = "\033[33m"
+	colorReset  = "\033[0m"
+)
+
+// FieldChange is the value of a field before and after a change
+type FieldChange struct {
+	Field   string
+	Current string
+	Desired string
+}
+
+// Changed returns true if the value of the field changes
+func (f FieldChange) Changed() bool {
+	return f.Current != f.Desired
+}
+
+// UseColor returns true if the output to Stdout can be colorized
+func UseColor() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	f, ok := Stdout.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Colorize returns s in the color provided if the output supports colors
+func Colorize(color, s string) string {
+	if !UseColor() {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// PrintChanges prints the fields as a diff. Fields which change are shown
+// with their current value prefixed by - and their new value prefixed by +.
+func PrintChanges(indent string, changes []FieldChange) {
+	for _, change := range changes {
+		if !change.Changed() {
+			Printf("  %s%s: %s\n", indent, change.Field, change.Current)
+			continue
+		}
+		Printf("%s\n", Colorize(ColorRed, "- "+indent+change.Field+": "+change.Current))
+		Printf("%s\n", Colorize(ColorGreen, "+ "+indent+change.Field+": "+change.Desired))
+	}
+}
+
+// Confirm asks the user the question and returns true if the answer is yes
+func Confirm(question string) (bool, error) {
+	Printf("%s [y/N]: ", question)
+	answer, err := bufio.NewReader(Stdin).ReadString('\n')
+	if err != nil && len(answer) == 0 {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// ConfirmVolumes asks the user to confirm an operation on count volumes,
+// unless yes is set. The answer cannot be read when the input of the command
+// was read from stdin, in which case the user is asked to use --yes.
+func ConfirmVolumes(question string, count int, yes bool) (bool, error) {
+	if yes {
+		return true, nil
+	}
+	ok, err := Confirm(question)
+	if err == io.EOF {
+		return false, fmt.Errorf("Unable to confirm the operation on %d volumes. Use --yes to confirm", count)
+	} else if err != nil {
+		return false, err
+	}
+	if !ok {
+		Printf("No volumes changed\n")
+	}
+	return ok, nil
+}
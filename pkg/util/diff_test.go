@@ -0,0 +1,87 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package util
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintChanges(t *testing.T) {
+	var b bytes.Buffer
+	original := Stdout
+	Stdout = &b
+	defer func() { Stdout = original }()
+
+	PrintChanges("", []FieldChange{
+		{Field: "replicas", Current: "1", Desired: "2"},
+		{Field: "sticky", Current: "false", Desired: "false"},
+	})
+
+	// Colors are only used on terminals
+	assert.Equal(t, "- replicas: 1\n+ replicas: 2\n  sticky: false\n", b.String())
+}
+
+func TestConfirm(t *testing.T) {
+	var b bytes.Buffer
+	originalOut, originalIn := Stdout, Stdin
+	Stdout = &b
+	defer func() { Stdout, Stdin = originalOut, originalIn }()
+
+	for answer, expected := range map[string]bool{
+		"y\n":   true,
+		"YES\n": true,
+		"n\n":   false,
+		"\n":    false,
+		"y":     true,
+	} {
+		Stdin = strings.NewReader(answer)
+		ok, err := Confirm("Continue?")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, ok, answer)
+	}
+	assert.Contains(t, b.String(), "Continue? [y/N]: ")
+
+	Stdin = strings.NewReader("")
+	_, err := Confirm("Continue?")
+	assert.Error(t, err)
+}
+
+func TestConfirmVolumes(t *testing.T) {
+	var b bytes.Buffer
+	originalOut, originalIn := Stdout, Stdin
+	Stdout = &b
+	defer func() { Stdout, Stdin = originalOut, originalIn }()
+
+	Stdin = strings.NewReader("")
+	ok, err := ConfirmVolumes("Delete 3 volumes?", 3, true)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Empty(t, b.String())
+
+	ok, err = ConfirmVolumes("Delete 3 volumes?", 3, false)
+	assert.False(t, ok)
+	assert.EqualError(t, err, "Unable to confirm the operation on 3 volumes. Use --yes to confirm")
+
+	Stdin = strings.NewReader("n\n")
+	ok, err = ConfirmVolumes("Delete 3 volumes?", 3, false)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, b.String(), "No volumes changed")
+}
@@ -16,22 +16,50 @@ limitations under the License.
 
 package util
 
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
 const (
 	FORMAT_WIDE = "wide"
 	FORMAT_JSON = "json"
 	FORMAT_YAML = "yaml"
+
+	// The following formats are created from the wide table
+	FORMAT_CSV      = "csv"
+	FORMAT_MARKDOWN = "markdown"
+
+	// The following formats are printed from the json representation
+	FORMAT_NAME           = "name"
+	FORMAT_CUSTOM_COLUMNS = "custom-columns"
+	FORMAT_JSONPATH       = "jsonpath"
+	FORMAT_GO_TEMPLATE    = "go-template"
 )
 
 // FormatOutput is the interface used to ensure proper formatting
 type FormatOutput interface {
 	// SetFormat takes in as input the type of Formatting needed.
-	// Currently recoganized values are "wide", "json" and "yaml".
+	// Currently recoganized values are "wide", "json", "yaml", "csv",
+	// "markdown", "name",
+	// "custom-columns=<spec>", "jsonpath=<template>" and
+	// "go-template=<template>".
 	// Any other string including "" will end up with DefaultFormat.
 	SetFormat(typeOfFormatting string)
 
 	// GetFormat returns the format type set
 	GetFormat() string
 
+	// SetNoHeaders sets if the headers of tables must not be printed
+	SetNoHeaders(noHeaders bool)
+
+	// GetNoHeaders returns true if the headers of tables must not be printed
+	GetNoHeaders() bool
+
 	// DefaultFormat formats the output as a regular string
 	// This is called when either "wide", "yaml" or "json" is not set
 	DefaultFormat() (string, error)
@@ -46,23 +74,71 @@ type FormatOutput interface {
 	JsonFormat() (string, error)
 }
 
-// GetFormattedOutput returns the formatted output
+// GetFormattedOutput returns the formatted output. The name,
+// custom-columns, jsonpath and go-template formats are created from the
+// JsonFormat of the object, and the csv and markdown formats from the
+// TableFormat of the object.
 func GetFormattedOutput(in FormatOutput) (string, error) {
-	switch in.GetFormat() {
-	case FORMAT_YAML:
+	format := in.GetFormat()
+	switch {
+	case format == FORMAT_YAML:
 		return in.YamlFormat()
-	case FORMAT_JSON:
+	case format == FORMAT_JSON:
 		return in.JsonFormat()
-	case FORMAT_WIDE:
-		return in.WideFormat()
-	default:
-		return in.DefaultFormat()
+	case isPrinterFormat(format):
+		str, err := in.JsonFormat()
+		if err != nil {
+			return "", err
+		}
+		return printFormat(format, str, in.GetNoHeaders())
+	case format == FORMAT_CSV || format == FORMAT_MARKDOWN:
+		tf, ok := in.(TableFormatOutput)
+		if !ok {
+			return "", fmt.Errorf("Output %s is not supported by this command", format)
+		}
+		table, err := tf.TableFormat()
+		if err != nil || table == nil {
+			return "", err
+		}
+		if format == FORMAT_CSV {
+			return table.Csv(in.GetNoHeaders())
+		}
+		return table.Markdown(), nil
+	}
+
+	var (
+		str string
+		err error
+	)
+	if format == FORMAT_WIDE {
+		str, err = in.WideFormat()
+	} else {
+		str, err = in.DefaultFormat()
 	}
+	if err != nil || !in.GetNoHeaders() {
+		return str, err
+	}
+	return removeTableHeader(str), nil
 }
 
-// Print the formatted output to stdout
-// In case of any  error, just return the error
-func PrintFormatted(in FormatOutput) error {
+// formatOutputHandlerKey is the key of the format output handler in a
+// context.Context
+type formatOutputHandlerKey struct{}
+
+// WithFormatOutputHandler returns a copy of ctx with a function which
+// receives the objects given to PrintFormatted instead of printing them
+func WithFormatOutputHandler(ctx context.Context, handler func(FormatOutput) error) context.Context {
+	return context.WithValue(ctx, formatOutputHandlerKey{}, handler)
+}
+
+// Print the formatted output to stdout, or give it to the handler in ctx
+// if any. In case of any  error, just return the error
+func PrintFormatted(ctx context.Context, in FormatOutput) error {
+	if ctx != nil {
+		if handler, ok := ctx.Value(formatOutputHandlerKey{}).(func(FormatOutput) error); ok {
+			return handler(in)
+		}
+	}
 	str, err := GetFormattedOutput(in)
 	if err != nil {
 		return err
@@ -73,6 +149,9 @@ func PrintFormatted(in FormatOutput) error {
 
 type BaseFormatOutput struct {
 	FormatType string
+	NoHeaders  bool
+	// RawUnits shows sizes in bytes instead of humanized
+	RawUnits bool
 }
 
 // SetFormat takes in as input the type of Formatting needed.
@@ -87,6 +166,24 @@ func (bfo *BaseFormatOutput) GetFormat() string {
 	return bfo.FormatType
 }
 
+// SetNoHeaders sets if the headers of tables must not be printed
+func (bfo *BaseFormatOutput) SetNoHeaders(noHeaders bool) {
+	bfo.NoHeaders = noHeaders
+}
+
+// GetNoHeaders returns true if the headers of tables must not be printed
+func (bfo *BaseFormatOutput) GetNoHeaders() bool {
+	return bfo.NoHeaders
+}
+
+// FormatBytes returns the size humanized, or in bytes if RawUnits is set
+func (bfo *BaseFormatOutput) FormatBytes(size uint64) string {
+	if bfo.RawUnits {
+		return strconv.FormatUint(size, 10)
+	}
+	return humanize.BigIBytes(new(big.Int).SetUint64(size))
+}
+
 // DefaultFormat just returns the Desc
 func (bfo *BaseFormatOutput) DefaultFormat() (string, error) {
 	return "", nil
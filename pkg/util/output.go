@@ -32,6 +32,8 @@ var (
 	Stdout io.Writer = os.Stdout
 	// Stderr points to the output buffer to send errors to the screen
 	Stderr io.Writer = os.Stderr
+	// Stdin points to the input buffer to read user input from
+	Stdin io.Reader = os.Stdin
 )
 
 // Printf is just like fmt.Printf except that it send the output to Stdout. It
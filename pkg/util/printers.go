@@ -0,0 +1,292 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// nameFields are the fields, in order, used to find the name of an object
+// for the "name" output
+var nameFields = []string{
+	"{.locator.name}",
+	"{.metadata.name}",
+	"{.hostname}",
+	"{.kubernetes.context}",
+	"{.id}",
+}
+
+// isPrinterFormat returns true if the format is printed from the json
+// representation of the object instead of by the formatter
+func isPrinterFormat(format string) bool {
+	kind := strings.SplitN(format, "=", 2)[0]
+	switch kind {
+	case FORMAT_NAME, FORMAT_CUSTOM_COLUMNS, FORMAT_JSONPATH, FORMAT_GO_TEMPLATE:
+		return true
+	}
+	return false
+}
+
+// printFormat prints the object in the json string using the format, which
+// is name, custom-columns=<spec>, jsonpath=<template> or
+// go-template=<template>. Lists are available to templates as .items like in
+// kubectl. Fields can be named as in the json output, like .spec.ha_level,
+// or in camel case, like .spec.haLevel.
+func printFormat(format, jsonStr string, noHeaders bool) (string, error) {
+	obj, err := decodeObject(jsonStr)
+	if err != nil {
+		return "", err
+	}
+
+	kind, arg := format, ""
+	if i := strings.Index(format, "="); i != -1 {
+		kind, arg = format[:i], format[i+1:]
+	}
+	if kind != FORMAT_NAME && len(arg) == 0 {
+		return "", fmt.Errorf("Missing template for output %s. Use -o %s=<template>", kind, kind)
+	}
+
+	switch kind {
+	case FORMAT_NAME:
+		return printNames(obj)
+	case FORMAT_CUSTOM_COLUMNS:
+		return printCustomColumns(arg, obj, noHeaders)
+	case FORMAT_JSONPATH:
+		j := jsonpath.New("output").AllowMissingKeys(true)
+		if err := j.Parse(arg); err != nil {
+			return "", fmt.Errorf("Invalid jsonpath template %s: %v", arg, err)
+		}
+		var b bytes.Buffer
+		if err := j.Execute(&b, templateData(obj)); err != nil {
+			return "", err
+		}
+		return strings.TrimRight(b.String(), "\n"), nil
+	case FORMAT_GO_TEMPLATE:
+		t, err := template.New("output").Parse(arg)
+		if err != nil {
+			return "", fmt.Errorf("Invalid go-template %s: %v", arg, err)
+		}
+		var b bytes.Buffer
+		if err := t.Execute(&b, templateData(obj)); err != nil {
+			return "", err
+		}
+		return strings.TrimRight(b.String(), "\n"), nil
+	}
+	return "", fmt.Errorf("Unknown output format %s", format)
+}
+
+// decodeObject decodes the json and adds a camel case alias to the keys in
+// snake case. Integers are decoded as int64 so that sizes are not printed
+// in scientific notation.
+func decodeObject(jsonStr string) (interface{}, error) {
+	if len(strings.TrimSpace(jsonStr)) == 0 {
+		return []interface{}{}, nil
+	}
+	d := json.NewDecoder(strings.NewReader(jsonStr))
+	d.UseNumber()
+	var obj interface{}
+	if err := d.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("Failed to decode the output: %v", err)
+	}
+	return normalize(obj), nil
+}
+
+func normalize(obj interface{}) interface{} {
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(o))
+		for k := range o {
+			keys = append(keys, k)
+		}
+		for _, k := range keys {
+			o[k] = normalize(o[k])
+			if camel := snakeToCamel(k); camel != k {
+				if _, ok := o[camel]; !ok {
+					o[camel] = o[k]
+				}
+			}
+		}
+	case []interface{}:
+		for i := range o {
+			o[i] = normalize(o[i])
+		}
+	case json.Number:
+		if i, err := o.Int64(); err == nil {
+			return i
+		}
+		if f, err := o.Float64(); err == nil {
+			return f
+		}
+	}
+	return obj
+}
+
+// snakeToCamel changes names like ha_level to haLevel
+func snakeToCamel(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) != 0 {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// items returns the objects in a list, or the object itself
+func items(obj interface{}) []interface{} {
+	if list, ok := obj.([]interface{}); ok {
+		return list
+	}
+	return []interface{}{obj}
+}
+
+// templateData returns lists as an object with items
+func templateData(obj interface{}) interface{} {
+	if list, ok := obj.([]interface{}); ok {
+		return map[string]interface{}{"items": list}
+	}
+	return obj
+}
+
+// relaxedPath allows paths without braces, like .locator.name
+func relaxedPath(path string) string {
+	path = strings.TrimSpace(path)
+	if strings.HasPrefix(path, "{") {
+		return path
+	}
+	if !strings.HasPrefix(path, ".") && !strings.HasPrefix(path, "[") {
+		path = "." + path
+	}
+	return "{" + path + "}"
+}
+
+// evalPath returns the values found at the path separated by commas. The
+// elements of lists are also separated by commas.
+func evalPath(j *jsonpath.JSONPath, obj interface{}) (string, error) {
+	results, err := j.FindResults(obj)
+	if err != nil {
+		return "", err
+	}
+	values := make([]string, 0)
+	for _, r := range results {
+		for _, v := range r {
+			if !v.IsValid() || !v.CanInterface() || v.Interface() == nil {
+				continue
+			}
+			if list, ok := v.Interface().([]interface{}); ok {
+				for _, e := range list {
+					values = append(values, fmt.Sprintf("%v", e))
+				}
+			} else {
+				values = append(values, fmt.Sprintf("%v", v.Interface()))
+			}
+		}
+	}
+	return strings.Join(values, ","), nil
+}
+
+func printNames(obj interface{}) (string, error) {
+	paths := make([]*jsonpath.JSONPath, 0, len(nameFields))
+	for _, f := range nameFields {
+		j := jsonpath.New("name").AllowMissingKeys(true)
+		if err := j.Parse(f); err != nil {
+			return "", err
+		}
+		paths = append(paths, j)
+	}
+
+	names := make([]string, 0)
+	for _, item := range items(obj) {
+		for _, j := range paths {
+			name, err := evalPath(j, item)
+			if err != nil {
+				return "", err
+			}
+			if len(name) != 0 {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return strings.Join(names, "\n"), nil
+}
+
+// printCustomColumns prints the columns in the spec, which is a comma
+// separated list of <header>:<path>
+func printCustomColumns(spec string, obj interface{}, noHeaders bool) (string, error) {
+	headers := make([]string, 0)
+	paths := make([]*jsonpath.JSONPath, 0)
+	for _, column := range strings.Split(spec, ",") {
+		parts := strings.SplitN(column, ":", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return "", fmt.Errorf("Invalid custom column %s. Columns must be in the format <header>:<path>", column)
+		}
+		j := jsonpath.New(parts[0]).AllowMissingKeys(true)
+		if err := j.Parse(relaxedPath(parts[1])); err != nil {
+			return "", fmt.Errorf("Invalid path %s for column %s: %v", parts[1], parts[0], err)
+		}
+		headers = append(headers, parts[0])
+		paths = append(paths, j)
+	}
+
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0)
+	if !noHeaders {
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+	}
+	for _, item := range items(obj) {
+		values := make([]string, len(paths))
+		for i, j := range paths {
+			v, err := evalPath(j, item)
+			if err != nil {
+				return "", err
+			}
+			if len(v) == 0 {
+				v = "<none>"
+			}
+			values[i] = v
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	w.Flush()
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// removeTableHeader removes the header and separator of a table printed by
+// tabby. Output which is not a table is returned unchanged.
+func removeTableHeader(s string) string {
+	lines := strings.SplitN(s, "\n", 3)
+	if len(lines) < 2 || !isTableSeparator(lines[1]) {
+		return s
+	}
+	if len(lines) < 3 {
+		return ""
+	}
+	return lines[2]
+}
+
+// isTableSeparator returns true if the line is the separator printed by tabby
+// under the header, made of dashes
+func isTableSeparator(line string) bool {
+	return strings.Contains(line, "-") && len(strings.Trim(line, "- ")) == 0
+}
@@ -0,0 +1,182 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package util
+
+import (
+	"testing"
+
+	api "github.com/libopenstorage/openstorage-sdk-clients/sdk/golang"
+	"github.com/stretchr/testify/assert"
+)
+
+type testVolumesFormatter struct {
+	BaseFormatOutput
+	vols []*api.Volume
+}
+
+func (f *testVolumesFormatter) JsonFormat() (string, error) {
+	return ToJson(f.vols)
+}
+
+func (f *testVolumesFormatter) DefaultFormat() (string, error) {
+	table, err := f.TableFormat()
+	if err != nil {
+		return "", err
+	}
+	return table.Tabbed(), nil
+}
+
+func (f *testVolumesFormatter) TableFormat() (*Table, error) {
+	t := &Table{}
+	t.AddHeader("Name", "HA")
+	for _, v := range f.vols {
+		t.AddLine(v.GetLocator().GetName(), v.GetSpec().GetHaLevel())
+	}
+	return t, nil
+}
+
+func (f *testVolumesFormatter) WideFormat() (string, error) {
+	return f.DefaultFormat()
+}
+
+func newTestVolumesFormatter(format string) *testVolumesFormatter {
+	f := &testVolumesFormatter{
+		vols: []*api.Volume{
+			{
+				Id:      "1",
+				Locator: &api.VolumeLocator{Name: "vol1"},
+				Spec:    &api.VolumeSpec{HaLevel: 3, Size: 1 << 40},
+			},
+			{
+				Id:      "2",
+				Locator: &api.VolumeLocator{Name: "vol2"},
+				Spec:    &api.VolumeSpec{HaLevel: 1},
+			},
+		},
+	}
+	f.SetFormat(format)
+	return f
+}
+
+func TestCustomColumnsOutput(t *testing.T) {
+	f := newTestVolumesFormatter("custom-columns=NAME:.locator.name,HA:.spec.haLevel,SIZE:spec.size,GROUP:.group.id")
+	out, err := GetFormattedOutput(f)
+	assert.NoError(t, err)
+	assert.Equal(t, ""+
+		"NAME   HA   SIZE            GROUP\n"+
+		"vol1   3    1099511627776   <none>\n"+
+		"vol2   1    <none>          <none>", out)
+
+	f.SetNoHeaders(true)
+	out, err = GetFormattedOutput(f)
+	assert.NoError(t, err)
+	assert.Equal(t, ""+
+		"vol1   3   1099511627776   <none>\n"+
+		"vol2   1   <none>          <none>", out)
+
+	f.SetFormat("custom-columns=NAME")
+	_, err = GetFormattedOutput(f)
+	assert.Error(t, err)
+
+	f.SetFormat("custom-columns=")
+	_, err = GetFormattedOutput(f)
+	assert.Error(t, err)
+}
+
+func TestJsonPathOutput(t *testing.T) {
+	f := newTestVolumesFormatter(`jsonpath={range .items[*]}{.locator.name}={.spec.ha_level}{"\n"}{end}`)
+	out, err := GetFormattedOutput(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "vol1=3\nvol2=1", out)
+
+	f.SetFormat(`jsonpath={.items[?(@.spec.haLevel==3)].id}`)
+	out, err = GetFormattedOutput(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", out)
+
+	f.SetFormat(`jsonpath={.items[`)
+	_, err = GetFormattedOutput(f)
+	assert.Error(t, err)
+}
+
+func TestGoTemplateOutput(t *testing.T) {
+	f := newTestVolumesFormatter(`go-template={{range .items}}{{.id}} {{.locator.name}}{{"\n"}}{{end}}`)
+	out, err := GetFormattedOutput(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "1 vol1\n2 vol2", out)
+
+	f.SetFormat(`go-template={{.id`)
+	_, err = GetFormattedOutput(f)
+	assert.Error(t, err)
+}
+
+func TestNameOutput(t *testing.T) {
+	f := newTestVolumesFormatter("name")
+	out, err := GetFormattedOutput(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "vol1\nvol2", out)
+
+	d := &DefaultFormatOutput{Id: []string{"id1"}}
+	d.SetFormat("name")
+	out, err = GetFormattedOutput(d)
+	assert.NoError(t, err)
+	assert.Equal(t, "id1", out)
+}
+
+func TestNoHeaders(t *testing.T) {
+	f := newTestVolumesFormatter("")
+	f.SetNoHeaders(true)
+	out, err := GetFormattedOutput(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "vol1  3\nvol2  1\n", out)
+
+	// Output which is not a table is not changed
+	d := &DefaultFormatOutput{Desc: "Volume created"}
+	d.SetNoHeaders(true)
+	out, err = GetFormattedOutput(d)
+	assert.NoError(t, err)
+	assert.Equal(t, "Volume created", out)
+}
+
+func TestCsvAndMarkdownOutput(t *testing.T) {
+	f := newTestVolumesFormatter("csv")
+	out, err := GetFormattedOutput(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "Name,HA\nvol1,3\nvol2,1", out)
+
+	f.SetNoHeaders(true)
+	out, err = GetFormattedOutput(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "vol1,3\nvol2,1", out)
+
+	f.SetFormat("markdown")
+	out, err = GetFormattedOutput(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "| Name | HA |\n| --- | --- |\n| vol1 | 3 |\n| vol2 | 1 |", out)
+
+	// Output which is not a table cannot be converted
+	d := &DefaultFormatOutput{Desc: "Volume created"}
+	d.SetFormat("csv")
+	_, err = GetFormattedOutput(d)
+	assert.Error(t, err)
+}
+
+func TestFormatBytes(t *testing.T) {
+	f := &BaseFormatOutput{}
+	assert.Equal(t, "1.0 GiB", f.FormatBytes(1<<30))
+	f.RawUnits = true
+	assert.Equal(t, "1073741824", f.FormatBytes(1<<30))
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package util
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cheynewallace/tabby"
+)
+
+// Table is the table printed by a formatter. The default and wide formats
+// print it with tabby, and the csv and markdown formats are created from it.
+type Table struct {
+	Headers []string
+	Rows    [][]string
+}
+
+// TableFormatOutput is implemented by the formatters which print a table
+type TableFormatOutput interface {
+	// TableFormat returns the table of the wide format, or nil if there
+	// is nothing to print
+	TableFormat() (*Table, error)
+}
+
+// AddHeader sets the headers of the table
+func (t *Table) AddHeader(columns ...interface{}) {
+	t.Headers = toStrings(columns)
+}
+
+// AddLine adds a row to the table
+func (t *Table) AddLine(columns ...interface{}) {
+	t.Rows = append(t.Rows, toStrings(columns))
+}
+
+func toStrings(columns []interface{}) []string {
+	s := make([]string, len(columns))
+	for i, c := range columns {
+		s[i] = fmt.Sprint(c)
+	}
+	return s
+}
+
+// Tabbed returns the table printed by tabby
+func (t *Table) Tabbed() string {
+	var b bytes.Buffer
+	tb := tabby.NewCustom(tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0))
+	tb.AddHeader(toInterfaces(t.Headers)...)
+	for _, row := range t.Rows {
+		tb.AddLine(toInterfaces(row)...)
+	}
+	tb.Print()
+	return b.String()
+}
+
+func toInterfaces(columns []string) []interface{} {
+	s := make([]interface{}, len(columns))
+	for i, c := range columns {
+		s[i] = c
+	}
+	return s
+}
+
+// Csv returns the table as csv
+func (t *Table) Csv(noHeaders bool) (string, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if !noHeaders {
+		w.Write(t.Headers)
+	}
+	w.WriteAll(t.Rows)
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// Markdown returns the table in markdown. The headers are always printed
+// since a markdown table requires them.
+func (t *Table) Markdown() string {
+	var b bytes.Buffer
+	writeMarkdownRow(&b, t.Headers)
+	separator := make([]string, len(t.Headers))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeMarkdownRow(&b, separator)
+	for _, row := range t.Rows {
+		writeMarkdownRow(&b, row)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func writeMarkdownRow(b *bytes.Buffer, cells []string) {
+	b.WriteString("|")
+	for _, c := range cells {
+		b.WriteString(" " + escapeMarkdown(c) + " |")
+	}
+	b.WriteString("\n")
+}
+
+// escapeMarkdown escapes the characters which would break a table cell
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		"|", `\|`,
+	).Replace(s)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package util
+
+import (
+	"bytes"
+	"testing"
+	"text/tabwriter"
+
+	"github.com/cheynewallace/tabby"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableTabbed(t *testing.T) {
+	var b bytes.Buffer
+	tb := tabby.NewCustom(tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0))
+	tb.AddHeader("NAME", "SIZE", "HA")
+	tb.AddLine("vol1", "1 GiB", 3)
+	tb.AddLine("a-longer-volume-name", "", 1)
+	tb.Print()
+
+	table := &Table{}
+	table.AddHeader("NAME", "SIZE", "HA")
+	table.AddLine("vol1", "1 GiB", 3)
+	table.AddLine("a-longer-volume-name", "", 1)
+	assert.Equal(t, []string{"NAME", "SIZE", "HA"}, table.Headers)
+	assert.Equal(t, [][]string{
+		{"vol1", "1 GiB", "3"},
+		{"a-longer-volume-name", "", "1"},
+	}, table.Rows)
+	assert.Equal(t, b.String(), table.Tabbed())
+}
+
+func TestTableCsvAndMarkdown(t *testing.T) {
+	table := &Table{
+		Headers: []string{"NAME", "LABELS"},
+		Rows: [][]string{
+			{"vol1", `app=db,team="a"`},
+			{"vol|2", `c:\data`},
+			{"données", "équipe=a"},
+		},
+	}
+
+	out, err := table.Csv(false)
+	assert.NoError(t, err)
+	assert.Equal(t, "NAME,LABELS\nvol1,\"app=db,team=\"\"a\"\"\"\nvol|2,c:\\data\ndonnées,équipe=a", out)
+
+	assert.Equal(t, ""+
+		"| NAME | LABELS |\n"+
+		"| --- | --- |\n"+
+		"| vol1 | app=db,team=\"a\" |\n"+
+		"| vol\\|2 | c:\\\\data |\n"+
+		"| données | équipe=a |", table.Markdown())
+}
@@ -16,10 +16,13 @@ limitations under the License.
 package util
 
 import (
-	"fmt"
+	"errors"
 	"time"
 )
 
+// ErrWaitTimeout is returned by WaitFor when the timeout expires
+var ErrWaitTimeout = errors.New("Timed out")
+
 // WaitFor() waits until f() returns false or err != nil
 // f() returns <wait as bool, or err>.
 func WaitFor(timeout time.Duration, period time.Duration, f func() (bool, error)) error {
@@ -31,13 +34,15 @@ func WaitFor(timeout time.Duration, period time.Duration, f func() (bool, error)
 	for wait {
 		select {
 		case <-timeoutChan:
-			return fmt.Errorf("Timed out")
+			return ErrWaitTimeout
 		default:
 			wait, err = f()
 			if err != nil {
 				return err
 			}
-			time.Sleep(period)
+			if wait {
+				time.Sleep(period)
+			}
 		}
 	}
 
@@ -0,0 +1,67 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package util
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrNotStarted is returned by ForEach for the calls which were not started
+// because a previous call failed
+var ErrNotStarted = errors.New("Not started")
+
+// ForEach calls f for each index from 0 to n-1 using up to workers goroutines
+// and returns the error of each call in the order of the indexes. When
+// continueOnError is false, no more calls are started once a call fails.
+func ForEach(n, workers int, continueOnError bool, f func(i int) error) []error {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	var (
+		errs    = make([]error, n)
+		failed  int32
+		wg      sync.WaitGroup
+		indexes = make(chan int)
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if !continueOnError && atomic.LoadInt32(&failed) != 0 {
+					errs[i] = ErrNotStarted
+					continue
+				}
+				if errs[i] = f(i); errs[i] != nil {
+					atomic.StoreInt32(&failed, 1)
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return errs
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © 2020 Portworx
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package util
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEach(t *testing.T) {
+	var (
+		calls   int32
+		running int32
+		maxRun  int32
+	)
+	errs := ForEach(20, 3, false, func(i int) error {
+		atomic.AddInt32(&calls, 1)
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRun)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRun, m, n) {
+				break
+			}
+		}
+		return nil
+	})
+	assert.Len(t, errs, 20)
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(20), calls)
+	assert.True(t, maxRun <= 3)
+
+	// No workers means one
+	errs = ForEach(2, 0, false, func(i int) error { return nil })
+	assert.Len(t, errs, 2)
+
+	assert.Len(t, ForEach(0, 4, false, func(i int) error { return nil }), 0)
+}
+
+func TestForEachErrors(t *testing.T) {
+	fail := func(i int) error {
+		if i%2 == 1 {
+			return fmt.Errorf("failed %d", i)
+		}
+		return nil
+	}
+
+	errs := ForEach(6, 2, true, fail)
+	for i, err := range errs {
+		if i%2 == 1 {
+			assert.EqualError(t, err, fmt.Sprintf("failed %d", i))
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+
+	// With a single worker the calls are in order, so all the calls after
+	// the first failure are not started
+	errs = ForEach(6, 1, false, fail)
+	assert.NoError(t, errs[0])
+	assert.EqualError(t, errs[1], "failed 1")
+	for _, err := range errs[2:] {
+		assert.Equal(t, ErrNotStarted, err)
+	}
+}